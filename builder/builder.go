@@ -0,0 +1,504 @@
+// Package builder assembles static STAC catalogs in memory and saves them to disk.
+//
+// A tree of catalogs, collections, and items is built with NewCatalog (or
+// NewCollection) and the Add methods on the resulting nodes.  When the tree is
+// normalized, each resource is assigned an href based on a Layout and the
+// structural links (root, parent, child, item, collection, and self) are
+// generated.  Other links are left as they are.
+package builder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/internal/normurl"
+)
+
+const (
+	mediaTypeJSON    = "application/json"
+	mediaTypeGeoJSON = "application/geo+json"
+)
+
+// CatalogType determines how links are written when a catalog is saved.
+type CatalogType string
+
+const (
+	// SelfContained catalogs have only relative links and no self links.
+	SelfContained CatalogType = "self-contained"
+
+	// RelativePublished catalogs have relative links and an absolute self link
+	// on the root only.
+	RelativePublished CatalogType = "relative-published"
+
+	// AbsolutePublished catalogs have absolute links and self links on every
+	// resource.
+	AbsolutePublished CatalogType = "absolute-published"
+)
+
+// structuralRels are the link relations generated by the builder.  Existing
+// links with these relations are replaced when the tree is normalized.
+var structuralRels = map[string]bool{
+	"root":       true,
+	"self":       true,
+	"parent":     true,
+	"child":      true,
+	"item":       true,
+	"collection": true,
+}
+
+// Node is a catalog, collection, or item in the tree.
+type Node struct {
+	catalog    *stac.Catalog
+	collection *stac.Collection
+	item       *stac.Item
+	parent     *Node
+	children   []*Node
+	items      []*Node
+	href       string
+}
+
+// NewCatalog creates a new node for a catalog.
+func NewCatalog(catalog *stac.Catalog) *Node {
+	return &Node{catalog: catalog}
+}
+
+// NewCollection creates a new node for a collection.
+func NewCollection(collection *stac.Collection) *Node {
+	return &Node{collection: collection}
+}
+
+// Catalog returns the node's catalog (or nil if the node is not a catalog).
+func (n *Node) Catalog() *stac.Catalog {
+	return n.catalog
+}
+
+// Collection returns the node's collection (or nil if the node is not a collection).
+func (n *Node) Collection() *stac.Collection {
+	return n.collection
+}
+
+// Item returns the node's item (or nil if the node is not an item).
+func (n *Node) Item() *stac.Item {
+	return n.item
+}
+
+// Id returns the identifier of the node's resource.
+func (n *Node) Id() string {
+	switch {
+	case n.catalog != nil:
+		return n.catalog.Id
+	case n.collection != nil:
+		return n.collection.Id
+	case n.item != nil:
+		return n.item.Id
+	}
+	return ""
+}
+
+// Parent returns the parent node (or nil for the root).
+func (n *Node) Parent() *Node {
+	return n.parent
+}
+
+// Children returns the child catalogs and collections.
+func (n *Node) Children() []*Node {
+	return n.children
+}
+
+// Items returns the child items.
+func (n *Node) Items() []*Node {
+	return n.items
+}
+
+// Href returns the path of the resource relative to the root directory.  This
+// is empty until the tree has been normalized.
+func (n *Node) Href() string {
+	return n.href
+}
+
+// AddCatalog adds a child catalog.
+func (n *Node) AddCatalog(catalog *stac.Catalog) (*Node, error) {
+	return n.addChild(&Node{catalog: catalog})
+}
+
+// AddCollection adds a child collection.
+func (n *Node) AddCollection(collection *stac.Collection) (*Node, error) {
+	return n.addChild(&Node{collection: collection})
+}
+
+// AddItem adds an item.
+func (n *Node) AddItem(item *stac.Item) (*Node, error) {
+	if n.item != nil {
+		return nil, fmt.Errorf("cannot add item %q to item %q", item.Id, n.Id())
+	}
+	child := &Node{item: item, parent: n}
+	if err := n.checkId(child); err != nil {
+		return nil, err
+	}
+	n.items = append(n.items, child)
+	return child, nil
+}
+
+func (n *Node) addChild(child *Node) (*Node, error) {
+	if n.item != nil {
+		return nil, fmt.Errorf("cannot add %q to item %q", child.Id(), n.Id())
+	}
+	if err := n.checkId(child); err != nil {
+		return nil, err
+	}
+	child.parent = n
+	n.children = append(n.children, child)
+	return child, nil
+}
+
+func (n *Node) checkId(child *Node) error {
+	id := child.Id()
+	if id == "" {
+		return errors.New("missing id")
+	}
+	for _, existing := range n.children {
+		if existing.Id() == id {
+			return fmt.Errorf("%q already has a child with id %q", n.Id(), id)
+		}
+	}
+	for _, existing := range n.items {
+		if existing.Id() == id {
+			return fmt.Errorf("%q already has an item with id %q", n.Id(), id)
+		}
+	}
+	return nil
+}
+
+// nearestCollection returns the closest collection node (including the node itself).
+func (n *Node) nearestCollection() *Node {
+	for node := n; node != nil; node = node.parent {
+		if node.collection != nil {
+			return node
+		}
+	}
+	return nil
+}
+
+func (n *Node) title() string {
+	switch {
+	case n.catalog != nil:
+		return n.catalog.Title
+	case n.collection != nil:
+		return n.collection.Title
+	}
+	return ""
+}
+
+func (n *Node) mediaType() string {
+	if n.item != nil {
+		return mediaTypeGeoJSON
+	}
+	return mediaTypeJSON
+}
+
+func (n *Node) links() *[]*stac.Link {
+	switch {
+	case n.catalog != nil:
+		return &n.catalog.Links
+	case n.collection != nil:
+		return &n.collection.Links
+	}
+	return &n.item.Links
+}
+
+func (n *Node) resource() any {
+	switch {
+	case n.catalog != nil:
+		return n.catalog
+	case n.collection != nil:
+		return n.collection
+	}
+	return n.item
+}
+
+// walk calls the function for the node and all of its descendants (parents before children).
+func (n *Node) walk(fn func(*Node) error) error {
+	if err := fn(n); err != nil {
+		return err
+	}
+	for _, child := range n.children {
+		if err := child.walk(fn); err != nil {
+			return err
+		}
+	}
+	for _, item := range n.items {
+		if err := item.walk(fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Layout has templates for the path of each type of resource relative to the
+// root directory.  Templates may include the following variables:
+//
+//   - {id} - the resource identifier
+//   - {path} - the directory of the parent resource
+//   - {parent} - the identifier of the parent resource
+//   - {collection} - the identifier of the collection (the resource itself for
+//     collections and the nearest collection ancestor otherwise)
+//   - {item} - the item identifier (items only)
+//
+// For example, an Item template of "{collection}/{item}/{item}.json" puts every
+// item in a directory under its collection's identifier.  The root resource is
+// always saved as catalog.json or collection.json.
+type Layout struct {
+	Catalog    string
+	Collection string
+	Item       string
+}
+
+// DefaultLayout follows the best practices for static catalogs, with each
+// resource in its own directory nested under its parent.
+var DefaultLayout = &Layout{
+	Catalog:    "{path}/{id}/catalog.json",
+	Collection: "{path}/{id}/collection.json",
+	Item:       "{path}/{id}/{id}.json",
+}
+
+var templateVariable = regexp.MustCompile(`\{([a-z]+)\}`)
+
+func (l *Layout) href(node *Node) (string, error) {
+	if node.parent == nil {
+		if node.collection != nil {
+			return "collection.json", nil
+		}
+		return "catalog.json", nil
+	}
+
+	var template string
+	switch {
+	case node.catalog != nil:
+		template = l.Catalog
+		if template == "" {
+			template = DefaultLayout.Catalog
+		}
+	case node.collection != nil:
+		template = l.Collection
+		if template == "" {
+			template = DefaultLayout.Collection
+		}
+	default:
+		template = l.Item
+		if template == "" {
+			template = DefaultLayout.Item
+		}
+	}
+
+	var expandErr error
+	href := templateVariable.ReplaceAllStringFunc(template, func(match string) string {
+		switch name := match[1 : len(match)-1]; name {
+		case "id":
+			return node.Id()
+		case "path":
+			return path.Dir(node.parent.href)
+		case "parent":
+			return node.parent.Id()
+		case "collection":
+			collection := node.nearestCollection()
+			if collection == nil {
+				expandErr = fmt.Errorf("cannot expand %s for %q without a collection", match, node.Id())
+				return ""
+			}
+			return collection.Id()
+		case "item":
+			if node.item == nil {
+				expandErr = fmt.Errorf("cannot expand %s for %q (not an item)", match, node.Id())
+				return ""
+			}
+			return node.Id()
+		default:
+			expandErr = fmt.Errorf("unknown variable %s in layout template %q", match, template)
+			return ""
+		}
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
+
+	href = strings.TrimPrefix(path.Clean("/"+href), "/")
+	if href == "" {
+		return "", fmt.Errorf("layout template %q results in an empty path for %q", template, node.Id())
+	}
+	return href, nil
+}
+
+// Builder generates links for a tree of resources and saves it as a static catalog.
+type Builder struct {
+	root        *Node
+	layout      *Layout
+	catalogType CatalogType
+	baseURL     string
+}
+
+// Options for the builder.
+type Options struct {
+	// Layout determines the path of each resource.  The DefaultLayout is used if
+	// not provided.
+	Layout *Layout
+
+	// Type determines how links are written.  The default is SelfContained.
+	Type CatalogType
+
+	// BaseURL is the URL where the root directory will be published.  It is
+	// required for RelativePublished and AbsolutePublished catalogs.
+	BaseURL string
+}
+
+// New creates a new builder for the tree with the provided root.
+func New(root *Node, options ...*Options) *Builder {
+	b := &Builder{
+		root:        root,
+		layout:      DefaultLayout,
+		catalogType: SelfContained,
+	}
+	for _, opt := range options {
+		if opt.Layout != nil {
+			b.layout = opt.Layout
+		}
+		if opt.Type != "" {
+			b.catalogType = opt.Type
+		}
+		if opt.BaseURL != "" {
+			b.baseURL = opt.BaseURL
+		}
+	}
+	return b
+}
+
+// Root returns the root node.
+func (b *Builder) Root() *Node {
+	return b.root
+}
+
+// Normalize assigns an href to every resource in the tree and replaces the
+// structural links on each resource.  It is called by Save and only needs to
+// be called directly to inspect the result without saving.
+func (b *Builder) Normalize() error {
+	switch b.catalogType {
+	case SelfContained:
+	case RelativePublished, AbsolutePublished:
+		if b.baseURL == "" {
+			return fmt.Errorf("a base URL is required for %s catalogs", b.catalogType)
+		}
+		base, err := url.Parse(b.baseURL)
+		if err != nil || !base.IsAbs() {
+			return fmt.Errorf("invalid base URL: %s", b.baseURL)
+		}
+	default:
+		return fmt.Errorf("unsupported catalog type: %s", b.catalogType)
+	}
+
+	if b.root.item != nil {
+		return errors.New("the root must be a catalog or collection")
+	}
+	b.root.parent = nil
+
+	seen := map[string]*Node{}
+	err := b.root.walk(func(node *Node) error {
+		href, err := b.layout.href(node)
+		if err != nil {
+			return err
+		}
+		if existing, ok := seen[href]; ok {
+			return fmt.Errorf("both %q and %q would be saved as %s", existing.Id(), node.Id(), href)
+		}
+		seen[href] = node
+		node.href = href
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return b.root.walk(func(node *Node) error {
+		b.updateLinks(node)
+		return nil
+	})
+}
+
+// target returns the href of one resource as referenced from another.
+func (b *Builder) target(from *Node, to *Node) string {
+	if b.catalogType == AbsolutePublished {
+		return b.absolute(to)
+	}
+	return normurl.Rel(from.href, to.href)
+}
+
+func (b *Builder) absolute(node *Node) string {
+	return strings.TrimSuffix(b.baseURL, "/") + "/" + node.href
+}
+
+func (b *Builder) updateLinks(node *Node) {
+	links := []*stac.Link{
+		{Rel: "root", Href: b.target(node, b.root), Type: mediaTypeJSON},
+	}
+
+	if b.catalogType == AbsolutePublished || (b.catalogType == RelativePublished && node == b.root) {
+		links = append(links, &stac.Link{Rel: "self", Href: b.absolute(node), Type: node.mediaType()})
+	}
+
+	if node.parent != nil {
+		links = append(links, &stac.Link{Rel: "parent", Href: b.target(node, node.parent), Type: mediaTypeJSON})
+	}
+
+	if node.item != nil {
+		if collection := node.nearestCollection(); collection != nil {
+			node.item.Collection = collection.Id()
+			links = append(links, &stac.Link{Rel: "collection", Href: b.target(node, collection), Type: mediaTypeJSON})
+		}
+	}
+
+	for _, child := range node.children {
+		links = append(links, &stac.Link{Rel: "child", Href: b.target(node, child), Type: mediaTypeJSON, Title: child.title()})
+	}
+
+	for _, item := range node.items {
+		links = append(links, &stac.Link{Rel: "item", Href: b.target(node, item), Type: mediaTypeGeoJSON})
+	}
+
+	existing := node.links()
+	for _, link := range *existing {
+		if link == nil || structuralRels[link.Rel] {
+			continue
+		}
+		links = append(links, link)
+	}
+	*existing = links
+}
+
+// Save normalizes the tree and writes each resource to the directory.
+func (b *Builder) Save(dir string) error {
+	if err := b.Normalize(); err != nil {
+		return err
+	}
+
+	return b.root.walk(func(node *Node) error {
+		outFile := filepath.Join(dir, filepath.FromSlash(node.href))
+		if err := os.MkdirAll(filepath.Dir(outFile), 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+
+		data, err := json.MarshalIndent(node.resource(), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode %q: %w", node.Id(), err)
+		}
+		if err := os.WriteFile(outFile, data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", outFile, err)
+		}
+		return nil
+	})
+}
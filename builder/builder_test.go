@@ -0,0 +1,219 @@
+package builder_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/builder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTree(t *testing.T) *builder.Node {
+	root := builder.NewCatalog(&stac.Catalog{
+		Version:     "1.0.0",
+		Id:          "root",
+		Description: "Root catalog",
+		Links: []*stac.Link{
+			{Rel: "self", Href: "https://example.com/old/catalog.json"},
+			{Rel: "license", Href: "https://example.com/license.html"},
+		},
+	})
+
+	collection, err := root.AddCollection(&stac.Collection{
+		Version:     "1.0.0",
+		Id:          "collection-a",
+		Title:       "Collection A",
+		Description: "Test collection",
+		License:     "CC-BY-4.0",
+		Extent: &stac.Extent{
+			Spatial:  &stac.SpatialExtent{Bbox: [][]float64{{-180, -90, 180, 90}}},
+			Temporal: &stac.TemporalExtent{Interval: [][]any{{"2024-01-01T00:00:00Z", nil}}},
+		},
+	})
+	require.NoError(t, err)
+
+	for _, id := range []string{"item-1", "item-2"} {
+		_, err := collection.AddItem(&stac.Item{
+			Version:    "1.0.0",
+			Id:         id,
+			Geometry:   map[string]any{"type": "Point", "coordinates": []float64{1, 2}},
+			Properties: map[string]any{"datetime": "2024-01-01T00:00:00Z"},
+		})
+		require.NoError(t, err)
+	}
+	return root
+}
+
+func linkMap(links []*stac.Link) map[string][]string {
+	m := map[string][]string{}
+	for _, link := range links {
+		m[link.Rel] = append(m[link.Rel], link.Href)
+	}
+	return m
+}
+
+func TestSelfContained(t *testing.T) {
+	root := newTree(t)
+	b := builder.New(root)
+	require.NoError(t, b.Normalize())
+
+	collection := root.Children()[0]
+	item := collection.Items()[0]
+
+	assert.Equal(t, "catalog.json", root.Href())
+	assert.Equal(t, "collection-a/collection.json", collection.Href())
+	assert.Equal(t, "collection-a/item-1/item-1.json", item.Href())
+
+	assert.Equal(t, map[string][]string{
+		"root":    {"./catalog.json"},
+		"child":   {"./collection-a/collection.json"},
+		"license": {"https://example.com/license.html"},
+	}, linkMap(root.Catalog().Links))
+	assert.Equal(t, "Collection A", root.Catalog().Links[1].Title)
+
+	assert.Equal(t, map[string][]string{
+		"root":   {"../catalog.json"},
+		"parent": {"../catalog.json"},
+		"item":   {"./item-1/item-1.json", "./item-2/item-2.json"},
+	}, linkMap(collection.Collection().Links))
+
+	assert.Equal(t, map[string][]string{
+		"root":       {"../../catalog.json"},
+		"parent":     {"../collection.json"},
+		"collection": {"../collection.json"},
+	}, linkMap(item.Item().Links))
+	assert.Equal(t, "collection-a", item.Item().Collection)
+}
+
+func TestRelativePublished(t *testing.T) {
+	root := newTree(t)
+	b := builder.New(root, &builder.Options{
+		Type:    builder.RelativePublished,
+		BaseURL: "https://example.com/stac/",
+	})
+	require.NoError(t, b.Normalize())
+
+	assert.Equal(t, []string{"https://example.com/stac/catalog.json"}, linkMap(root.Catalog().Links)["self"])
+
+	collection := root.Children()[0]
+	assert.NotContains(t, linkMap(collection.Collection().Links), "self")
+	assert.Equal(t, []string{"../catalog.json"}, linkMap(collection.Collection().Links)["root"])
+}
+
+func TestAbsolutePublished(t *testing.T) {
+	root := newTree(t)
+	b := builder.New(root, &builder.Options{
+		Type:    builder.AbsolutePublished,
+		BaseURL: "https://example.com/stac",
+	})
+	require.NoError(t, b.Normalize())
+
+	item := root.Children()[0].Items()[1]
+	assert.Equal(t, map[string][]string{
+		"root":       {"https://example.com/stac/catalog.json"},
+		"self":       {"https://example.com/stac/collection-a/item-2/item-2.json"},
+		"parent":     {"https://example.com/stac/collection-a/collection.json"},
+		"collection": {"https://example.com/stac/collection-a/collection.json"},
+	}, linkMap(item.Item().Links))
+}
+
+func TestPublishedMissingBaseURL(t *testing.T) {
+	b := builder.New(newTree(t), &builder.Options{Type: builder.AbsolutePublished})
+	assert.ErrorContains(t, b.Normalize(), "base URL is required")
+}
+
+func TestLayout(t *testing.T) {
+	root := newTree(t)
+	catalog, err := root.AddCatalog(&stac.Catalog{Version: "1.0.0", Id: "nested", Description: "Nested catalog"})
+	require.NoError(t, err)
+	nested, err := catalog.AddCollection(&stac.Collection{Version: "1.0.0", Id: "collection-b", Description: "Nested collection"})
+	require.NoError(t, err)
+	item, err := nested.AddItem(&stac.Item{Version: "1.0.0", Id: "item-3"})
+	require.NoError(t, err)
+
+	b := builder.New(root, &builder.Options{
+		Layout: &builder.Layout{
+			Collection: "{id}/collection.json",
+			Item:       "{collection}/{item}/{item}.json",
+		},
+	})
+	require.NoError(t, b.Normalize())
+
+	assert.Equal(t, "nested/catalog.json", catalog.Href())
+	assert.Equal(t, "collection-b/collection.json", nested.Href())
+	assert.Equal(t, "collection-b/item-3/item-3.json", item.Href())
+	assert.Equal(t, []string{"../nested/catalog.json"}, linkMap(nested.Collection().Links)["parent"])
+}
+
+func TestLayoutErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		layout *builder.Layout
+		err    string
+	}{
+		{
+			name:   "unknown variable",
+			layout: &builder.Layout{Item: "{nope}/{id}.json"},
+			err:    "unknown variable {nope}",
+		},
+		{
+			name:   "item variable for collection",
+			layout: &builder.Layout{Collection: "{item}/collection.json"},
+			err:    "not an item",
+		},
+		{
+			name:   "conflicting paths",
+			layout: &builder.Layout{Item: "items.json"},
+			err:    "would be saved as items.json",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b := builder.New(newTree(t), &builder.Options{Layout: c.layout})
+			assert.ErrorContains(t, b.Normalize(), c.err)
+		})
+	}
+}
+
+func TestAddErrors(t *testing.T) {
+	root := newTree(t)
+
+	_, err := root.AddCollection(&stac.Collection{Id: "collection-a"})
+	assert.ErrorContains(t, err, "already has a child")
+
+	_, err = root.AddCatalog(&stac.Catalog{})
+	assert.ErrorContains(t, err, "missing id")
+
+	item := root.Children()[0].Items()[0]
+	_, err = item.AddItem(&stac.Item{Id: "child"})
+	assert.ErrorContains(t, err, "cannot add item")
+}
+
+func TestSave(t *testing.T) {
+	dir := t.TempDir()
+	b := builder.New(newTree(t))
+	require.NoError(t, b.Save(dir))
+
+	for _, name := range []string{
+		"catalog.json",
+		"collection-a/collection.json",
+		"collection-a/item-1/item-1.json",
+		"collection-a/item-2/item-2.json",
+	} {
+		assert.FileExists(t, filepath.Join(dir, name))
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "collection-a", "item-1", "item-1.json"))
+	require.NoError(t, err)
+
+	item := &stac.Item{}
+	require.NoError(t, json.Unmarshal(data, item))
+	assert.Equal(t, "item-1", item.Id)
+	assert.Equal(t, "collection-a", item.Collection)
+	assert.Equal(t, []string{"../collection.json"}, linkMap(item.Links)["parent"])
+}
@@ -0,0 +1,412 @@
+// Package checker verifies that the links and assets in STAC resources can be resolved.
+package checker
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/planetlabs/go-stac/crawler"
+	"github.com/planetlabs/go-stac/internal/normurl"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
+)
+
+// ReferenceType indicates whether a reference is a link or an asset.
+type ReferenceType string
+
+const (
+	LinkReference  ReferenceType = "link"
+	AssetReference ReferenceType = "asset"
+)
+
+// Reference is a link or asset href in a STAC resource.
+type Reference struct {
+	// Referrer is the file path or URL of the resource with the reference.
+	Referrer string
+
+	// Type indicates whether the reference is a link or an asset.
+	Type ReferenceType
+
+	// Name is the link relation type or the asset key.
+	Name string
+
+	// Href is the unresolved href from the link or asset.
+	Href string
+
+	// MediaType is the media type from the link or asset (if any).
+	MediaType string
+}
+
+// Status is the outcome of checking a reference.
+type Status string
+
+const (
+	// StatusOK indicates that the reference resolved to a resource with the expected media type.
+	StatusOK Status = "ok"
+
+	// StatusDead indicates that the reference could not be resolved.
+	StatusDead Status = "dead"
+
+	// StatusMismatch indicates that the resolved resource has an unexpected media type.
+	StatusMismatch Status = "mismatch"
+
+	// StatusUnsupported indicates that the reference could not be checked (e.g. an unsupported URL scheme).
+	StatusUnsupported Status = "unsupported"
+)
+
+// Result of checking a single reference.
+type Result struct {
+	*Reference
+
+	// Location is the resolved file path or URL.
+	Location string
+
+	// Status of the reference.
+	Status Status
+
+	// ContentType is the media type reported by the server or derived from the file extension.
+	ContentType string
+
+	// Err describes why a reference is dead or unsupported.
+	Err error
+}
+
+func (r *Result) String() string {
+	switch r.Status {
+	case StatusMismatch:
+		return fmt.Sprintf("%s: %s %q (%s) has media type %q, expected %q", r.Referrer, r.Type, r.Name, r.Href, r.ContentType, r.MediaType)
+	case StatusDead, StatusUnsupported:
+		return fmt.Sprintf("%s: %s %s %q (%s): %s", r.Referrer, r.Status, r.Type, r.Name, r.Href, r.Err)
+	default:
+		return fmt.Sprintf("%s: %s %s %q (%s)", r.Referrer, r.Status, r.Type, r.Name, r.Href)
+	}
+}
+
+// ResultHandler is called with the result of each check.  Calls are not made
+// concurrently.  Any returned error will stop checking.
+type ResultHandler func(*Result) error
+
+// Checker resolves links and asset hrefs.
+type Checker struct {
+	concurrency int
+	noRecursion bool
+	filter      func(*Reference) bool
+	client      *http.Client
+	cache       *sync.Map
+	group       *singleflight.Group
+}
+
+// Options for the Checker.
+type Options struct {
+	// Limit to the number of references to check concurrently.
+	Concurrency int
+
+	// Set to true to check the references of a single resource without crawling linked resources.
+	NoRecursion bool
+
+	// Optional function to limit which references are checked.  If the function returns
+	// false, the reference will not be checked.
+	Filter func(*Reference) bool
+
+	// Optional client for HTTP requests.
+	Client *http.Client
+}
+
+// New creates a new Checker.
+func New(options ...*Options) *Checker {
+	c := &Checker{
+		concurrency: runtime.GOMAXPROCS(0),
+		client:      &http.Client{Timeout: time.Minute},
+		cache:       &sync.Map{},
+		group:       &singleflight.Group{},
+	}
+	for _, opt := range options {
+		if opt.Concurrency != 0 {
+			c.concurrency = opt.Concurrency
+		}
+		if opt.NoRecursion {
+			c.noRecursion = opt.NoRecursion
+		}
+		if opt.Filter != nil {
+			c.filter = opt.Filter
+		}
+		if opt.Client != nil {
+			c.client = opt.Client
+		}
+	}
+	return c
+}
+
+// Check crawls STAC resources starting with the entry and checks every link and
+// asset href.  The handler is called with the result of each check.
+//
+// The entry can be a path to a local file or a URL.  Context cancellation will
+// stop checking and the context error will be returned.
+func (c *Checker) Check(ctx context.Context, entry string, handler ResultHandler) error {
+	mutex := &sync.Mutex{}
+	visitor := func(resource crawler.Resource, info *crawler.ResourceInfo) error {
+		results, err := c.CheckResource(ctx, resource, info.Location)
+		if err != nil {
+			return &stopError{err}
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		for _, result := range results {
+			if err := handler(result); err != nil {
+				return &stopError{err}
+			}
+		}
+
+		if c.noRecursion {
+			return crawler.ErrStopRecursion
+		}
+		return nil
+	}
+
+	// resources that cannot be loaded are reported as dead by the referrer
+	errorHandler := func(err error) error {
+		stop := &stopError{}
+		if errors.Is(err, crawler.ErrStopRecursion) || errors.As(err, &stop) {
+			return err
+		}
+		return nil
+	}
+
+	err := crawler.Crawl(entry, visitor, &crawler.Options{
+		Queue:        crawler.NewMemoryQueue(ctx, c.concurrency),
+		ErrorHandler: errorHandler,
+	})
+	stop := &stopError{}
+	if errors.As(err, &stop) {
+		return stop.err
+	}
+	return err
+}
+
+// stopError wraps errors that should stop the crawl.
+type stopError struct {
+	err error
+}
+
+func (e *stopError) Error() string {
+	return e.err.Error()
+}
+
+func (e *stopError) Unwrap() error {
+	return e.err
+}
+
+// References returns the links and assets of a resource.
+func References(resource crawler.Resource, location string) []*Reference {
+	references := []*Reference{}
+	for _, link := range resource.Links() {
+		if method := link["method"]; method != "" && !strings.EqualFold(method, http.MethodGet) {
+			continue
+		}
+		references = append(references, &Reference{
+			Referrer:  location,
+			Type:      LinkReference,
+			Name:      link["rel"],
+			Href:      link["href"],
+			MediaType: link["type"],
+		})
+	}
+	for key, asset := range resource.Assets() {
+		references = append(references, &Reference{
+			Referrer:  location,
+			Type:      AssetReference,
+			Name:      key,
+			Href:      asset.Href(),
+			MediaType: asset.Type(),
+		})
+	}
+	return references
+}
+
+// CheckResource checks the references of a single resource.  The location is the
+// file path or URL of the resource and is used to resolve relative hrefs.
+func (c *Checker) CheckResource(ctx context.Context, resource crawler.Resource, location string) ([]*Result, error) {
+	base, err := normurl.New(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse location %s: %w", location, err)
+	}
+
+	references := References(resource, location)
+	results := make([]*Result, 0, len(references))
+	mutex := &sync.Mutex{}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(c.concurrency)
+	for _, reference := range references {
+		if c.filter != nil && !c.filter(reference) {
+			continue
+		}
+		group.Go(func() error {
+			result := c.checkReference(groupCtx, base, reference)
+			mutex.Lock()
+			results = append(results, result)
+			mutex.Unlock()
+			return groupCtx.Err()
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(results, func(a *Result, b *Result) int {
+		return cmp.Or(
+			cmp.Compare(a.Type, b.Type),
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(a.Href, b.Href),
+		)
+	})
+	return results, nil
+}
+
+type target struct {
+	contentType string
+	err         error
+}
+
+func (c *Checker) checkReference(ctx context.Context, base *normurl.Locator, reference *Reference) *Result {
+	result := &Result{Reference: reference}
+	if reference.Href == "" {
+		result.Status = StatusDead
+		result.Err = errors.New("missing href")
+		return result
+	}
+
+	loc, err := base.Resolve(reference.Href)
+	if err != nil {
+		result.Status = StatusUnsupported
+		result.Err = err
+		return result
+	}
+	result.Location = loc.String()
+
+	t := c.resolve(ctx, loc)
+	if t.err != nil {
+		result.Status = StatusDead
+		result.Err = t.err
+		return result
+	}
+
+	result.ContentType = t.contentType
+	if !compatibleMediaTypes(reference.MediaType, t.contentType) {
+		result.Status = StatusMismatch
+		return result
+	}
+
+	result.Status = StatusOK
+	return result
+}
+
+// resolve checks that a location exists and determines its media type.  Results are
+// cached so that each location is only checked once.
+func (c *Checker) resolve(ctx context.Context, loc *normurl.Locator) *target {
+	key := loc.String()
+	if value, ok := c.cache.Load(key); ok {
+		return value.(*target)
+	}
+	value, _, _ := c.group.Do(key, func() (any, error) {
+		if value, ok := c.cache.Load(key); ok {
+			return value, nil
+		}
+		var t *target
+		if loc.IsFilepath() {
+			t = statFile(key)
+		} else {
+			t = c.request(ctx, key)
+		}
+		c.cache.Store(key, t)
+		return t, nil
+	})
+	return value.(*target)
+}
+
+func statFile(filePath string) *target {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return &target{err: err}
+	}
+	if info.IsDir() {
+		return &target{err: fmt.Errorf("%s is a directory", filePath)}
+	}
+	return &target{contentType: mime.TypeByExtension(path.Ext(filePath))}
+}
+
+func (c *Checker) request(ctx context.Context, location string) *target {
+	resp, err := c.do(ctx, http.MethodHead, location)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented || resp.StatusCode == http.StatusForbidden) {
+		// some servers don't support HEAD requests, try a range request instead
+		resp, err = c.do(ctx, http.MethodGet, location)
+	}
+	if err != nil {
+		return &target{err: err}
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return &target{err: fmt.Errorf("unexpected response: %d", resp.StatusCode)}
+	}
+	return &target{contentType: resp.Header.Get("Content-Type")}
+}
+
+func (c *Checker) do(ctx context.Context, method string, location string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	if method == http.MethodGet {
+		req.Header.Set("Range", "bytes=0-0")
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	return resp, nil
+}
+
+var genericMediaTypes = map[string]bool{
+	"":                         true,
+	"application/octet-stream": true,
+	"binary/octet-stream":      true,
+}
+
+func baseMediaType(mediaType string) string {
+	base, _, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(strings.Split(mediaType, ";")[0]))
+	}
+	return base
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// compatibleMediaTypes determines if an actual media type is compatible with an expected one.
+// Parameters are ignored, generic types are accepted, and all JSON types are considered
+// compatible with each other.
+func compatibleMediaTypes(expected string, actual string) bool {
+	expectedBase := baseMediaType(expected)
+	actualBase := baseMediaType(actual)
+	if genericMediaTypes[expectedBase] || genericMediaTypes[actualBase] {
+		return true
+	}
+	if expectedBase == actualBase {
+		return true
+	}
+	return isJSON(expectedBase) && isJSON(actualBase)
+}
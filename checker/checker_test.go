@@ -0,0 +1,126 @@
+package checker_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/planetlabs/go-stac/checker"
+	"github.com/planetlabs/go-stac/crawler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type outcome struct {
+	referrer string
+	name     string
+	status   checker.Status
+}
+
+func check(t *testing.T, entry string, options *checker.Options) []*checker.Result {
+	results := []*checker.Result{}
+	err := checker.New(options).Check(context.Background(), entry, func(result *checker.Result) error {
+		results = append(results, result)
+		return nil
+	})
+	require.NoError(t, err)
+	return results
+}
+
+func problems(results []*checker.Result) []outcome {
+	outcomes := []outcome{}
+	for _, result := range results {
+		if result.Status == checker.StatusOK {
+			continue
+		}
+		outcomes = append(outcomes, outcome{
+			referrer: filepath.Base(result.Referrer),
+			name:     result.Name,
+			status:   result.Status,
+		})
+	}
+	return outcomes
+}
+
+var expectedProblems = []outcome{
+	{referrer: "catalog.json", name: "item", status: checker.StatusDead},
+	{referrer: "item.json", name: "data", status: checker.StatusMismatch},
+	{referrer: "item.json", name: "missing", status: checker.StatusDead},
+}
+
+func TestCheckFiles(t *testing.T) {
+	results := check(t, "testdata/catalog.json", &checker.Options{})
+
+	assert.Len(t, results, 7)
+	assert.ElementsMatch(t, expectedProblems, problems(results))
+}
+
+func TestCheckHTTP(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	defer server.Close()
+
+	results := check(t, server.URL+"/catalog.json", &checker.Options{})
+
+	assert.Len(t, results, 7)
+	assert.ElementsMatch(t, expectedProblems, problems(results))
+}
+
+func TestCheckHTTPNoHead(t *testing.T) {
+	files := http.FileServer(http.Dir("testdata"))
+	ranges := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if r.Header.Get("Range") != "" {
+			ranges += 1
+		}
+		files.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	results := check(t, server.URL+"/item.json", &checker.Options{NoRecursion: true, Concurrency: 1})
+
+	assert.Len(t, results, 4)
+	assert.ElementsMatch(t, expectedProblems[1:], problems(results))
+	assert.Equal(t, 4, ranges)
+}
+
+func TestCheckFilter(t *testing.T) {
+	results := check(t, "testdata/catalog.json", &checker.Options{
+		Filter: func(reference *checker.Reference) bool {
+			return reference.Type == checker.LinkReference
+		},
+	})
+
+	assert.Len(t, results, 4)
+	assert.ElementsMatch(t, expectedProblems[:1], problems(results))
+}
+
+func TestCheckResource(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	resource := crawler.Resource{
+		"type": "Catalog",
+		"links": []any{
+			map[string]any{"rel": "child", "href": "s3://bucket/catalog.json"},
+			map[string]any{"rel": "search", "href": "https://example.com/search", "method": "POST"},
+			map[string]any{"rel": "root", "href": "./catalog.json", "type": "text/html"},
+		},
+	}
+	results, err := checker.New().CheckResource(context.Background(), resource, filepath.Join(wd, "testdata", "catalog.json"))
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, "child", results[0].Name)
+	assert.Equal(t, checker.StatusUnsupported, results[0].Status)
+
+	assert.Equal(t, "root", results[1].Name)
+	assert.Equal(t, checker.StatusMismatch, results[1].Status)
+	assert.Equal(t, "application/json", results[1].ContentType)
+}
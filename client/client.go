@@ -0,0 +1,154 @@
+// Package client implements a client for STAC APIs.
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/internal/fetch"
+	"github.com/planetlabs/go-stac/internal/normurl"
+)
+
+// ErrSearchNotSupported is returned when an API does not advertise a search endpoint.
+var ErrSearchNotSupported = errors.New("the API does not support item search")
+
+// Client makes requests to a STAC API.
+type Client struct {
+	httpClient        *http.Client
+	transactionClient *http.Client
+	header            http.Header
+	location          *normurl.Locator
+	landing           *stac.Catalog
+}
+
+// Options for the Client.
+type Options struct {
+	// Optional client for HTTP requests.  By default, requests that fail with
+	// connection or server errors are retried, except for transaction requests
+	// (which are not idempotent and time out after a minute).  A provided client
+	// is used for all requests.
+	Client *http.Client
+
+	// Optional headers to include with every request (e.g. for authorization).
+	Header http.Header
+}
+
+// New creates a new Client by loading the landing page of a STAC API.
+func New(ctx context.Context, landingPage string, options ...*Options) (*Client, error) {
+	c := &Client{
+		httpClient:        fetch.DefaultClient,
+		transactionClient: &http.Client{Timeout: time.Minute},
+		header:            http.Header{},
+	}
+	for _, opt := range options {
+		if opt.Client != nil {
+			c.httpClient = opt.Client
+			c.transactionClient = opt.Client
+		}
+		if opt.Header != nil {
+			c.header = opt.Header.Clone()
+		}
+	}
+
+	loc, err := normurl.New(landingPage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse landing page URL %s: %w", landingPage, err)
+	}
+	if loc.IsFilepath() {
+		return nil, fmt.Errorf("expected a landing page URL, got %s", landingPage)
+	}
+	c.location = loc
+
+	landing := &stac.Catalog{}
+	if err := c.get(ctx, loc.String(), landing); err != nil {
+		return nil, err
+	}
+	c.landing = landing
+	return c, nil
+}
+
+// Landing returns the API landing page.
+func (c *Client) Landing() *stac.Catalog {
+	return c.landing
+}
+
+// ConformsTo returns the conformance classes advertised by the landing page.
+func (c *Client) ConformsTo() []string {
+	return slices.Clone(c.landing.ConformsTo)
+}
+
+var stacConformancePattern = regexp.MustCompile(`^https://api\.stacspec\.org/v1\.[^/]+/(.+)$`)
+
+// Conforms determines if the API advertises a conformance class.  The class can
+// be a full URI or the name of a STAC API class without the version prefix (e.g.
+// "item-search" or "item-search#filter").
+func (c *Client) Conforms(class string) bool {
+	for _, uri := range c.landing.ConformsTo {
+		if uri == class {
+			return true
+		}
+		if match := stacConformancePattern.FindStringSubmatch(uri); match != nil && match[1] == class {
+			return true
+		}
+	}
+	return false
+}
+
+// link returns the resolved URL for the first landing page link with the provided
+// relation type and method (GET if empty).
+func (c *Client) link(rel string, method string) (*stac.Link, string, bool) {
+	for _, link := range c.landing.Links {
+		if link.Rel != rel {
+			continue
+		}
+		linkMethod := link.Method
+		if linkMethod == "" {
+			linkMethod = http.MethodGet
+		}
+		if method != "" && !strings.EqualFold(linkMethod, method) {
+			continue
+		}
+		loc, err := c.resolve(link.Href)
+		if err != nil {
+			continue
+		}
+		return link, loc, true
+	}
+	return nil, "", false
+}
+
+func (c *Client) resolve(href string) (string, error) {
+	loc, err := c.location.Resolve(href)
+	if err != nil {
+		return "", err
+	}
+	return loc.String(), nil
+}
+
+func (c *Client) get(ctx context.Context, location string, value any) error {
+	return c.do(ctx, &fetch.Request{Method: http.MethodGet, Location: location}, value)
+}
+
+func (c *Client) do(ctx context.Context, request *fetch.Request, value any) error {
+	return apiError(fetch.JSON(ctx, c.httpClient, c.prepare(request), value))
+}
+
+// prepare adds the client headers and a default Accept header to a request.
+func (c *Client) prepare(request *fetch.Request) *fetch.Request {
+	header := c.header.Clone()
+	for key, values := range request.Header {
+		header[key] = values
+	}
+	if header.Get("Accept") == "" {
+		header.Set("Accept", "application/json, application/geo+json")
+	}
+	request.Header = header
+	return request
+}
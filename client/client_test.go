@@ -0,0 +1,255 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/planetlabs/go-stac/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func item(id string) map[string]any {
+	return map[string]any{
+		"type":         "Feature",
+		"stac_version": "1.0.0",
+		"id":           id,
+		"geometry":     map[string]any{"type": "Point", "coordinates": []float64{0, 0}},
+		"bbox":         []float64{0, 0, 0, 0},
+		"properties":   map[string]any{"datetime": "2024-01-01T00:00:00Z"},
+		"links":        []any{},
+		"assets":       map[string]any{},
+	}
+}
+
+func landing(url string, searchMethods ...string) map[string]any {
+	links := []map[string]any{
+		{"rel": "self", "href": url + "/", "type": "application/json"},
+		{"rel": "root", "href": url + "/", "type": "application/json"},
+	}
+	for _, method := range searchMethods {
+		links = append(links, map[string]any{"rel": "search", "href": url + "/search", "type": "application/geo+json", "method": method})
+	}
+	return map[string]any{
+		"type":         "Catalog",
+		"stac_version": "1.0.0",
+		"id":           "api",
+		"description":  "Test API",
+		"conformsTo": []string{
+			"https://api.stacspec.org/v1.0.0/core",
+			"https://api.stacspec.org/v1.0.0/item-search",
+			"https://api.stacspec.org/v1.0.0/item-search#filter",
+		},
+		"links": links,
+	}
+}
+
+func TestNew(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("Authorization"))
+		writeJSON(w, landing("http://"+r.Host, "GET"))
+	}))
+	defer server.Close()
+
+	c, err := client.New(context.Background(), server.URL+"/", &client.Options{
+		Header: http.Header{"Authorization": []string{"secret"}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "api", c.Landing().Id)
+	assert.Len(t, c.ConformsTo(), 3)
+	assert.True(t, c.Conforms("core"))
+	assert.True(t, c.Conforms("item-search#filter"))
+	assert.True(t, c.Conforms("https://api.stacspec.org/v1.0.0/item-search"))
+	assert.False(t, c.Conforms("collections"))
+}
+
+func TestNewNotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, err := client.New(context.Background(), server.URL+"/", &client.Options{Client: http.DefaultClient})
+	require.Error(t, err)
+}
+
+func TestSearchGet(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, landing("http://"+r.Host, "GET", "POST"))
+	})
+	mux.HandleFunc("GET /search", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "a,b", query.Get("collections"))
+		assert.Equal(t, "-10,-10.5,10,10.5", query.Get("bbox"))
+		assert.Equal(t, "2024-01-01T00:00:00Z/..", query.Get("datetime"))
+		assert.Equal(t, "2", query.Get("limit"))
+		assert.Equal(t, "eo:cloud_cover < 10", query.Get("filter"))
+		assert.Equal(t, "secret", r.Header.Get("Authorization"))
+
+		ids := []any{item("item-1"), item("item-2")}
+		links := []map[string]any{{"rel": "next", "href": "/search?" + query.Encode() + "&page=2"}}
+		if query.Get("page") == "2" {
+			ids = []any{item("item-3")}
+			links = nil
+		}
+		writeJSON(w, map[string]any{"type": "FeatureCollection", "features": ids, "links": links})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ctx := context.Background()
+	c, err := client.New(ctx, server.URL+"/", &client.Options{
+		Header: http.Header{"Authorization": []string{"secret"}},
+	})
+	require.NoError(t, err)
+
+	ids := []string{}
+	for item, err := range c.Search(ctx, &client.SearchParams{
+		Collections: []string{"a", "b"},
+		Bbox:        []float64{-10, -10.5, 10, 10.5},
+		Datetime:    "2024-01-01T00:00:00Z/..",
+		Limit:       2,
+		Additional:  map[string]any{"filter": "eo:cloud_cover < 10"},
+	}) {
+		require.NoError(t, err)
+		ids = append(ids, item.Id)
+	}
+	assert.Equal(t, []string{"item-1", "item-2", "item-3"}, ids)
+}
+
+func TestSearchPost(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, landing("http://"+r.Host, "GET", "POST"))
+	})
+	mux.HandleFunc("POST /search", func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]any{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, []any{"item-1", "item-2"}, body["ids"])
+		assert.Equal(t, map[string]any{"type": "Point", "coordinates": []any{0.0, 0.0}}, body["intersects"])
+
+		if body["token"] == "page-2" {
+			writeJSON(w, map[string]any{"type": "FeatureCollection", "features": []any{item("item-2")}})
+			return
+		}
+		writeJSON(w, map[string]any{
+			"type":     "FeatureCollection",
+			"features": []any{item("item-1")},
+			"links": []map[string]any{{
+				"rel":    "next",
+				"href":   "/search",
+				"method": "POST",
+				"body":   map[string]any{"token": "page-2"},
+				"merge":  true,
+			}},
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ctx := context.Background()
+	c, err := client.New(ctx, server.URL+"/")
+	require.NoError(t, err)
+
+	ids := []string{}
+	for item, err := range c.Search(ctx, &client.SearchParams{
+		Ids:        []string{"item-1", "item-2"},
+		Intersects: map[string]any{"type": "Point", "coordinates": []float64{0, 0}},
+	}) {
+		require.NoError(t, err)
+		ids = append(ids, item.Id)
+	}
+	assert.Equal(t, []string{"item-1", "item-2"}, ids)
+}
+
+func TestSearchStop(t *testing.T) {
+	requests := atomic.Int32{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, landing("http://"+r.Host, "GET"))
+	})
+	mux.HandleFunc("GET /search", func(w http.ResponseWriter, r *http.Request) {
+		count := requests.Add(1)
+		writeJSON(w, map[string]any{
+			"type":     "FeatureCollection",
+			"features": []any{item(fmt.Sprintf("item-%d", count))},
+			"links":    []map[string]any{{"rel": "next", "href": fmt.Sprintf("/search?page=%d", count+1)}},
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ctx := context.Background()
+	c, err := client.New(ctx, server.URL+"/")
+	require.NoError(t, err)
+
+	count := 0
+	for _, err := range c.Search(ctx, nil) {
+		require.NoError(t, err)
+		count += 1
+		if count == 3 {
+			break
+		}
+	}
+	assert.Equal(t, 3, count)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestSearchCycle(t *testing.T) {
+	requests := atomic.Int32{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, landing("http://"+r.Host, "GET"))
+	})
+	mux.HandleFunc("GET /search", func(w http.ResponseWriter, r *http.Request) {
+		count := requests.Add(1)
+		// the second page links back to the first
+		page := count%2 + 1
+		writeJSON(w, map[string]any{
+			"type":     "FeatureCollection",
+			"features": []any{item(fmt.Sprintf("item-%d", count))},
+			"links":    []map[string]any{{"rel": "next", "href": fmt.Sprintf("/search?page=%d", page)}},
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ctx := context.Background()
+	c, err := client.New(ctx, server.URL+"/")
+	require.NoError(t, err)
+
+	ids := []string{}
+	for item, err := range c.Search(ctx, &client.SearchParams{Additional: map[string]any{"page": "1"}}) {
+		require.NoError(t, err)
+		ids = append(ids, item.Id)
+		require.Less(t, len(ids), 10, "search did not stop")
+	}
+	assert.Equal(t, []string{"item-1", "item-2"}, ids)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestSearchNotSupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, landing("http://"+r.Host))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	c, err := client.New(ctx, server.URL+"/")
+	require.NoError(t, err)
+
+	for _, err := range c.Search(ctx, &client.SearchParams{}) {
+		assert.ErrorIs(t, err, client.ErrSearchNotSupported)
+	}
+}
@@ -0,0 +1,255 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"maps"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/internal/fetch"
+)
+
+// SearchParams are the parameters for an item search.
+type SearchParams struct {
+	// Collections limits results to items in the listed collections.
+	Collections []string
+
+	// Ids limits results to items with the listed identifiers.
+	Ids []string
+
+	// Bbox limits results to items that intersect a bounding box (4 or 6 numbers).
+	Bbox []float64
+
+	// Intersects limits results to items that intersect a GeoJSON geometry.
+	Intersects map[string]any
+
+	// Datetime limits results to items with a datetime or datetime range that
+	// intersects a single RFC 3339 datetime or an interval (e.g. "2020-01-01T00:00:00Z/..").
+	Datetime string
+
+	// Limit is the number of items to request per page.  This does not limit the
+	// total number of items returned by Search.
+	Limit int
+
+	// Additional search parameters (e.g. "filter", "filter-lang", or "sortby").  In
+	// GET requests, string values are included as-is and other values are encoded
+	// as JSON.
+	Additional map[string]any
+
+	// Method is the HTTP method for the search request.  If empty, GET is used unless
+	// Intersects is set or the API only advertises a POST search link.
+	Method string
+}
+
+// Search returns an iterator of items matching the search parameters.  The next
+// links of each page of results are followed until there are no more results, a
+// next link repeats an earlier request, or the caller stops iterating.
+//
+// If the API does not advertise a search link, ErrSearchNotSupported is yielded.
+func (c *Client) Search(ctx context.Context, params *SearchParams) iter.Seq2[*stac.Item, error] {
+	return func(yield func(*stac.Item, error) bool) {
+		request, err := c.searchRequest(params)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		seen := map[string]bool{}
+		for request != nil {
+			seen[requestKey(request)] = true
+			page := &stac.ItemsList{}
+			if err := c.do(ctx, request, page); err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if len(page.Items) == 0 {
+				return
+			}
+
+			request, err = c.nextRequest(request, page.Links)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if request != nil && seen[requestKey(request)] {
+				// a next link that repeats an earlier request would loop forever
+				return
+			}
+		}
+	}
+}
+
+func (c *Client) searchRequest(params *SearchParams) (*fetch.Request, error) {
+	if params == nil {
+		params = &SearchParams{}
+	}
+
+	method := strings.ToUpper(params.Method)
+	if method == "" {
+		method = http.MethodGet
+		if params.Intersects != nil {
+			method = http.MethodPost
+		}
+	}
+
+	_, location, ok := c.link("search", method)
+	if !ok && params.Method == "" {
+		// fall back to any advertised search method
+		for _, fallback := range []string{http.MethodPost, http.MethodGet} {
+			if _, location, ok = c.link("search", fallback); ok {
+				method = fallback
+				break
+			}
+		}
+	}
+	if !ok {
+		return nil, ErrSearchNotSupported
+	}
+
+	values := params.values()
+	if method == http.MethodGet {
+		query, err := encodeQuery(values)
+		if err != nil {
+			return nil, err
+		}
+		return &fetch.Request{Method: method, Location: withQuery(location, query)}, nil
+	}
+
+	body, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode search body: %w", err)
+	}
+	return &fetch.Request{Method: method, Location: location, Body: body}, nil
+}
+
+// values returns the search parameters as they would appear in a POST request body.
+func (params *SearchParams) values() map[string]any {
+	values := map[string]any{}
+	if len(params.Collections) > 0 {
+		values["collections"] = params.Collections
+	}
+	if len(params.Ids) > 0 {
+		values["ids"] = params.Ids
+	}
+	if len(params.Bbox) > 0 {
+		values["bbox"] = params.Bbox
+	}
+	if params.Intersects != nil {
+		values["intersects"] = params.Intersects
+	}
+	if params.Datetime != "" {
+		values["datetime"] = params.Datetime
+	}
+	if params.Limit > 0 {
+		values["limit"] = params.Limit
+	}
+	maps.Copy(values, params.Additional)
+	return values
+}
+
+func encodeQuery(values map[string]any) (url.Values, error) {
+	query := url.Values{}
+	for key, value := range values {
+		switch v := value.(type) {
+		case string:
+			query.Set(key, v)
+		case int:
+			query.Set(key, strconv.Itoa(v))
+		case []string:
+			query.Set(key, strings.Join(v, ","))
+		case []float64:
+			parts := make([]string, len(v))
+			for i, f := range v {
+				parts[i] = strconv.FormatFloat(f, 'f', -1, 64)
+			}
+			query.Set(key, strings.Join(parts, ","))
+		default:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode %q search parameter: %w", key, err)
+			}
+			query.Set(key, string(data))
+		}
+	}
+	return query, nil
+}
+
+func withQuery(location string, query url.Values) string {
+	if len(query) == 0 {
+		return location
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return location
+	}
+	existing := u.Query()
+	for key, values := range query {
+		existing[key] = values
+	}
+	u.RawQuery = existing.Encode()
+	return u.String()
+}
+
+// requestKey identifies a request by its method, location, and body.
+func requestKey(request *fetch.Request) string {
+	return request.Method + " " + request.Location + "\n" + string(request.Body)
+}
+
+// nextRequest returns the request for the next page of results or nil if there are no more.
+func (c *Client) nextRequest(previous *fetch.Request, links []*stac.Link) (*fetch.Request, error) {
+	var next *stac.Link
+	for _, link := range links {
+		if link.Rel == "next" {
+			next = link
+			break
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+
+	base, err := url.Parse(previous.Location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", previous.Location, err)
+	}
+	href, err := base.Parse(next.Href)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse next link %s: %w", next.Href, err)
+	}
+
+	request := &fetch.Request{Method: http.MethodGet, Location: href.String(), Header: http.Header{}}
+	for key, value := range next.Headers {
+		request.Header.Set(key, fmt.Sprint(value))
+	}
+	if next.Method == "" || strings.EqualFold(next.Method, http.MethodGet) {
+		return request, nil
+	}
+
+	request.Method = strings.ToUpper(next.Method)
+	body := map[string]any{}
+	if merge, _ := next.AdditionalFields["merge"].(bool); merge && previous.Body != nil {
+		if err := json.Unmarshal(previous.Body, &body); err != nil {
+			return nil, fmt.Errorf("failed to decode previous search body: %w", err)
+		}
+	}
+	if nextBody, ok := next.Body.(map[string]any); ok {
+		maps.Copy(body, nextBody)
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode search body: %w", err)
+	}
+	request.Body = data
+	return request, nil
+}
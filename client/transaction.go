@@ -0,0 +1,326 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/internal/fetch"
+)
+
+var (
+	// ErrTransactionsNotSupported is returned when an API does not advertise support
+	// for the Transaction extension.
+	ErrTransactionsNotSupported = errors.New("the API does not support transactions")
+
+	// ErrInvalid is matched by an *APIError when the API rejects a request as invalid
+	// (a 400 or 422 response).
+	ErrInvalid = errors.New("invalid request")
+
+	// ErrNotFound is matched by an *APIError for a 404 response.
+	ErrNotFound = errors.New("not found")
+
+	// ErrConflict is matched by an *APIError for a 409 response (e.g. when creating
+	// a resource that already exists).
+	ErrConflict = errors.New("conflict")
+)
+
+// APIError is returned when an API responds with an error status.  Use errors.Is
+// with ErrInvalid, ErrNotFound, or ErrConflict to check for common failures.
+type APIError struct {
+	// Location is the URL of the request.
+	Location string
+
+	// StatusCode is the response status code.
+	StatusCode int
+
+	// Code is the error code from the response body (if any).
+	Code string
+
+	// Description is the error description from the response body (if any).
+	Description string
+
+	// Body is the raw response body.
+	Body []byte
+}
+
+func (e *APIError) Error() string {
+	message := e.Description
+	if message == "" {
+		message = e.Code
+	}
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("request to %s failed with status %d: %s", e.Location, e.StatusCode, message)
+}
+
+func (e *APIError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ErrInvalid
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	}
+	return nil
+}
+
+// apiError converts a status error into an *APIError.  Other errors are returned
+// unchanged.
+func apiError(err error) error {
+	var statusErr *fetch.StatusError
+	if !errors.As(err, &statusErr) {
+		return err
+	}
+	apiErr := &APIError{
+		Location:   statusErr.Location,
+		StatusCode: statusErr.StatusCode,
+		Body:       statusErr.Body,
+	}
+	body := map[string]any{}
+	if json.Unmarshal(statusErr.Body, &body) == nil {
+		apiErr.Code, _ = body["code"].(string)
+		apiErr.Description, _ = body["description"].(string)
+	}
+	return apiErr
+}
+
+var (
+	itemTransactionClasses = []string{
+		"ogcapi-features/extensions/transaction",
+		"http://www.opengis.net/spec/ogcapi-features-4/1.0/conf/create-replace-delete",
+	}
+	collectionTransactionClasses = []string{
+		"collections/extensions/transaction",
+	}
+)
+
+func (c *Client) conformsToAny(classes []string) bool {
+	for _, class := range classes {
+		if c.Conforms(class) {
+			return true
+		}
+	}
+	return false
+}
+
+// collectionsURL returns the URL of the collections endpoint.
+func (c *Client) collectionsURL() (string, error) {
+	if _, loc, ok := c.link("data", http.MethodGet); ok {
+		return strings.TrimSuffix(loc, "/"), nil
+	}
+	return c.resolve("collections")
+}
+
+func (c *Client) itemsURL(collectionId string) (string, error) {
+	if !c.conformsToAny(itemTransactionClasses) {
+		return "", ErrTransactionsNotSupported
+	}
+	if collectionId == "" {
+		return "", errors.New("missing collection identifier")
+	}
+	collections, err := c.collectionsURL()
+	if err != nil {
+		return "", err
+	}
+	return collections + "/" + url.PathEscape(collectionId) + "/items", nil
+}
+
+func (c *Client) itemURL(collectionId string, itemId string) (string, error) {
+	if itemId == "" {
+		return "", errors.New("missing item identifier")
+	}
+	items, err := c.itemsURL(collectionId)
+	if err != nil {
+		return "", err
+	}
+	return items + "/" + url.PathEscape(itemId), nil
+}
+
+func (c *Client) collectionURL(collectionId string) (string, error) {
+	if !c.conformsToAny(collectionTransactionClasses) {
+		return "", ErrTransactionsNotSupported
+	}
+	if collectionId == "" {
+		return "", errors.New("missing collection identifier")
+	}
+	collections, err := c.collectionsURL()
+	if err != nil {
+		return "", err
+	}
+	return collections + "/" + url.PathEscape(collectionId), nil
+}
+
+const mediaTypeMergePatch = "application/merge-patch+json"
+
+// send makes a transaction request and returns the response body (which is empty
+// if the API does not respond with a resource).  Failed requests are not retried.
+func (c *Client) send(ctx context.Context, method string, location string, body any) (json.RawMessage, error) {
+	request := &fetch.Request{Method: method, Location: location, Header: http.Header{}}
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request body: %w", err)
+		}
+		request.Body = data
+		if method == http.MethodPatch {
+			request.Header.Set("Content-Type", mediaTypeMergePatch)
+		}
+	}
+
+	var response json.RawMessage
+	if _, err := fetch.Do(ctx, c.transactionClient, c.prepare(request), &response); err != nil {
+		return nil, apiError(err)
+	}
+	return response, nil
+}
+
+// decodeResult decodes a response body into the value.  If the response has no
+// body, nil is returned.
+func decodeResult[T any](data json.RawMessage, value *T) (*T, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return value, nil
+}
+
+// CreateItem adds a new item to a collection.  The item's collection member
+// identifies the collection.  If the API responds with the created item, it is
+// returned.  A nil item is returned if the API accepts the request without
+// responding with the item (e.g. for asynchronous processing).
+//
+// If the API does not advertise support for the Transaction extension,
+// ErrTransactionsNotSupported is returned.  Error responses from the API result
+// in an *APIError.
+func (c *Client) CreateItem(ctx context.Context, item *stac.Item) (*stac.Item, error) {
+	if item == nil {
+		return nil, errors.New("missing item")
+	}
+	location, err := c.itemsURL(item.Collection)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.send(ctx, http.MethodPost, location, item)
+	if err != nil {
+		return nil, err
+	}
+	return decodeResult(data, &stac.Item{})
+}
+
+// UpdateItem replaces an existing item.  The item's collection and id members
+// identify the item to replace.  The updated item is returned if the API
+// responds with it (and nil otherwise).
+func (c *Client) UpdateItem(ctx context.Context, item *stac.Item) (*stac.Item, error) {
+	if item == nil {
+		return nil, errors.New("missing item")
+	}
+	location, err := c.itemURL(item.Collection, item.Id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.send(ctx, http.MethodPut, location, item)
+	if err != nil {
+		return nil, err
+	}
+	return decodeResult(data, &stac.Item{})
+}
+
+// PatchItem updates part of an existing item with a JSON merge patch (RFC 7396).
+// Members with a nil value in the patch are removed from the item.  The updated
+// item is returned if the API responds with it (and nil otherwise).
+func (c *Client) PatchItem(ctx context.Context, collectionId string, itemId string, patch map[string]any) (*stac.Item, error) {
+	location, err := c.itemURL(collectionId, itemId)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.send(ctx, http.MethodPatch, location, patch)
+	if err != nil {
+		return nil, err
+	}
+	return decodeResult(data, &stac.Item{})
+}
+
+// DeleteItem removes an item from a collection.
+func (c *Client) DeleteItem(ctx context.Context, collectionId string, itemId string) error {
+	location, err := c.itemURL(collectionId, itemId)
+	if err != nil {
+		return err
+	}
+	_, err = c.send(ctx, http.MethodDelete, location, nil)
+	return err
+}
+
+// CreateCollection adds a new collection.  The created collection is returned
+// if the API responds with it (and nil otherwise).
+//
+// If the API does not advertise support for the collection Transaction
+// extension, ErrTransactionsNotSupported is returned.
+func (c *Client) CreateCollection(ctx context.Context, collection *stac.Collection) (*stac.Collection, error) {
+	if collection == nil {
+		return nil, errors.New("missing collection")
+	}
+	if !c.conformsToAny(collectionTransactionClasses) {
+		return nil, ErrTransactionsNotSupported
+	}
+	location, err := c.collectionsURL()
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.send(ctx, http.MethodPost, location, collection)
+	if err != nil {
+		return nil, err
+	}
+	return decodeResult(data, &stac.Collection{})
+}
+
+// UpdateCollection replaces an existing collection with the same id.  The
+// updated collection is returned if the API responds with it (and nil otherwise).
+func (c *Client) UpdateCollection(ctx context.Context, collection *stac.Collection) (*stac.Collection, error) {
+	if collection == nil {
+		return nil, errors.New("missing collection")
+	}
+	location, err := c.collectionURL(collection.Id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.send(ctx, http.MethodPut, location, collection)
+	if err != nil {
+		return nil, err
+	}
+	return decodeResult(data, &stac.Collection{})
+}
+
+// PatchCollection updates part of an existing collection with a JSON merge patch
+// (RFC 7396).  The updated collection is returned if the API responds with it
+// (and nil otherwise).
+func (c *Client) PatchCollection(ctx context.Context, collectionId string, patch map[string]any) (*stac.Collection, error) {
+	location, err := c.collectionURL(collectionId)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.send(ctx, http.MethodPatch, location, patch)
+	if err != nil {
+		return nil, err
+	}
+	return decodeResult(data, &stac.Collection{})
+}
+
+// DeleteCollection removes a collection.
+func (c *Client) DeleteCollection(ctx context.Context, collectionId string) error {
+	location, err := c.collectionURL(collectionId)
+	if err != nil {
+		return err
+	}
+	_, err = c.send(ctx, http.MethodDelete, location, nil)
+	return err
+}
@@ -0,0 +1,361 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// transactionAPI is an in-memory stand-in for an API with the Transaction extension.
+type transactionAPI struct {
+	mutex       sync.Mutex
+	conformsTo  []string
+	collections map[string]map[string]any
+	items       map[string]map[string]any
+	async       bool
+	requests    []string
+}
+
+func newTransactionAPI(conformsTo ...string) *transactionAPI {
+	return &transactionAPI{
+		conformsTo:  conformsTo,
+		collections: map[string]map[string]any{},
+		items:       map[string]map[string]any{},
+	}
+}
+
+func writeAPIError(w http.ResponseWriter, status int, code string, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"code": code, "description": description})
+}
+
+func (api *transactionAPI) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		l := landing("http://" + r.Host)
+		l["conformsTo"] = api.conformsTo
+		l["links"] = append(l["links"].([]map[string]any), map[string]any{
+			"rel": "data", "href": "http://" + r.Host + "/collections", "type": "application/json",
+		})
+		writeJSON(w, l)
+	})
+
+	decode := func(w http.ResponseWriter, r *http.Request) (map[string]any, bool) {
+		body := map[string]any{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeAPIError(w, http.StatusBadRequest, "InvalidBody", err.Error())
+			return nil, false
+		}
+		if id, _ := body["id"].(string); id == "" {
+			writeAPIError(w, http.StatusBadRequest, "InvalidParameterValue", "missing id")
+			return nil, false
+		}
+		return body, true
+	}
+
+	respond := func(w http.ResponseWriter, status int, value map[string]any) {
+		if api.async {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(value)
+	}
+
+	store := func(resources map[string]map[string]any, key string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			api.mutex.Lock()
+			defer api.mutex.Unlock()
+			api.requests = append(api.requests, r.Method+" "+r.URL.Path)
+
+			id := r.PathValue(key)
+			if key == "itemId" {
+				id = r.PathValue("collectionId") + "/" + id
+			}
+
+			switch r.Method {
+			case http.MethodPost:
+				body, ok := decode(w, r)
+				if !ok {
+					return
+				}
+				id = body["id"].(string)
+				if key == "itemId" {
+					id = r.PathValue("collectionId") + "/" + id
+				}
+				if _, exists := resources[id]; exists {
+					writeAPIError(w, http.StatusConflict, "Conflict", "already exists")
+					return
+				}
+				resources[id] = body
+				respond(w, http.StatusCreated, body)
+				return
+			}
+
+			existing, exists := resources[id]
+			if !exists {
+				writeAPIError(w, http.StatusNotFound, "NotFound", "no resource "+id)
+				return
+			}
+
+			switch r.Method {
+			case http.MethodPut:
+				body, ok := decode(w, r)
+				if !ok {
+					return
+				}
+				resources[id] = body
+				respond(w, http.StatusOK, body)
+			case http.MethodPatch:
+				if r.Header.Get("Content-Type") != "application/merge-patch+json" {
+					writeAPIError(w, http.StatusUnsupportedMediaType, "UnsupportedMediaType", r.Header.Get("Content-Type"))
+					return
+				}
+				patch := map[string]any{}
+				if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+					writeAPIError(w, http.StatusBadRequest, "InvalidBody", err.Error())
+					return
+				}
+				for key, value := range patch {
+					if value == nil {
+						delete(existing, key)
+					} else {
+						existing[key] = value
+					}
+				}
+				respond(w, http.StatusOK, existing)
+			case http.MethodDelete:
+				delete(resources, id)
+				w.WriteHeader(http.StatusNoContent)
+			}
+		}
+	}
+
+	mux.HandleFunc("POST /collections", store(api.collections, "collectionId"))
+	mux.HandleFunc("/collections/{collectionId}", store(api.collections, "collectionId"))
+	mux.HandleFunc("POST /collections/{collectionId}/items", store(api.items, "itemId"))
+	mux.HandleFunc("/collections/{collectionId}/items/{itemId}", store(api.items, "itemId"))
+	return mux
+}
+
+const (
+	itemTransactionClass       = "https://api.stacspec.org/v1.0.0/ogcapi-features/extensions/transaction"
+	collectionTransactionClass = "https://api.stacspec.org/v1.0.0/collections/extensions/transaction"
+)
+
+func newTransactionClient(t *testing.T, api *transactionAPI) *client.Client {
+	server := httptest.NewServer(api.handler())
+	t.Cleanup(server.Close)
+
+	c, err := client.New(context.Background(), server.URL+"/")
+	require.NoError(t, err)
+	return c
+}
+
+func testItem(id string) *stac.Item {
+	return &stac.Item{
+		Version:    "1.0.0",
+		Id:         id,
+		Collection: "test-collection",
+		Geometry:   map[string]any{"type": "Point", "coordinates": []float64{1, 2}},
+		Properties: map[string]any{"datetime": "2024-01-01T00:00:00Z", "gsd": 10},
+		Links:      []*stac.Link{},
+		Assets:     map[string]*stac.Asset{},
+	}
+}
+
+func TestItemTransactions(t *testing.T) {
+	ctx := context.Background()
+	api := newTransactionAPI(itemTransactionClass)
+	c := newTransactionClient(t, api)
+
+	created, err := c.CreateItem(ctx, testItem("item-1"))
+	require.NoError(t, err)
+	require.NotNil(t, created)
+	assert.Equal(t, "item-1", created.Id)
+	assert.Equal(t, float64(10), created.Properties["gsd"])
+
+	_, err = c.CreateItem(ctx, testItem("item-1"))
+	assert.ErrorIs(t, err, client.ErrConflict)
+
+	update := testItem("item-1")
+	update.Properties["gsd"] = 20
+	updated, err := c.UpdateItem(ctx, update)
+	require.NoError(t, err)
+	assert.Equal(t, float64(20), updated.Properties["gsd"])
+
+	patched, err := c.PatchItem(ctx, "test-collection", "item-1", map[string]any{
+		"properties": map[string]any{"datetime": "2024-02-01T00:00:00Z"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"datetime": "2024-02-01T00:00:00Z"}, patched.Properties)
+
+	require.NoError(t, c.DeleteItem(ctx, "test-collection", "item-1"))
+
+	err = c.DeleteItem(ctx, "test-collection", "item-1")
+	assert.ErrorIs(t, err, client.ErrNotFound)
+
+	assert.Equal(t, []string{
+		"POST /collections/test-collection/items",
+		"POST /collections/test-collection/items",
+		"PUT /collections/test-collection/items/item-1",
+		"PATCH /collections/test-collection/items/item-1",
+		"DELETE /collections/test-collection/items/item-1",
+		"DELETE /collections/test-collection/items/item-1",
+	}, api.requests)
+}
+
+func TestCreateItemInvalid(t *testing.T) {
+	c := newTransactionClient(t, newTransactionAPI(itemTransactionClass))
+
+	_, err := c.CreateItem(context.Background(), testItem(""))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, client.ErrInvalid)
+
+	apiErr := &client.APIError{}
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, "InvalidParameterValue", apiErr.Code)
+	assert.Equal(t, "missing id", apiErr.Description)
+	assert.Contains(t, apiErr.Error(), "missing id")
+}
+
+func TestCreateItemAccepted(t *testing.T) {
+	api := newTransactionAPI(itemTransactionClass)
+	api.async = true
+	c := newTransactionClient(t, api)
+
+	created, err := c.CreateItem(context.Background(), testItem("item-1"))
+	require.NoError(t, err)
+	assert.Nil(t, created)
+	assert.Contains(t, api.items, "test-collection/item-1")
+}
+
+func TestCollectionTransactions(t *testing.T) {
+	ctx := context.Background()
+	api := newTransactionAPI(collectionTransactionClass)
+	c := newTransactionClient(t, api)
+
+	collection := &stac.Collection{
+		Version:     "1.0.0",
+		Id:          "test-collection",
+		Description: "A test collection",
+		License:     "CC-BY-4.0",
+		Extent: &stac.Extent{
+			Spatial:  &stac.SpatialExtent{Bbox: [][]float64{{-180, -90, 180, 90}}},
+			Temporal: &stac.TemporalExtent{Interval: [][]any{{"2024-01-01T00:00:00Z", nil}}},
+		},
+		Links: []*stac.Link{},
+	}
+
+	created, err := c.CreateCollection(ctx, collection)
+	require.NoError(t, err)
+	assert.Equal(t, "A test collection", created.Description)
+
+	collection.Description = "An updated collection"
+	updated, err := c.UpdateCollection(ctx, collection)
+	require.NoError(t, err)
+	assert.Equal(t, "An updated collection", updated.Description)
+
+	patched, err := c.PatchCollection(ctx, "test-collection", map[string]any{"title": "Test"})
+	require.NoError(t, err)
+	assert.Equal(t, "Test", patched.Title)
+
+	require.NoError(t, c.DeleteCollection(ctx, "test-collection"))
+	assert.Empty(t, api.collections)
+
+	_, err = c.PatchCollection(ctx, "test-collection", map[string]any{"title": "Missing"})
+	assert.ErrorIs(t, err, client.ErrNotFound)
+
+	// item transactions are not advertised
+	_, err = c.CreateItem(ctx, testItem("item-1"))
+	assert.ErrorIs(t, err, client.ErrTransactionsNotSupported)
+}
+
+func TestTransactionsNotSupported(t *testing.T) {
+	ctx := context.Background()
+	api := newTransactionAPI("https://api.stacspec.org/v1.0.0/core")
+	c := newTransactionClient(t, api)
+
+	_, err := c.CreateItem(ctx, testItem("item-1"))
+	assert.ErrorIs(t, err, client.ErrTransactionsNotSupported)
+
+	err = c.DeleteItem(ctx, "test-collection", "item-1")
+	assert.ErrorIs(t, err, client.ErrTransactionsNotSupported)
+
+	_, err = c.CreateCollection(ctx, &stac.Collection{Id: "test-collection"})
+	assert.ErrorIs(t, err, client.ErrTransactionsNotSupported)
+
+	assert.Empty(t, api.requests)
+}
+
+func TestTransactionNoRetry(t *testing.T) {
+	requests := []string{}
+	mutex := sync.Mutex{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		l := landing("http://" + r.Host)
+		l["conformsTo"] = []string{itemTransactionClass}
+		writeJSON(w, l)
+	})
+	mux.HandleFunc("/collections/", func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mutex.Unlock()
+		writeAPIError(w, http.StatusServiceUnavailable, "Unavailable", "try again later")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ctx := context.Background()
+	c, err := client.New(ctx, server.URL+"/")
+	require.NoError(t, err)
+
+	_, err = c.CreateItem(ctx, testItem("item-1"))
+	apiErr := &client.APIError{}
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+
+	_, err = c.UpdateItem(ctx, testItem("item-1"))
+	require.Error(t, err)
+
+	_, err = c.PatchItem(ctx, "test-collection", "item-1", map[string]any{"title": "Test"})
+	require.Error(t, err)
+
+	assert.Equal(t, []string{
+		"POST /collections/test-collection/items",
+		"PUT /collections/test-collection/items/item-1",
+		"PATCH /collections/test-collection/items/item-1",
+	}, requests)
+}
+
+func TestTransactionsMissingResource(t *testing.T) {
+	ctx := context.Background()
+	api := newTransactionAPI(itemTransactionClass, collectionTransactionClass)
+	c := newTransactionClient(t, api)
+
+	_, err := c.CreateItem(ctx, nil)
+	assert.EqualError(t, err, "missing item")
+
+	_, err = c.UpdateItem(ctx, nil)
+	assert.EqualError(t, err, "missing item")
+
+	_, err = c.CreateCollection(ctx, nil)
+	assert.EqualError(t, err, "missing collection")
+
+	_, err = c.UpdateCollection(ctx, nil)
+	assert.EqualError(t, err, "missing collection")
+
+	assert.Empty(t, api.requests)
+}
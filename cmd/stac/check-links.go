@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/planetlabs/go-stac/checker"
+	"github.com/urfave/cli/v2"
+)
+
+var checkLinksCommand = &cli.Command{
+	Name:        "check-links",
+	Usage:       "Check links and assets in STAC metadata",
+	Description: "Crawls STAC resources and reports on links and asset hrefs that cannot be resolved or have an unexpected media type.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    flagEntry,
+			Usage:   "Path or URL to STAC resource (catalog, collection, or item) to crawl",
+			EnvVars: []string{toEnvVar(flagEntry)},
+		},
+		&cli.BoolFlag{
+			Name:    flagNoAssets,
+			Usage:   "Only check links (not asset hrefs)",
+			EnvVars: []string{toEnvVar(flagNoAssets)},
+		},
+		&cli.BoolFlag{
+			Name:    flagNoRecursion,
+			Usage:   "Visit a single resource",
+			EnvVars: []string{toEnvVar(flagNoRecursion)},
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		entryPath := ctx.String(flagEntry)
+		if entryPath == "" {
+			return cli.Exit(fmt.Sprintf("missing --%s", flagEntry), 1)
+		}
+
+		options := &checker.Options{
+			NoRecursion: ctx.Bool(flagNoRecursion),
+		}
+		if ctx.Bool(flagNoAssets) {
+			options.Filter = func(reference *checker.Reference) bool {
+				return reference.Type == checker.LinkReference
+			}
+		}
+
+		problems := 0
+		handler := func(result *checker.Result) error {
+			if result.Status == checker.StatusOK {
+				return nil
+			}
+			problems += 1
+			fmt.Println(result.String())
+			return nil
+		}
+
+		if err := checker.New(options).Check(context.Background(), entryPath, handler); err != nil {
+			return cli.Exit(fmt.Sprintf("check failed: %s", err), 3)
+		}
+		if problems > 0 {
+			return cli.Exit(fmt.Sprintf("found %d problem(s)", problems), 2)
+		}
+		return nil
+	},
+}
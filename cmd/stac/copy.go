@@ -0,0 +1,491 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/planetlabs/go-stac/cql2"
+	"github.com/planetlabs/go-stac/crawler"
+	"github.com/planetlabs/go-stac/download"
+	"github.com/planetlabs/go-stac/internal/normurl"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/sync/errgroup"
+)
+
+var copyCommand = &cli.Command{
+	Name:        "copy",
+	Usage:       "Copy a catalog to a local directory",
+	Description: "Crawls a static catalog or STAC API and writes each resource to an output directory, rewriting links so the copy is a self-contained catalog.  Items from API pages are written as static item files.  Assets can be downloaded as well.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    flagEntry,
+			Usage:   "Path or URL to STAC resource (catalog, collection, item, or API root) to copy",
+			EnvVars: []string{toEnvVar(flagEntry)},
+		},
+		&cli.StringFlag{
+			Name:    flagOutput,
+			Usage:   "Path to a directory for writing the copy",
+			EnvVars: []string{toEnvVar(flagOutput)},
+		},
+		&cli.StringFlag{
+			Name:    flagFilter,
+			Usage:   "CQL2 text or JSON filter for items to copy",
+			EnvVars: []string{toEnvVar(flagFilter)},
+		},
+		&cli.BoolFlag{
+			Name:    flagAssets,
+			Usage:   "Download assets",
+			EnvVars: []string{toEnvVar(flagAssets)},
+		},
+		&cli.IntFlag{
+			Name:    flagConcurrency,
+			Usage:   "Number of assets to download concurrently",
+			Value:   4,
+			EnvVars: []string{toEnvVar(flagConcurrency)},
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		entry := ctx.String(flagEntry)
+		if entry == "" {
+			return cli.Exit(fmt.Sprintf("missing --%s", flagEntry), 1)
+		}
+
+		output := ctx.String(flagOutput)
+		if output == "" {
+			return cli.Exit(fmt.Sprintf("missing --%s", flagOutput), 1)
+		}
+
+		options := &copyOptions{
+			output:      output,
+			assets:      ctx.Bool(flagAssets),
+			concurrency: ctx.Int(flagConcurrency),
+		}
+
+		if value := ctx.String(flagFilter); value != "" {
+			filter, err := cql2.ParseFilter(value)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("invalid --%s: %s", flagFilter, err), 1)
+			}
+			options.filter = filter
+		}
+
+		if err := copyCatalog(context.Background(), entry, options); err != nil {
+			return cli.Exit(fmt.Sprintf("copy failed: %s", err), 1)
+		}
+		return nil
+	},
+}
+
+type copyOptions struct {
+	output      string
+	filter      crawler.ItemFilter
+	assets      bool
+	concurrency int
+}
+
+// copiedResource is a resource that has been written to the output directory.
+type copiedResource struct {
+	location     string
+	output       string
+	resourceType crawler.ResourceType
+	id           string
+	collection   string
+	parent       *copiedResource
+	children     []*copiedResource
+}
+
+// droppedRels are link relations that are regenerated for the copy or that only
+// make sense for an API.
+var droppedRels = map[string]bool{
+	"self":         true,
+	"root":         true,
+	"parent":       true,
+	"child":        true,
+	"item":         true,
+	"collection":   true,
+	"items":        true,
+	"children":     true,
+	"data":         true,
+	"conformance":  true,
+	"queryables":   true,
+	"search":       true,
+	"service-desc": true,
+	"service-doc":  true,
+	"next":         true,
+	"prev":         true,
+	"previous":     true,
+	"first":        true,
+	"last":         true,
+
+	"http://www.opengis.net/def/rel/ogc/1.0/queryables": true,
+}
+
+type copier struct {
+	options *copyOptions
+	entry   string
+	mutex   sync.Mutex
+
+	// copied resources by location
+	copied map[string]*copiedResource
+
+	// locations by output path
+	outputs map[string]string
+
+	// location of the resource that first linked to each location
+	linkedBy map[string]string
+
+	// output paths of assets by source location
+	assets map[string]string
+
+	// source locations of assets by output path
+	claimed map[string]string
+
+	// output paths of assets that have been scheduled for download
+	downloading map[string]bool
+}
+
+func copyCatalog(ctx context.Context, entry string, options *copyOptions) error {
+	c := &copier{
+		options:  options,
+		copied:   map[string]*copiedResource{},
+		outputs:  map[string]string{},
+		linkedBy: map[string]string{},
+		assets:   map[string]string{},
+		claimed:  map[string]string{},
+
+		downloading: map[string]bool{},
+	}
+
+	crawlOptions := &crawler.Options{ItemFilter: options.filter}
+	if err := crawler.Crawl(entry, c.visit, crawlOptions); err != nil {
+		return err
+	}
+	if len(c.copied) == 0 {
+		return fmt.Errorf("nothing to copy from %s", entry)
+	}
+
+	c.connect()
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	if options.concurrency > 0 {
+		group.SetLimit(options.concurrency)
+	}
+
+	resources := c.sorted()
+	for _, resource := range resources {
+		downloads, err := c.rewrite(resource)
+		if err != nil {
+			_ = group.Wait()
+			return err
+		}
+		for _, asset := range downloads {
+			group.Go(func() error {
+				return download.File(groupCtx, nil, nil, asset.source, asset.target, asset.expected)
+			})
+		}
+	}
+	return group.Wait()
+}
+
+// visit writes a crawled resource to the output directory as is.  Links are
+// rewritten after the crawl when all copied resources are known.
+func (c *copier) visit(resource crawler.Resource, info *crawler.ResourceInfo) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.entry == "" {
+		c.entry = info.Entry
+	}
+
+	outPath, err := copyPath(info.Entry, info.Location, resource)
+	if err != nil {
+		return err
+	}
+	if existing, ok := c.outputs[outPath]; ok {
+		return fmt.Errorf("both %s and %s would be written to %s", existing, info.Location, outPath)
+	}
+	c.outputs[outPath] = info.Location
+
+	id, _ := resource["id"].(string)
+	collection, _ := resource["collection"].(string)
+	c.copied[info.Location] = &copiedResource{
+		location:     info.Location,
+		output:       outPath,
+		resourceType: resource.Type(),
+		id:           id,
+		collection:   collection,
+	}
+
+	loc, err := normurl.New(info.Location)
+	if err != nil {
+		return err
+	}
+	for _, link := range resource.Links() {
+		if link["rel"] != "child" && link["rel"] != "item" {
+			continue
+		}
+		linkLoc, err := loc.Resolve(link["href"])
+		if err != nil {
+			continue
+		}
+		if _, ok := c.linkedBy[linkLoc.String()]; !ok {
+			c.linkedBy[linkLoc.String()] = info.Location
+		}
+	}
+
+	return writeResource(filepath.Join(c.options.output, filepath.FromSlash(outPath)), resource)
+}
+
+// connect determines the parent of each copied resource.  Resources are
+// connected to the resource that linked to them.  Items from an API are
+// connected to their collection, and anything else to the entry.
+func (c *copier) connect() {
+	collections := map[string]*copiedResource{}
+	for _, resource := range c.copied {
+		if resource.resourceType == crawler.Collection {
+			collections[resource.id] = resource
+		}
+	}
+
+	for _, resource := range c.sorted() {
+		if resource.location == c.entry {
+			continue
+		}
+		if parent, ok := c.copied[c.linkedBy[resource.location]]; ok {
+			resource.parent = parent
+		} else if collection, ok := collections[resource.collection]; ok && resource.resourceType == crawler.Item {
+			resource.parent = collection
+		} else if root, ok := c.copied[c.entry]; ok {
+			resource.parent = root
+		}
+		if resource.parent != nil {
+			resource.parent.children = append(resource.parent.children, resource)
+		}
+	}
+}
+
+func (c *copier) sorted() []*copiedResource {
+	resources := make([]*copiedResource, 0, len(c.copied))
+	for _, resource := range c.copied {
+		resources = append(resources, resource)
+	}
+	slices.SortFunc(resources, func(a, b *copiedResource) int {
+		return strings.Compare(a.output, b.output)
+	})
+	return resources
+}
+
+// assetDownload is an asset to copy to a target path.
+type assetDownload struct {
+	source   string
+	target   string
+	expected *download.Expected
+}
+
+// rewrite updates the links and asset hrefs of a copied resource.  If assets are
+// being copied, the assets to download are returned.
+func (c *copier) rewrite(copied *copiedResource) ([]*assetDownload, error) {
+	outFile := filepath.Join(c.options.output, filepath.FromSlash(copied.output))
+	data, err := os.ReadFile(outFile)
+	if err != nil {
+		return nil, err
+	}
+	resource := crawler.Resource{}
+	if err := json.Unmarshal(data, &resource); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", outFile, err)
+	}
+
+	loc, err := normurl.New(copied.location)
+	if err != nil {
+		return nil, err
+	}
+
+	links := []any{}
+	if root, ok := c.copied[c.entry]; ok {
+		links = append(links, map[string]any{"rel": "root", "href": normurl.Rel(copied.output, root.output), "type": "application/json"})
+	}
+	if copied.parent != nil {
+		links = append(links, map[string]any{"rel": "parent", "href": normurl.Rel(copied.output, copied.parent.output), "type": "application/json"})
+		if copied.resourceType == crawler.Item && copied.parent.resourceType == crawler.Collection {
+			links = append(links, map[string]any{"rel": "collection", "href": normurl.Rel(copied.output, copied.parent.output), "type": "application/json"})
+		}
+	}
+	for _, child := range copied.children {
+		if child.resourceType == crawler.Item {
+			links = append(links, map[string]any{"rel": "item", "href": normurl.Rel(copied.output, child.output), "type": "application/geo+json"})
+		} else {
+			links = append(links, map[string]any{"rel": "child", "href": normurl.Rel(copied.output, child.output), "type": "application/json"})
+		}
+	}
+
+	values, _ := resource["links"].([]any)
+	for _, value := range values {
+		link, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		rel, _ := link["rel"].(string)
+		if droppedRels[rel] {
+			continue
+		}
+		if href, ok := link["href"].(string); ok {
+			link["href"] = c.resolve(loc, copied, href)
+		}
+		links = append(links, link)
+	}
+	resource["links"] = links
+
+	// conformance classes describe an API, not the static copy
+	delete(resource, "conformsTo")
+
+	downloads := []*assetDownload{}
+	if assets, ok := resource["assets"].(map[string]any); ok {
+		for key, value := range assets {
+			asset, ok := value.(map[string]any)
+			if !ok {
+				continue
+			}
+			href, ok := asset["href"].(string)
+			if !ok {
+				continue
+			}
+			assetLoc, err := loc.Resolve(href)
+			if err != nil {
+				asset["href"] = href
+				continue
+			}
+			if !c.options.assets {
+				asset["href"] = assetLoc.String()
+				continue
+			}
+			target := c.assetPath(copied, key, assetLoc.String())
+			asset["href"] = normurl.Rel(copied.output, target)
+			if !c.downloading[target] {
+				c.downloading[target] = true
+				downloads = append(downloads, &assetDownload{
+					source:   assetLoc.String(),
+					target:   filepath.Join(c.options.output, filepath.FromSlash(target)),
+					expected: assetExpected(asset),
+				})
+			}
+		}
+	}
+
+	return downloads, writeResource(outFile, resource)
+}
+
+// resolve returns the href for a link in the copy.  Links to copied resources
+// are made relative and others are made absolute.
+func (c *copier) resolve(loc *normurl.Locator, copied *copiedResource, href string) string {
+	linkLoc, err := loc.Resolve(href)
+	if err != nil {
+		return href
+	}
+	if target, ok := c.copied[linkLoc.String()]; ok {
+		return normurl.Rel(copied.output, target.output)
+	}
+	return linkLoc.String()
+}
+
+// assetPath determines where an asset will be written.  Assets under the
+// entry directory keep their relative path and others are written next to the
+// resource.
+func (c *copier) assetPath(copied *copiedResource, key string, source string) string {
+	if target, ok := c.assets[source]; ok {
+		return target
+	}
+
+	target, ok := relativeLocation(c.entry, source)
+	if !ok {
+		name := download.AssetName(key, source)
+		target = path.Join(path.Dir(copied.output), name)
+		if other, taken := c.claimed[target]; taken && other != source {
+			target = path.Join(path.Dir(copied.output), download.SafeName(key)+"-"+name)
+		}
+	}
+
+	c.assets[source] = target
+	c.claimed[target] = source
+	return target
+}
+
+// copyPath determines the output path (relative to the output directory) for a
+// crawled resource.  Resources under the directory of the entry keep their
+// relative path.  Others (e.g. from an API) are named by identifier.
+func copyPath(entry string, location string, resource crawler.Resource) (string, error) {
+	if rel, ok := relativeLocation(entry, location); ok && path.Ext(rel) == ".json" {
+		return rel, nil
+	}
+
+	if location == entry {
+		if resource.Type() == crawler.Collection {
+			return "collection.json", nil
+		}
+		return "catalog.json", nil
+	}
+
+	id, _ := resource["id"].(string)
+	if id == "" {
+		return "", fmt.Errorf("missing id for %s", location)
+	}
+	id = download.SafeName(id)
+
+	switch resource.Type() {
+	case crawler.Collection:
+		return path.Join(id, "collection.json"), nil
+	case crawler.Catalog:
+		return path.Join(id, "catalog.json"), nil
+	case crawler.Item:
+		collection, _ := resource["collection"].(string)
+		if collection == "" {
+			collection = "items"
+		}
+		return path.Join(download.SafeName(collection), id, id+".json"), nil
+	}
+	return "", fmt.Errorf("unexpected resource type for %s", location)
+}
+
+// relativeLocation returns the slash-separated path of a location relative to
+// the directory of the entry (and false if it is not under the directory).
+func relativeLocation(entry string, location string) (string, bool) {
+	entryUrl, entryErr := url.Parse(entry)
+	locationUrl, locationErr := url.Parse(location)
+	if entryErr != nil || locationErr != nil {
+		return "", false
+	}
+
+	if entryUrl.IsAbs() || locationUrl.IsAbs() {
+		if entryUrl.Scheme != locationUrl.Scheme || entryUrl.Host != locationUrl.Host || locationUrl.RawQuery != "" {
+			return "", false
+		}
+		rel, ok := strings.CutPrefix(locationUrl.Path, path.Dir(entryUrl.Path)+"/")
+		if !ok || rel == "" {
+			return "", false
+		}
+		return path.Clean(rel), true
+	}
+
+	rel, err := filepath.Rel(filepath.Dir(entry), location)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// assetExpected gets the expected checksum and size of an asset.
+func assetExpected(asset map[string]any) *download.Expected {
+	expected := &download.Expected{}
+	expected.Checksum, _ = asset["file:checksum"].(string)
+	if size, ok := asset["file:size"].(float64); ok {
+		expected.Size = int64(size)
+	}
+	return expected
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/planetlabs/go-stac/cql2"
+	"github.com/planetlabs/go-stac/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readCopied(t *testing.T, dir string, name string) map[string]any {
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	require.NoError(t, err)
+	resource := map[string]any{}
+	require.NoError(t, json.Unmarshal(data, &resource))
+	return resource
+}
+
+func copiedLinks(resource map[string]any) map[string][]string {
+	hrefs := map[string][]string{}
+	for _, value := range resource["links"].([]any) {
+		link := value.(map[string]any)
+		rel := link["rel"].(string)
+		hrefs[rel] = append(hrefs[rel], link["href"].(string))
+	}
+	return hrefs
+}
+
+func copiedFiles(t *testing.T, dir string) []string {
+	files := []string{}
+	err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		files = append(files, filepath.ToSlash(rel))
+		return err
+	})
+	require.NoError(t, err)
+	return files
+}
+
+func TestCopyStatic(t *testing.T) {
+	output := t.TempDir()
+	filter, err := cql2.ParseFilter(`"eo:cloud_cover" < 20`)
+	require.NoError(t, err)
+
+	err = copyCatalog(context.Background(), "../../server/testdata/catalog.json", &copyOptions{output: output, filter: filter})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"catalog.json",
+		"collection-a/a1/a1.json",
+		"collection-a/a3/a3.json",
+		"collection-a/collection.json",
+		"collection-b/collection.json",
+	}, copiedFiles(t, output))
+
+	assert.Equal(t, map[string][]string{
+		"root":   {"../catalog.json"},
+		"parent": {"../catalog.json"},
+		"item":   {"./a1/a1.json", "./a3/a3.json"},
+	}, copiedLinks(readCopied(t, output, "collection-a/collection.json")))
+
+	item := readCopied(t, output, "collection-a/a3/a3.json")
+	assert.Equal(t, map[string][]string{
+		"root":       {"../../catalog.json"},
+		"parent":     {"../collection.json"},
+		"collection": {"../collection.json"},
+	}, copiedLinks(item))
+
+	// without --assets, asset hrefs point to the original location
+	href := item["assets"].(map[string]any)["data"].(map[string]any)["href"].(string)
+	abs, err := filepath.Abs("../../server/testdata/collection-a/a3/data.tif")
+	require.NoError(t, err)
+	assert.Equal(t, abs, href)
+}
+
+func TestCopyAPI(t *testing.T) {
+	s, err := server.New("../../server/testdata/catalog.json")
+	require.NoError(t, err)
+	api := httptest.NewServer(s)
+	defer api.Close()
+
+	output := t.TempDir()
+	require.NoError(t, copyCatalog(context.Background(), api.URL+"/", &copyOptions{output: output}))
+
+	assert.Equal(t, []string{
+		"catalog.json",
+		"collection-a/a1/a1.json",
+		"collection-a/a2/a2.json",
+		"collection-a/a3/a3.json",
+		"collection-a/collection.json",
+		"collection-b/b1/b1.json",
+		"collection-b/collection.json",
+	}, copiedFiles(t, output))
+
+	root := readCopied(t, output, "catalog.json")
+	assert.NotContains(t, root, "conformsTo")
+	assert.Equal(t, map[string][]string{
+		"root":  {"./catalog.json"},
+		"child": {"./collection-a/collection.json", "./collection-b/collection.json"},
+	}, copiedLinks(root))
+
+	assert.Equal(t, map[string][]string{
+		"root":       {"../../catalog.json"},
+		"parent":     {"../collection.json"},
+		"collection": {"../collection.json"},
+	}, copiedLinks(readCopied(t, output, "collection-b/b1/b1.json")))
+}
+
+func TestCopyAssets(t *testing.T) {
+	mutex := &sync.Mutex{}
+	requests := []string{}
+	assets := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requests = append(requests, r.URL.Path)
+		mutex.Unlock()
+		http.ServeContent(w, r, r.URL.Path, time.Time{}, strings.NewReader("hello world"))
+	}))
+	defer assets.Close()
+
+	source := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(source, "catalog.json"), []byte(`{
+		"type": "Catalog",
+		"stac_version": "1.0.0",
+		"id": "test",
+		"description": "Test catalog",
+		"links": [{"rel": "item", "href": "./item/item.json"}]
+	}`), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(source, "item"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(source, "item", "local.txt"), []byte("local"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(source, "item", "item.json"), []byte(`{
+		"type": "Feature",
+		"stac_version": "1.0.0",
+		"id": "item",
+		"geometry": null,
+		"properties": {"datetime": "2024-01-01T00:00:00Z"},
+		"links": [{"rel": "parent", "href": "../catalog.json"}],
+		"assets": {
+			"local": {"href": "./local.txt", "file:size": 5},
+			"remote": {
+				"href": "`+assets.URL+`/data/remote.txt",
+				"file:checksum": "1220b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+			}
+		}
+	}`), 0644))
+
+	output := t.TempDir()
+	options := &copyOptions{output: output, assets: true, concurrency: 2}
+	require.NoError(t, copyCatalog(context.Background(), filepath.Join(source, "catalog.json"), options))
+
+	assert.Equal(t, []string{
+		"catalog.json",
+		"item/item.json",
+		"item/local.txt",
+		"item/remote.txt",
+	}, copiedFiles(t, output))
+
+	item := readCopied(t, output, "item/item.json")
+	itemAssets := item["assets"].(map[string]any)
+	assert.Equal(t, "./local.txt", itemAssets["local"].(map[string]any)["href"])
+	assert.Equal(t, "./remote.txt", itemAssets["remote"].(map[string]any)["href"])
+
+	data, err := os.ReadFile(filepath.Join(output, "item", "remote.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(data))
+	assert.Equal(t, []string{"/data/remote.txt"}, requests)
+
+	// verified assets are not downloaded again
+	require.NoError(t, copyCatalog(context.Background(), filepath.Join(source, "catalog.json"), options))
+	assert.Equal(t, []string{"/data/remote.txt"}, requests)
+}
+
+func TestCopyChecksumMismatch(t *testing.T) {
+	assets := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("unexpected"))
+	}))
+	defer assets.Close()
+
+	source := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(source, "item.json"), []byte(`{
+		"type": "Feature",
+		"stac_version": "1.0.0",
+		"id": "item",
+		"geometry": null,
+		"properties": {"datetime": "2024-01-01T00:00:00Z"},
+		"links": [],
+		"assets": {
+			"data": {
+				"href": "`+assets.URL+`/data.txt",
+				"file:checksum": "1220b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+			}
+		}
+	}`), 0644))
+
+	output := t.TempDir()
+	err := copyCatalog(context.Background(), filepath.Join(source, "item.json"), &copyOptions{output: output, assets: true})
+	assert.ErrorContains(t, err, "checksum mismatch")
+	assert.NoFileExists(t, filepath.Join(output, "data.txt"))
+}
@@ -12,8 +12,16 @@ The stac program is a command line tool for working with STAC resources.
 
 	COMMANDS:
 		validate             Validate STAC metadata
+		lint                 Check STAC metadata against best practices
+		check-links          Check links and assets in STAC metadata
+		search               Search a STAC API for items
+		serve                Serve a static catalog as a STAC API
 		stats                Generate STAC statistics
+		summarize            Generate collection summaries
 		make-links-absolute  Rewrite links in STAC metadata
+		make-links-relative  Rewrite links in STAC metadata to be relative
+		copy                 Copy a catalog to a local directory
+		download             Download item assets
 		format               Format STAC metadata
 		version              Print build information
 		help, h              Shows a list of commands or help for one command
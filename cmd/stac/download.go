@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/client"
+	"github.com/planetlabs/go-stac/cql2"
+	"github.com/planetlabs/go-stac/crawler"
+	"github.com/planetlabs/go-stac/download"
+	"github.com/planetlabs/go-stac/internal/fetch"
+	"github.com/planetlabs/go-stac/internal/normurl"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/sync/errgroup"
+)
+
+var downloadCommand = &cli.Command{
+	Name:        "download",
+	Usage:       "Download item assets",
+	Description: "Downloads the assets of items from a file (an item or a feature collection), a crawl of a catalog, or a STAC API search.  Each item is written to {output}/{collection}/{item}/{item}.json with asset hrefs pointing to the downloaded files.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    flagEntry,
+			Usage:   "Path or URL to an item, feature collection, catalog, collection, or (with --search) STAC API",
+			EnvVars: []string{toEnvVar(flagEntry)},
+		},
+		&cli.StringFlag{
+			Name:    flagOutput,
+			Usage:   "Path to a directory for writing items and assets",
+			EnvVars: []string{toEnvVar(flagOutput)},
+		},
+		&cli.StringSliceFlag{
+			Name:    flagKey,
+			Usage:   "Asset key to download (can be repeated)",
+			EnvVars: []string{toEnvVar(flagKey)},
+		},
+		&cli.StringSliceFlag{
+			Name:    flagRole,
+			Usage:   "Asset role to download (can be repeated)",
+			EnvVars: []string{toEnvVar(flagRole)},
+		},
+		&cli.StringSliceFlag{
+			Name:    flagMediaType,
+			Usage:   "Asset media type to download (can be repeated)",
+			EnvVars: []string{toEnvVar(flagMediaType)},
+		},
+		&cli.IntFlag{
+			Name:    flagConcurrency,
+			Usage:   "Number of assets to download concurrently",
+			Value:   4,
+			EnvVars: []string{toEnvVar(flagConcurrency)},
+		},
+		&cli.StringFlag{
+			Name:    flagFilter,
+			Usage:   "CQL2 filter expression as text or JSON",
+			EnvVars: []string{toEnvVar(flagFilter)},
+		},
+		&cli.BoolFlag{
+			Name:    flagSearch,
+			Usage:   "Search the STAC API at the entry URL for items",
+			EnvVars: []string{toEnvVar(flagSearch)},
+		},
+		&cli.StringFlag{
+			Name:    flagBbox,
+			Usage:   "Bounding box for search as comma-separated numbers (e.g. -122.5,37.5,-122,38)",
+			EnvVars: []string{toEnvVar(flagBbox)},
+		},
+		&cli.StringFlag{
+			Name:    flagIntersects,
+			Usage:   "Path to a GeoJSON file with a geometry or feature that items must intersect (with --search)",
+			EnvVars: []string{toEnvVar(flagIntersects)},
+		},
+		&cli.StringFlag{
+			Name:    flagDatetime,
+			Usage:   "Datetime or interval for search (e.g. 2024-01-01T00:00:00Z/..)",
+			EnvVars: []string{toEnvVar(flagDatetime)},
+		},
+		&cli.StringSliceFlag{
+			Name:    flagCollections,
+			Usage:   "Collection identifier for search (can be repeated)",
+			EnvVars: []string{toEnvVar(flagCollections)},
+		},
+		&cli.IntFlag{
+			Name:    flagMaxItems,
+			Usage:   "Maximum number of items to download from a search (0 for no limit)",
+			EnvVars: []string{toEnvVar(flagMaxItems)},
+		},
+		&cli.IntFlag{
+			Name:    flagLimit,
+			Usage:   "Number of items to request per page when searching",
+			EnvVars: []string{toEnvVar(flagLimit)},
+		},
+		&cli.StringSliceFlag{
+			Name:    flagHeader,
+			Usage:   "Header to include with requests as <name>: <value> (can be repeated)",
+			EnvVars: []string{toEnvVar(flagHeader)},
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		entry := ctx.String(flagEntry)
+		if entry == "" {
+			return cli.Exit(fmt.Sprintf("missing --%s", flagEntry), 1)
+		}
+
+		output := ctx.String(flagOutput)
+		if output == "" {
+			return cli.Exit(fmt.Sprintf("missing --%s", flagOutput), 1)
+		}
+
+		header, err := parseHeaders(ctx.StringSlice(flagHeader))
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+
+		downloader := download.New(output, &download.Options{
+			Keys:        splitValues(ctx.StringSlice(flagKey)),
+			Roles:       splitValues(ctx.StringSlice(flagRole)),
+			MediaTypes:  splitValues(ctx.StringSlice(flagMediaType)),
+			Concurrency: ctx.Int(flagConcurrency),
+			Header:      header,
+		})
+
+		if ctx.Bool(flagSearch) {
+			params, err := searchParams(ctx)
+			if err != nil {
+				return cli.Exit(err.Error(), 1)
+			}
+
+			c, err := client.New(context.Background(), entry, &client.Options{Header: header})
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to load landing page: %s", err), 1)
+			}
+
+			if err := downloadSearch(context.Background(), c, params, ctx.Int(flagMaxItems), downloader, ctx.Int(flagConcurrency)); err != nil {
+				return cli.Exit(fmt.Sprintf("download failed: %s", err), 1)
+			}
+			return nil
+		}
+
+		var filter *cql2.Filter
+		if value := ctx.String(flagFilter); value != "" {
+			filter, err = cql2.ParseFilter(value)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("invalid --%s: %s", flagFilter, err), 1)
+			}
+		}
+
+		if err := downloadEntry(context.Background(), entry, header, filter, downloader); err != nil {
+			return cli.Exit(fmt.Sprintf("download failed: %s", err), 1)
+		}
+		return nil
+	},
+}
+
+func downloadItem(ctx context.Context, downloader *download.Downloader, item *stac.Item, location string) error {
+	if err := downloader.Item(ctx, item, location); err != nil {
+		return err
+	}
+	return downloader.Save(item)
+}
+
+// downloadSearch downloads the assets of items from a search.  Items are
+// processed concurrently (while the downloader limits concurrent downloads).
+func downloadSearch(ctx context.Context, c *client.Client, params *client.SearchParams, maxItems int, downloader *download.Downloader, concurrency int) error {
+	group, groupCtx := errgroup.WithContext(ctx)
+	if concurrency > 0 {
+		group.SetLimit(concurrency)
+	}
+
+	count := 0
+	for item, err := range c.Search(groupCtx, params) {
+		if err != nil {
+			if groupErr := group.Wait(); groupErr != nil {
+				return groupErr
+			}
+			return err
+		}
+		group.Go(func() error {
+			return downloadItem(groupCtx, downloader, item, "")
+		})
+		count += 1
+		if maxItems > 0 && count >= maxItems {
+			break
+		}
+	}
+	return group.Wait()
+}
+
+// downloadEntry downloads the assets of items in a feature collection or of
+// items found by crawling the entry.
+func downloadEntry(ctx context.Context, entry string, header http.Header, filter *cql2.Filter, downloader *download.Downloader) error {
+	loc, err := entryLocation(entry)
+	if err != nil {
+		return err
+	}
+
+	value := map[string]any{}
+	if loc.IsFilepath() {
+		data, err := os.ReadFile(loc.String())
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", entry, err)
+		}
+	} else {
+		request := &fetch.Request{Location: loc.String(), Header: header}
+		if err := fetch.JSON(ctx, fetch.DefaultClient, request, &value); err != nil {
+			return err
+		}
+	}
+
+	if value["type"] == "FeatureCollection" {
+		return downloadFeatures(ctx, loc.String(), value, filter, downloader)
+	}
+
+	options := &crawler.Options{Header: header}
+	if filter != nil {
+		options.ItemFilter = filter
+	}
+
+	visitor := func(resource crawler.Resource, info *crawler.ResourceInfo) error {
+		if resource.Type() != crawler.Item {
+			return nil
+		}
+		data, err := json.Marshal(resource)
+		if err != nil {
+			return err
+		}
+		item := &stac.Item{}
+		if err := json.Unmarshal(data, item); err != nil {
+			return fmt.Errorf("failed to parse item %s: %w", info.Location, err)
+		}
+		return downloadItem(ctx, downloader, item, info.Location)
+	}
+
+	return crawler.Crawl(loc.String(), visitor, options)
+}
+
+func downloadFeatures(ctx context.Context, location string, value map[string]any, filter *cql2.Filter, downloader *download.Downloader) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	collection := &stac.ItemsList{}
+	if err := json.Unmarshal(data, collection); err != nil {
+		return fmt.Errorf("failed to parse items in %s: %w", location, err)
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	for _, item := range collection.Items {
+		if filter != nil {
+			match, err := filter.MatchItem(item)
+			if err != nil {
+				_ = group.Wait()
+				return fmt.Errorf("failed to filter item %s: %w", item.Id, err)
+			}
+			if !match {
+				continue
+			}
+		}
+		group.Go(func() error {
+			return downloadItem(groupCtx, downloader, item, location)
+		})
+	}
+	return group.Wait()
+}
+
+// entryLocation resolves a path (relative to the working directory) or URL.
+func entryLocation(entry string) (*normurl.Locator, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	base, err := normurl.New(fmt.Sprintf("%s%c", wd, os.PathSeparator))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse working directory: %w", err)
+	}
+	return base.Resolve(entry)
+}
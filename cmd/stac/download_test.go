@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/planetlabs/go-stac/cql2"
+	"github.com/planetlabs/go-stac/download"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeDownloadSource(t *testing.T) string {
+	source := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(source, "a.tif"), []byte("a"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(source, "b.tif"), []byte("b"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(source, "catalog.json"), []byte(`{
+		"type": "Catalog",
+		"stac_version": "1.0.0",
+		"id": "test",
+		"description": "Test catalog",
+		"links": [{"rel": "item", "href": "./a.json"}, {"rel": "item", "href": "./b.json"}]
+	}`), 0644))
+	item := func(id string, cloudCover int) string {
+		return `{
+			"type": "Feature",
+			"stac_version": "1.0.0",
+			"id": "` + id + `",
+			"collection": "test",
+			"geometry": null,
+			"properties": {"datetime": "2024-01-01T00:00:00Z", "eo:cloud_cover": ` + strconv.Itoa(cloudCover) + `},
+			"links": [],
+			"assets": {
+				"data": {"href": "./` + id + `.tif", "roles": ["data"]},
+				"thumbnail": {"href": "./` + id + `.png", "roles": ["thumbnail"]}
+			}
+		}`
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(source, "a.json"), []byte(item("a", 1)), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(source, "b.json"), []byte(item("b", 9)), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(source, "items.json"), []byte(`{
+		"type": "FeatureCollection",
+		"features": [`+item("a", 1)+`, `+item("b", 9)+`]
+	}`), 0644))
+	return source
+}
+
+func TestDownloadCrawl(t *testing.T) {
+	source := writeDownloadSource(t)
+	filter, err := cql2.ParseFilter(`"eo:cloud_cover" < 5`)
+	require.NoError(t, err)
+
+	output := t.TempDir()
+	downloader := download.New(output, &download.Options{Roles: []string{"data"}})
+	require.NoError(t, downloadEntry(context.Background(), filepath.Join(source, "catalog.json"), nil, filter, downloader))
+
+	assert.Equal(t, []string{"test/a/a.json", "test/a/a.tif"}, copiedFiles(t, output))
+
+	item := readCopied(t, output, "test/a/a.json")
+	assets := item["assets"].(map[string]any)
+	assert.Equal(t, "./a.tif", assets["data"].(map[string]any)["href"])
+	assert.Equal(t, filepath.Join(source, "a.png"), assets["thumbnail"].(map[string]any)["href"])
+}
+
+func TestDownloadFeatureCollection(t *testing.T) {
+	source := writeDownloadSource(t)
+
+	output := t.TempDir()
+	downloader := download.New(output, &download.Options{Keys: []string{"data"}})
+	require.NoError(t, downloadEntry(context.Background(), filepath.Join(source, "items.json"), nil, nil, downloader))
+
+	assert.Equal(t, []string{"test/a/a.json", "test/a/a.tif", "test/b/b.json", "test/b/b.tif"}, copiedFiles(t, output))
+}
+
+func TestDownloadCrawlHeader(t *testing.T) {
+	source := writeDownloadSource(t)
+	files := http.FileServer(http.Dir(source))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		files.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer secret")
+
+	output := t.TempDir()
+	downloader := download.New(output, &download.Options{Roles: []string{"data"}, Header: header})
+	require.NoError(t, downloadEntry(context.Background(), server.URL+"/catalog.json", header, nil, downloader))
+
+	assert.Equal(t, []string{"test/a/a.json", "test/a/a.tif", "test/b/b.json", "test/b/b.tif"}, copiedFiles(t, output))
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/planetlabs/go-stac/linter"
+	"github.com/urfave/cli/v2"
+)
+
+var lintCommand = &cli.Command{
+	Name:        "lint",
+	Usage:       "Check STAC metadata against best practices",
+	Description: "Crawls STAC resources and reports on deviations from the STAC best practices.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    flagEntry,
+			Usage:   "Path or URL to STAC resource (catalog, collection, or item) to lint",
+			EnvVars: []string{toEnvVar(flagEntry)},
+		},
+		&cli.StringFlag{
+			Name:    flagConfig,
+			Usage:   "Path to a JSON config file (e.g. {\"disable\": [\"file-layout\"]})",
+			EnvVars: []string{toEnvVar(flagConfig)},
+		},
+		&cli.StringSliceFlag{
+			Name:    flagDisable,
+			Usage:   "Identifier of a rule to disable (can be repeated)",
+			EnvVars: []string{toEnvVar(flagDisable)},
+		},
+		&cli.StringFlag{
+			Name:    flagOutput,
+			Usage:   "Path to a directory for writing STAC metadata with fixes applied",
+			EnvVars: []string{toEnvVar(flagOutput)},
+		},
+		&cli.BoolFlag{
+			Name:    flagNoRecursion,
+			Usage:   "Visit a single resource",
+			EnvVars: []string{toEnvVar(flagNoRecursion)},
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		entryPath := ctx.String(flagEntry)
+		if entryPath == "" {
+			return cli.Exit(fmt.Sprintf("missing --%s", flagEntry), 1)
+		}
+
+		config := &linter.Config{}
+		if configPath := ctx.String(flagConfig); configPath != "" {
+			c, err := linter.LoadConfig(configPath)
+			if err != nil {
+				return cli.Exit(err.Error(), 1)
+			}
+			config = c
+		}
+		config.Disable = append(config.Disable, ctx.StringSlice(flagDisable)...)
+
+		outputPath := ctx.String(flagOutput)
+
+		l := linter.New(&linter.Options{
+			NoRecursion: ctx.Bool(flagNoRecursion),
+			Fix:         outputPath != "",
+			Config:      config,
+		})
+
+		remaining := 0
+		handler := func(report *linter.Report) error {
+			for _, problem := range report.Problems {
+				status := ""
+				if problem.Fixed {
+					status = " [fixed]"
+				} else {
+					remaining += 1
+				}
+				fmt.Printf("%s: %s (%s)%s\n", report.Location, problem.Message, problem.Rule.Id, status)
+			}
+
+			if outputPath == "" {
+				return nil
+			}
+
+			outFile, err := outputFile(outputPath, report.Entry, report.Location)
+			if err != nil {
+				return err
+			}
+			return writeResource(outFile, report.Resource)
+		}
+
+		if err := l.Lint(context.Background(), entryPath, handler); err != nil {
+			return cli.Exit(fmt.Sprintf("lint failed: %s", err), 3)
+		}
+		if remaining > 0 {
+			return cli.Exit(fmt.Sprintf("found %d problem(s)", remaining), 2)
+		}
+		return nil
+	},
+}
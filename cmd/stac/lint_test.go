@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func runCommand(command *cli.Command, args ...string) error {
+	app := &cli.App{
+		Commands:       []*cli.Command{command},
+		ExitErrHandler: func(*cli.Context, error) {},
+	}
+	return app.Run(append([]string{"stac", command.Name}, args...))
+}
+
+func TestLint(t *testing.T) {
+	err := runCommand(lintCommand, "--entry", "../../linter/testdata/good/catalog.json")
+	assert.NoError(t, err)
+}
+
+func TestLintProblems(t *testing.T) {
+	err := runCommand(lintCommand, "--entry", "../../linter/testdata/catalog.json")
+	require.Error(t, err)
+	exitErr, ok := err.(cli.ExitCoder)
+	require.True(t, ok)
+	assert.Equal(t, 2, exitErr.ExitCode())
+	assert.Contains(t, err.Error(), "problem(s)")
+}
+
+func TestLintDisable(t *testing.T) {
+	err := runCommand(
+		lintCommand,
+		"--entry", "../../linter/testdata/catalog.json",
+		"--no-recursion",
+		"--disable", "self-link",
+		"--disable", "link-media-type",
+	)
+	assert.NoError(t, err)
+}
+
+func TestLintOutput(t *testing.T) {
+	output := t.TempDir()
+	err := runCommand(lintCommand, "--entry", "../../linter/testdata/catalog.json", "--output", output)
+	require.Error(t, err, "some problems cannot be fixed")
+
+	assert.Equal(t, []string{
+		"Messy-Collection/collection.json",
+		"Messy-Collection/item-1/item.json",
+		"catalog.json",
+	}, copiedFiles(t, output))
+
+	catalog := readCopied(t, output, "catalog.json")
+	links := catalog["links"].([]any)
+	require.Len(t, links, 2)
+	assert.Equal(t, "application/json", links[1].(map[string]any)["type"])
+
+	item := readCopied(t, output, "Messy-Collection/item-1/item.json")
+	thumbnail := item["assets"].(map[string]any)["thumbnail"].(map[string]any)
+	assert.Equal(t, []any{"thumbnail"}, thumbnail["roles"])
+}
+
+func TestLintMissingEntry(t *testing.T) {
+	err := runCommand(lintCommand)
+	assert.EqualError(t, err, "missing --entry")
+}
@@ -15,11 +15,49 @@ import (
 
 const (
 	// validate flags
-	flagSchema = "schema"
+	flagSchema   = "schema"
+	flagSemantic = "semantic"
+	flagApi      = "api"
+	flagWatch    = "watch"
 
-	// make-links-absolute flags
+	// make-links-absolute and make-links-relative flags
 	flagUrl = "url"
 
+	// lint flags
+	flagConfig  = "config"
+	flagDisable = "disable"
+
+	// check-links flags
+	flagNoAssets = "no-assets"
+
+	// search flags
+	flagBbox        = "bbox"
+	flagIntersects  = "intersects"
+	flagDatetime    = "datetime"
+	flagCollections = "collections"
+	flagFilter      = "filter"
+	flagMaxItems    = "max-items"
+	flagLimit       = "limit"
+	flagHeader      = "header"
+	flagFormat      = "format"
+
+	// serve flags
+	flagAddress = "address"
+
+	// copy flags
+	flagAssets      = "assets"
+	flagConcurrency = "concurrency"
+
+	// download flags
+	flagKey       = "key"
+	flagRole      = "role"
+	flagMediaType = "media-type"
+	flagSearch    = "search"
+
+	// summarize flags
+	flagRule      = "rule"
+	flagMaxValues = "max-values"
+
 	// version flags
 	flagVerbose = "verbose"
 
@@ -106,8 +144,16 @@ func main() {
 		Description: "Utilities for working with Spatio-Temporal Asset Catalog (STAC) metadata.",
 		Commands: []*cli.Command{
 			validateCommand,
+			lintCommand,
+			checkLinksCommand,
+			searchCommand,
+			serveCommand,
 			statsCommand,
+			summarizeCommand,
 			absoluteLinksCommand,
+			relativeLinksCommand,
+			copyCommand,
+			downloadCommand,
 			formatCommand,
 			versionCommand,
 		},
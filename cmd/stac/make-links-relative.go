@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/planetlabs/go-stac/crawler"
+	"github.com/planetlabs/go-stac/internal/normurl"
+	"github.com/planetlabs/go-stac/relink"
+	"github.com/urfave/cli/v2"
+)
+
+var relativeLinksCommand = &cli.Command{
+	Name:        "make-links-relative",
+	Usage:       "Rewrite links in STAC metadata to be relative",
+	Description: "Crawls STAC resources and makes links and asset hrefs under the entry directory relative.  Root, parent, and self links are inserted or corrected based on where each resource was found.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    flagEntry,
+			Usage:   "Path to STAC resource (catalog, collection, or item) to crawl",
+			EnvVars: []string{toEnvVar(flagEntry)},
+		},
+		&cli.StringFlag{
+			Name:    flagUrl,
+			Usage:   "Published URL for the STAC entry resource (absolute links under this URL are made relative and used for self links)",
+			EnvVars: []string{toEnvVar(flagUrl)},
+		},
+		&cli.StringFlag{
+			Name:    flagOutput,
+			Usage:   "Path to a directory for writing updated STAC metadata",
+			EnvVars: []string{toEnvVar(flagOutput)},
+		},
+		&cli.BoolFlag{
+			Name:    flagNoAssets,
+			Usage:   "Leave asset hrefs unchanged",
+			EnvVars: []string{toEnvVar(flagNoAssets)},
+		},
+		&cli.BoolFlag{
+			Name:    flagNoRecursion,
+			Usage:   "Visit a single resource",
+			EnvVars: []string{toEnvVar(flagNoRecursion)},
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		entryPath := ctx.String(flagEntry)
+		if entryPath == "" {
+			return fmt.Errorf("missing --%s", flagEntry)
+		}
+
+		outputPath := ctx.String(flagOutput)
+		if outputPath == "" {
+			return fmt.Errorf("missing --%s", flagOutput)
+		}
+
+		options := &relink.Options{
+			URL:    ctx.String(flagUrl),
+			Assets: !ctx.Bool(flagNoAssets),
+		}
+		noRecursion := ctx.Bool(flagNoRecursion)
+
+		// parents maps the location of each child and item to the location of the
+		// resource that linked to it (resources are visited before their children)
+		parents := map[string]string{}
+		mutex := &sync.Mutex{}
+
+		visitor := func(resource crawler.Resource, info *crawler.ResourceInfo) error {
+			loc, err := normurl.New(info.Location)
+			if err != nil {
+				return err
+			}
+
+			mutex.Lock()
+			resourceOptions := *options
+			resourceOptions.Parent = parents[info.Location]
+			for _, link := range resource.Links() {
+				if link["rel"] != "child" && link["rel"] != "item" {
+					continue
+				}
+				linkLoc, err := loc.Resolve(link["href"])
+				if err != nil {
+					continue
+				}
+				parents[linkLoc.String()] = info.Location
+			}
+			mutex.Unlock()
+
+			relResource, err := relink.MakeRelative(resource, info, &resourceOptions)
+			if err != nil {
+				return fmt.Errorf("failed to rewrite links for %s: %w", info.Location, err)
+			}
+
+			outFile, err := outputFile(outputPath, info.Entry, info.Location)
+			if err != nil {
+				return err
+			}
+			if err := writeResource(outFile, relResource); err != nil {
+				return err
+			}
+
+			if noRecursion {
+				return crawler.ErrStopRecursion
+			}
+
+			return nil
+		}
+
+		return crawler.Crawl(entryPath, visitor)
+	},
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestResource(t *testing.T, dir string, name string, resource map[string]any) {
+	data, err := json.Marshal(resource)
+	require.NoError(t, err)
+	resourcePath := filepath.Join(dir, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(resourcePath), 0755))
+	require.NoError(t, os.WriteFile(resourcePath, data, 0644))
+}
+
+func TestMakeLinksRelative(t *testing.T) {
+	input := t.TempDir()
+	writeTestResource(t, input, "catalog.json", map[string]any{
+		"stac_version": "1.0.0",
+		"type":         "Catalog",
+		"id":           "catalog",
+		"description":  "A catalog",
+		"links": []any{
+			map[string]any{"rel": "root", "href": "https://example.com/stac/catalog.json"},
+			map[string]any{"rel": "child", "href": "./collection/collection.json"},
+		},
+	})
+	writeTestResource(t, input, "collection/collection.json", map[string]any{
+		"stac_version": "1.0.0",
+		"type":         "Collection",
+		"id":           "collection",
+		"description":  "A collection",
+		"license":      "CC-BY-4.0",
+		"extent": map[string]any{
+			"spatial":  map[string]any{"bbox": []any{[]any{-180, -90, 180, 90}}},
+			"temporal": map[string]any{"interval": []any{[]any{"2021-01-01T00:00:00Z", nil}}},
+		},
+		"links": []any{
+			map[string]any{"rel": "root", "href": "https://example.com/stac/catalog.json"},
+			map[string]any{"rel": "item", "href": "./item/item.json"},
+			map[string]any{"rel": "license", "href": "https://example.com/license.html"},
+		},
+	})
+	writeTestResource(t, input, "collection/item/item.json", map[string]any{
+		"stac_version": "1.0.0",
+		"type":         "Feature",
+		"id":           "item",
+		"geometry":     map[string]any{"type": "Point", "coordinates": []any{0, 0}},
+		"bbox":         []any{0, 0, 0, 0},
+		"properties":   map[string]any{"datetime": "2021-01-01T00:00:00Z"},
+		"links":        []any{},
+		"assets": map[string]any{
+			"data": map[string]any{"href": "https://example.com/stac/collection/item/data.tif"},
+		},
+	})
+
+	output := t.TempDir()
+	err := runCommand(
+		relativeLinksCommand,
+		"--entry", filepath.Join(input, "catalog.json"),
+		"--url", "https://example.com/stac/catalog.json",
+		"--output", output,
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"catalog.json",
+		"collection/collection.json",
+		"collection/item/item.json",
+	}, copiedFiles(t, output))
+
+	assert.Equal(t, map[string][]string{
+		"root":  {"./catalog.json"},
+		"child": {"./collection/collection.json"},
+		"self":  {"https://example.com/stac/catalog.json"},
+	}, copiedLinks(readCopied(t, output, "catalog.json")))
+
+	assert.Equal(t, map[string][]string{
+		"root":    {"../catalog.json"},
+		"item":    {"./item/item.json"},
+		"license": {"https://example.com/license.html"},
+		"self":    {"https://example.com/stac/collection/collection.json"},
+		"parent":  {"../catalog.json"},
+	}, copiedLinks(readCopied(t, output, "collection/collection.json")))
+
+	item := readCopied(t, output, "collection/item/item.json")
+	assert.Equal(t, map[string][]string{
+		"root":   {"../../catalog.json"},
+		"self":   {"https://example.com/stac/collection/item/item.json"},
+		"parent": {"../collection.json"},
+	}, copiedLinks(item))
+	data := item["assets"].(map[string]any)["data"].(map[string]any)
+	assert.Equal(t, "./data.tif", data["href"])
+}
+
+func TestMakeLinksRelativeNoAssets(t *testing.T) {
+	input := t.TempDir()
+	writeTestResource(t, input, "item.json", map[string]any{
+		"stac_version": "1.0.0",
+		"type":         "Feature",
+		"id":           "item",
+		"geometry":     map[string]any{"type": "Point", "coordinates": []any{0, 0}},
+		"bbox":         []any{0, 0, 0, 0},
+		"properties":   map[string]any{"datetime": "2021-01-01T00:00:00Z"},
+		"links":        []any{},
+		"assets": map[string]any{
+			"data": map[string]any{"href": "https://example.com/stac/data.tif"},
+		},
+	})
+
+	output := t.TempDir()
+	err := runCommand(
+		relativeLinksCommand,
+		"--entry", filepath.Join(input, "item.json"),
+		"--url", "https://example.com/stac/item.json",
+		"--output", output,
+		"--no-assets",
+	)
+	require.NoError(t, err)
+
+	item := readCopied(t, output, "item.json")
+	data := item["assets"].(map[string]any)["data"].(map[string]any)
+	assert.Equal(t, "https://example.com/stac/data.tif", data["href"])
+}
+
+func TestMakeLinksRelativeMissingOutput(t *testing.T) {
+	err := runCommand(relativeLinksCommand, "--entry", "catalog.json")
+	assert.EqualError(t, err, "missing --output")
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// outputFile determines where a crawled resource should be written in an output
+// directory so that the layout relative to the entry is preserved.
+func outputFile(outputPath string, entry string, location string) (string, error) {
+	entryUrl, entryErr := url.Parse(entry)
+	locationUrl, locationErr := url.Parse(location)
+	if entryErr == nil && locationErr == nil && entryUrl.IsAbs() && locationUrl.IsAbs() {
+		rel := strings.TrimPrefix(locationUrl.Path, path.Dir(entryUrl.Path))
+		if rel == locationUrl.Path || path.Ext(rel) == "" {
+			return "", fmt.Errorf("cannot determine output path for %s", location)
+		}
+		return filepath.Join(outputPath, filepath.FromSlash(rel)), nil
+	}
+
+	rel, err := filepath.Rel(filepath.Dir(entry), location)
+	if err != nil {
+		return "", fmt.Errorf("failed to make relative path: %w", err)
+	}
+	if strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("cannot write %s outside of the output directory", location)
+	}
+	return filepath.Join(outputPath, rel), nil
+}
+
+// writeResource writes a resource as formatted JSON, creating the parent
+// directory if needed.
+func writeResource(outFile string, resource map[string]any) error {
+	if err := os.MkdirAll(filepath.Dir(outFile), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	data, err := json.MarshalIndent(orderedMap(resource), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", outFile, err)
+	}
+	if err := os.WriteFile(outFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputFile(t *testing.T) {
+	outFile, err := outputFile("out", "stac/catalog.json", "stac/collection/item.json")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("out", "collection", "item.json"), outFile)
+
+	outFile, err = outputFile("out", "https://example.com/stac/catalog.json", "https://example.com/stac/collection/item.json")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("out", "collection", "item.json"), outFile)
+
+	_, err = outputFile("out", "stac/catalog.json", "other/item.json")
+	assert.ErrorContains(t, err, "outside of the output directory")
+
+	_, err = outputFile("out", "https://example.com/stac/catalog.json", "https://other.com/item.json")
+	assert.ErrorContains(t, err, "cannot determine output path")
+}
+
+func TestWriteResource(t *testing.T) {
+	outFile := filepath.Join(t.TempDir(), "nested", "catalog.json")
+	require.NoError(t, writeResource(outFile, map[string]any{
+		"links":        []any{},
+		"id":           "test",
+		"type":         "Catalog",
+		"stac_version": "1.0.0",
+	}))
+
+	data, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+	expected := `{
+  "stac_version": "1.0.0",
+  "type": "Catalog",
+  "id": "test",
+  "links": []
+}`
+	assert.Equal(t, expected, string(data))
+}
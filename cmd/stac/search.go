@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/planetlabs/go-stac/client"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	formatGeoJSON = "geojson"
+	formatNDJSON  = "ndjson"
+)
+
+var searchFormatValues = []string{formatGeoJSON, formatNDJSON}
+
+var searchCommand = &cli.Command{
+	Name:        "search",
+	Usage:       "Search a STAC API for items",
+	Description: "Searches a STAC API and writes matching items to stdout as a GeoJSON FeatureCollection or newline-delimited JSON.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    flagEntry,
+			Usage:   "URL of the STAC API landing page",
+			EnvVars: []string{toEnvVar(flagEntry)},
+		},
+		&cli.StringFlag{
+			Name:    flagBbox,
+			Usage:   "Bounding box as comma-separated numbers (e.g. -122.5,37.5,-122,38)",
+			EnvVars: []string{toEnvVar(flagBbox)},
+		},
+		&cli.StringFlag{
+			Name:    flagIntersects,
+			Usage:   "Path to a GeoJSON file with a geometry or feature that items must intersect",
+			EnvVars: []string{toEnvVar(flagIntersects)},
+		},
+		&cli.StringFlag{
+			Name:    flagDatetime,
+			Usage:   "Datetime or interval (e.g. 2024-01-01T00:00:00Z/..)",
+			EnvVars: []string{toEnvVar(flagDatetime)},
+		},
+		&cli.StringSliceFlag{
+			Name:    flagCollections,
+			Usage:   "Collection identifier (can be repeated)",
+			EnvVars: []string{toEnvVar(flagCollections)},
+		},
+		&cli.StringFlag{
+			Name:    flagFilter,
+			Usage:   "CQL2 filter expression as text or JSON",
+			EnvVars: []string{toEnvVar(flagFilter)},
+		},
+		&cli.IntFlag{
+			Name:    flagMaxItems,
+			Usage:   "Maximum number of items to return (0 for no limit)",
+			EnvVars: []string{toEnvVar(flagMaxItems)},
+		},
+		&cli.IntFlag{
+			Name:    flagLimit,
+			Usage:   "Number of items to request per page",
+			EnvVars: []string{toEnvVar(flagLimit)},
+		},
+		&cli.StringSliceFlag{
+			Name:    flagHeader,
+			Usage:   "Header to include with requests as <name>: <value> (can be repeated)",
+			EnvVars: []string{toEnvVar(flagHeader)},
+		},
+		&cli.GenericFlag{
+			Name:  flagFormat,
+			Usage: fmt.Sprintf("Output format (%s)", strings.Join(searchFormatValues, ", ")),
+			Value: &Enum{
+				Values:  searchFormatValues,
+				Default: formatGeoJSON,
+			},
+			EnvVars: []string{toEnvVar(flagFormat)},
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		entry := ctx.String(flagEntry)
+		if entry == "" {
+			return cli.Exit(fmt.Sprintf("missing --%s", flagEntry), 1)
+		}
+
+		header, err := parseHeaders(ctx.StringSlice(flagHeader))
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+
+		params, err := searchParams(ctx)
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+
+		c, err := client.New(context.Background(), entry, &client.Options{Header: header})
+		if err != nil {
+			return cli.Exit(fmt.Sprintf("failed to load landing page: %s", err), 1)
+		}
+
+		if err := search(context.Background(), c, params, ctx.Int(flagMaxItems), ctx.String(flagFormat), os.Stdout); err != nil {
+			return cli.Exit(fmt.Sprintf("search failed: %s", err), 1)
+		}
+		return nil
+	},
+}
+
+// searchParams gets the search parameters from the command flags.
+func searchParams(ctx *cli.Context) (*client.SearchParams, error) {
+	params := &client.SearchParams{
+		Collections: splitValues(ctx.StringSlice(flagCollections)),
+		Datetime:    ctx.String(flagDatetime),
+		Limit:       ctx.Int(flagLimit),
+	}
+	if value := ctx.String(flagBbox); value != "" {
+		bbox, err := parseBbox(value)
+		if err != nil {
+			return nil, err
+		}
+		params.Bbox = bbox
+	}
+	if intersectsPath := ctx.String(flagIntersects); intersectsPath != "" {
+		geometry, err := readGeometry(intersectsPath)
+		if err != nil {
+			return nil, err
+		}
+		params.Intersects = geometry
+	}
+	if value := ctx.String(flagFilter); value != "" {
+		filter, err := parseFilter(value)
+		if err != nil {
+			return nil, err
+		}
+		params.Additional = filter
+	}
+	return params, nil
+}
+
+func search(ctx context.Context, c *client.Client, params *client.SearchParams, maxItems int, format string, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	if format == formatGeoJSON {
+		if _, err := io.WriteString(w, `{"type":"FeatureCollection","features":[`); err != nil {
+			return err
+		}
+	}
+
+	count := 0
+	for item, err := range c.Search(ctx, params) {
+		if err != nil {
+			return err
+		}
+		if format == formatGeoJSON && count > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		if err := encoder.Encode(item); err != nil {
+			return fmt.Errorf("failed to encode item %s: %w", item.Id, err)
+		}
+		count += 1
+		if maxItems > 0 && count >= maxItems {
+			break
+		}
+	}
+
+	if format == formatGeoJSON {
+		if _, err := io.WriteString(w, "]}\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitValues allows repeated flags and comma-separated values.
+func splitValues(values []string) []string {
+	split := []string{}
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				split = append(split, part)
+			}
+		}
+	}
+	return split
+}
+
+func parseBbox(value string) ([]float64, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 && len(parts) != 6 {
+		return nil, fmt.Errorf("invalid --%s value %q, expected 4 or 6 numbers", flagBbox, value)
+	}
+	bbox := make([]float64, len(parts))
+	for i, part := range parts {
+		number, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%s value %q: %w", flagBbox, value, err)
+		}
+		bbox[i] = number
+	}
+	return bbox, nil
+}
+
+func parseHeaders(values []string) (http.Header, error) {
+	header := http.Header{}
+	for _, value := range values {
+		name, headerValue, ok := strings.Cut(value, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid --%s value %q, expected <name>: <value>", flagHeader, value)
+		}
+		header.Add(strings.TrimSpace(name), strings.TrimSpace(headerValue))
+	}
+	return header, nil
+}
+
+// parseFilter returns search parameters for a CQL2 text or JSON filter.
+func parseFilter(value string) (map[string]any, error) {
+	if !strings.HasPrefix(strings.TrimSpace(value), "{") {
+		return map[string]any{"filter": value, "filter-lang": "cql2-text"}, nil
+	}
+	filter := map[string]any{}
+	if err := json.Unmarshal([]byte(value), &filter); err != nil {
+		return nil, fmt.Errorf("invalid --%s JSON: %w", flagFilter, err)
+	}
+	return map[string]any{"filter": filter, "filter-lang": "cql2-json"}, nil
+}
+
+// readGeometry reads a GeoJSON geometry or the geometry of a GeoJSON feature.
+func readGeometry(geometryPath string) (map[string]any, error) {
+	data, err := os.ReadFile(geometryPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", geometryPath, err)
+	}
+	geojson := map[string]any{}
+	if err := json.Unmarshal(data, &geojson); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", geometryPath, err)
+	}
+	if geojson["type"] == "Feature" {
+		geometry, ok := geojson["geometry"].(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("feature in %s has no geometry", geometryPath)
+		}
+		return geometry, nil
+	}
+	if _, ok := geojson["coordinates"]; !ok {
+		if _, ok := geojson["geometries"]; !ok {
+			return nil, fmt.Errorf("expected a GeoJSON geometry or feature in %s", geometryPath)
+		}
+	}
+	return geojson, nil
+}
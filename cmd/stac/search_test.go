@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/planetlabs/go-stac/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBbox(t *testing.T) {
+	bbox, err := parseBbox("-122.5, 37.5,-122,38")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{-122.5, 37.5, -122, 38}, bbox)
+
+	_, err = parseBbox("1,2,3")
+	assert.Error(t, err)
+
+	_, err = parseBbox("1,2,3,four")
+	assert.Error(t, err)
+}
+
+func TestParseHeaders(t *testing.T) {
+	header, err := parseHeaders([]string{"Authorization: Bearer token", "X-Extra:value"})
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token", header.Get("Authorization"))
+	assert.Equal(t, "value", header.Get("X-Extra"))
+
+	_, err = parseHeaders([]string{"missing-separator"})
+	assert.Error(t, err)
+}
+
+func TestParseFilter(t *testing.T) {
+	filter, err := parseFilter("eo:cloud_cover < 10")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"filter": "eo:cloud_cover < 10", "filter-lang": "cql2-text"}, filter)
+
+	filter, err = parseFilter(`{"op": "<", "args": [{"property": "eo:cloud_cover"}, 10]}`)
+	require.NoError(t, err)
+	assert.Equal(t, "cql2-json", filter["filter-lang"])
+	assert.Equal(t, "<", filter["filter"].(map[string]any)["op"])
+}
+
+func TestSplitValues(t *testing.T) {
+	assert.Equal(t, []string{"a", "b", "c"}, splitValues([]string{"a,b", " c "}))
+}
+
+func newSearchServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"type":         "Catalog",
+			"stac_version": "1.0.0",
+			"id":           "api",
+			"description":  "Test API",
+			"links":        []map[string]any{{"rel": "search", "href": "/search", "method": "GET"}},
+		})
+	})
+	mux.HandleFunc("GET /search", func(w http.ResponseWriter, r *http.Request) {
+		features := []any{}
+		for i := range 2 {
+			features = append(features, map[string]any{
+				"type":         "Feature",
+				"stac_version": "1.0.0",
+				"id":           fmt.Sprintf("item-%s-%d", r.URL.Query().Get("page"), i),
+				"geometry":     nil,
+				"properties":   map[string]any{"datetime": "2024-01-01T00:00:00Z"},
+				"links":        []any{},
+				"assets":       map[string]any{},
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"type":     "FeatureCollection",
+			"features": features,
+			"links":    []map[string]any{{"rel": "next", "href": "/search?page=next"}},
+		})
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestSearch(t *testing.T) {
+	server := newSearchServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	c, err := client.New(ctx, server.URL+"/")
+	require.NoError(t, err)
+
+	output := &bytes.Buffer{}
+	require.NoError(t, search(ctx, c, &client.SearchParams{}, 3, formatGeoJSON, output))
+
+	collection := map[string]any{}
+	require.NoError(t, json.Unmarshal(output.Bytes(), &collection))
+	assert.Equal(t, "FeatureCollection", collection["type"])
+	assert.Len(t, collection["features"], 3)
+
+	output.Reset()
+	require.NoError(t, search(ctx, c, &client.SearchParams{}, 3, formatNDJSON, output))
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	require.Len(t, lines, 3)
+	for _, line := range lines {
+		item := map[string]any{}
+		require.NoError(t, json.Unmarshal([]byte(line), &item))
+		assert.Equal(t, "Feature", item["type"])
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/planetlabs/go-stac/server"
+	"github.com/urfave/cli/v2"
+)
+
+var serveCommand = &cli.Command{
+	Name:        "serve",
+	Usage:       "Serve a static catalog as a STAC API",
+	Description: "Crawls a local catalog and serves the collections and items with a STAC API (including item search).",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    flagEntry,
+			Usage:   "Path to STAC catalog or collection to serve",
+			EnvVars: []string{toEnvVar(flagEntry)},
+		},
+		&cli.StringFlag{
+			Name:    flagAddress,
+			Usage:   "Address to listen on",
+			Value:   "localhost:8080",
+			EnvVars: []string{toEnvVar(flagAddress)},
+		},
+		&cli.StringFlag{
+			Name:    flagUrl,
+			Usage:   "Base URL for links in responses (by default, links use the host of each request)",
+			EnvVars: []string{toEnvVar(flagUrl)},
+		},
+		&cli.IntFlag{
+			Name:    flagLimit,
+			Usage:   "Number of items per page if a request does not include a limit",
+			Value:   10,
+			EnvVars: []string{toEnvVar(flagLimit)},
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		entry := ctx.String(flagEntry)
+		if entry == "" {
+			return cli.Exit(fmt.Sprintf("missing --%s", flagEntry), 1)
+		}
+
+		s, err := server.New(entry, &server.Options{
+			BaseURL: ctx.String(flagUrl),
+			Limit:   ctx.Int(flagLimit),
+		})
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+
+		listener, err := net.Listen("tcp", ctx.String(flagAddress))
+		if err != nil {
+			return cli.Exit(fmt.Sprintf("failed to listen: %s", err), 1)
+		}
+
+		signalCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		httpServer := &http.Server{Handler: s, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			<-signalCtx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = httpServer.Shutdown(shutdownCtx)
+		}()
+
+		fmt.Fprintf(os.Stderr, "serving %s at http://%s/\n", entry, listener.Addr())
+		if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return cli.Exit(fmt.Sprintf("server failed: %s", err), 3)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/planetlabs/go-stac/crawler"
+	"github.com/planetlabs/go-stac/summary"
+	"github.com/urfave/cli/v2"
+)
+
+var summarizeCommand = &cli.Command{
+	Name:        "summarize",
+	Usage:       "Generate collection summaries",
+	Description: "Crawls the items of a collection and generates summaries of their properties.  Strings with few distinct values are summarized as lists, and numbers and datetimes are summarized as ranges.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    flagEntry,
+			Usage:   "Path or URL to a collection",
+			EnvVars: []string{toEnvVar(flagEntry)},
+		},
+		&cli.StringFlag{
+			Name:    flagOutput,
+			Usage:   "Path to write a version of the collection with summaries added (if not provided, summaries will be written to stdout)",
+			EnvVars: []string{toEnvVar(flagOutput)},
+		},
+		&cli.StringSliceFlag{
+			Name:    flagRule,
+			Usage:   "Summary rule for a property as <name>=<kind> where kind is auto, values, range, schema, or skip (can be repeated)",
+			EnvVars: []string{toEnvVar(flagRule)},
+		},
+		&cli.IntFlag{
+			Name:    flagMaxValues,
+			Usage:   "Maximum number of distinct values for a string property to be summarized as a list",
+			Value:   10,
+			EnvVars: []string{toEnvVar(flagMaxValues)},
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		entry := ctx.String(flagEntry)
+		if entry == "" {
+			return cli.Exit(fmt.Sprintf("missing --%s", flagEntry), 1)
+		}
+
+		rules, err := parseRules(ctx.StringSlice(flagRule))
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+
+		options := &summary.Options{Rules: rules, MaxValues: ctx.Int(flagMaxValues)}
+		collection, err := summarizeCollection(entry, options)
+		if err != nil {
+			return cli.Exit(fmt.Sprintf("failed to summarize: %s", err), 1)
+		}
+
+		outputPath := ctx.String(flagOutput)
+		if outputPath == "" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(collection["summaries"])
+		}
+
+		data, err := json.MarshalIndent(orderedMap(collection), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode resource as JSON: %w", err)
+		}
+		return os.WriteFile(outputPath, data, 0644)
+	},
+}
+
+// parseRules parses <name>=<kind> values.
+func parseRules(values []string) (map[string]*summary.Rule, error) {
+	rules := map[string]*summary.Rule{}
+	for _, value := range values {
+		name, kindValue, ok := strings.Cut(value, "=")
+		name = strings.TrimSpace(name)
+		kindValue = strings.TrimSpace(kindValue)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --%s value %q, expected <name>=<kind>", flagRule, value)
+		}
+		kind := summary.Kind(kindValue)
+		if kindValue == "auto" {
+			kind = summary.Auto
+		} else if kind == summary.Auto || !slices.Contains(summary.Kinds, kind) {
+			return nil, fmt.Errorf("invalid --%s value %q, kind must be auto, values, range, schema, or skip", flagRule, value)
+		}
+		rules[name] = &summary.Rule{Kind: kind}
+	}
+	return rules, nil
+}
+
+// summarizeCollection crawls a collection and returns the collection with
+// summaries generated from the properties of its items.  Items that belong to
+// other collections are not included.
+func summarizeCollection(entry string, options *summary.Options) (crawler.Resource, error) {
+	summarizer := summary.New(options)
+
+	var collection crawler.Resource
+	collectionId := ""
+
+	visitor := func(resource crawler.Resource, info *crawler.ResourceInfo) error {
+		if info.Location == info.Entry {
+			if resource.Type() != crawler.Collection {
+				return fmt.Errorf("expected %s to be a collection, got %s", entry, resource.Type())
+			}
+			collection = resource
+			collectionId, _ = resource["id"].(string)
+			return nil
+		}
+
+		if resource.Type() != crawler.Item {
+			return nil
+		}
+		if id, ok := resource["collection"].(string); ok && id != collectionId {
+			return nil
+		}
+		properties, _ := resource["properties"].(map[string]any)
+		summarizer.Add(properties)
+		return nil
+	}
+
+	if err := crawler.Crawl(entry, visitor); err != nil {
+		return nil, err
+	}
+	if collection == nil {
+		return nil, fmt.Errorf("no collection found at %s", entry)
+	}
+
+	collection["summaries"] = summarizer.Summaries()
+	return collection, nil
+}
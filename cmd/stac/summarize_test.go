@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/planetlabs/go-stac/summary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeCollection(t *testing.T) {
+	collection, err := summarizeCollection("../../server/testdata/collection-a/collection.json", &summary.Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "collection-a", collection["id"])
+	assert.Equal(t, map[string]any{
+		"datetime":       map[string]any{"minimum": "2021-01-01T00:00:00Z", "maximum": "2022-01-01T00:00:00Z"},
+		"eo:cloud_cover": map[string]any{"minimum": float64(5), "maximum": float64(50)},
+	}, collection["summaries"])
+}
+
+func TestSummarizeNotCollection(t *testing.T) {
+	_, err := summarizeCollection("../../server/testdata/catalog.json", &summary.Options{})
+	assert.ErrorContains(t, err, "to be a collection")
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := parseRules([]string{"eo:cloud_cover=skip", "gsd = range", "platform=auto"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*summary.Rule{
+		"eo:cloud_cover": {Kind: summary.Skip},
+		"gsd":            {Kind: summary.Range},
+		"platform":       {Kind: summary.Auto},
+	}, rules)
+
+	_, err = parseRules([]string{"gsd=average"})
+	assert.ErrorContains(t, err, "kind must be")
+
+	_, err = parseRules([]string{"gsd"})
+	assert.ErrorContains(t, err, "expected <name>=<kind>")
+
+	_, err = parseRules([]string{"gsd="})
+	assert.ErrorContains(t, err, "kind must be")
+}
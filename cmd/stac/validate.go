@@ -1,8 +1,15 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
 	"strings"
 
 	"github.com/planetlabs/go-stac/validator"
@@ -17,7 +24,7 @@ var validateCommand = &cli.Command{
 	Flags: []cli.Flag{
 		&cli.StringFlag{
 			Name:    flagEntry,
-			Usage:   "Path to STAC resource (catalog, collection, or item) to validate",
+			Usage:   "Path to STAC resource (catalog, collection, or item) to validate (use - to read JSON or newline-delimited JSON from stdin)",
 			EnvVars: []string{toEnvVar(flagEntry)},
 		},
 		&cli.StringSliceFlag{
@@ -25,6 +32,21 @@ var validateCommand = &cli.Command{
 			Usage:   "Substitute schema as <original>=<substitute> pairs",
 			EnvVars: []string{toEnvVar(flagSchema)},
 		},
+		&cli.BoolFlag{
+			Name:    flagSemantic,
+			Usage:   "Check semantic rules (e.g. bbox contains geometry) in addition to the schema",
+			EnvVars: []string{toEnvVar(flagSemantic)},
+		},
+		&cli.BoolFlag{
+			Name:    flagApi,
+			Usage:   "Treat the entry as a STAC API landing page and check the advertised conformance classes",
+			EnvVars: []string{toEnvVar(flagApi)},
+		},
+		&cli.BoolFlag{
+			Name:    flagWatch,
+			Usage:   "Watch local files and validate resources again when they change",
+			EnvVars: []string{toEnvVar(flagWatch)},
+		},
 		&cli.BoolFlag{
 			Name:    flagNoRecursion,
 			Usage:   "Visit a single resource",
@@ -61,18 +83,150 @@ var validateCommand = &cli.Command{
 			schemaMap[items[0]] = items[1]
 		}
 
-		v := validator.New(&validator.Options{
+		options := &validator.Options{
 			NoRecursion: ctx.Bool(flagNoRecursion),
 			SchemaMap:   schemaMap,
 			Logger:      logger,
-		})
+		}
+		if ctx.Bool(flagSemantic) {
+			options.Rules = validator.Rules()
+			options.IssueHandler = func(issue *validator.Issue) error {
+				if issue.Rule.Severity == validator.SeverityError {
+					return issue
+				}
+				fmt.Fprintf(os.Stderr, "%#v\n", issue)
+				return nil
+			}
+		}
+
+		v := validator.New(options)
+		if ctx.Bool(flagApi) {
+			return validateAPI(v, entryPath)
+		}
+		if entryPath == "-" {
+			return validateStream(v, os.Stdin)
+		}
+		if ctx.Bool(flagWatch) {
+			return watch(v, entryPath)
+		}
+
 		err := v.Validate(context.Background(), entryPath)
 		if err != nil {
 			if validationErr, ok := err.(*validator.ValidationError); ok {
 				return cli.Exit(fmt.Sprintf("%#v\n", validationErr), 2)
 			}
+			if issue, ok := err.(*validator.Issue); ok {
+				return cli.Exit(fmt.Sprintf("%#v\n", issue), 2)
+			}
 			return cli.Exit(fmt.Sprintf("validation failed: %s\n", err), 3)
 		}
 		return nil
 	},
 }
+
+// validateStream validates a single JSON document or a stream of newline-delimited
+// JSON documents.  Each document is reported with a location like "stdin:1" (using
+// the line number for newline-delimited JSON).  A document that cannot be parsed or
+// validated is reported as invalid and the remaining documents are still validated.
+// If the first line is not a complete JSON value, the whole input is treated as a
+// single (formatted) document.
+func validateStream(v *validator.Validator, reader io.Reader) error {
+	buffered := bufio.NewReader(reader)
+	invalid := 0
+	first := true
+	for count := 1; ; count += 1 {
+		data, readErr := buffered.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return cli.Exit(fmt.Sprintf("failed to read stdin: %s\n", readErr), 3)
+		}
+
+		if len(bytes.TrimSpace(data)) > 0 {
+			if first && !json.Valid(data) {
+				rest, err := io.ReadAll(buffered)
+				if err != nil {
+					return cli.Exit(fmt.Sprintf("failed to read stdin: %s\n", err), 3)
+				}
+				data = append(data, rest...)
+				readErr = io.EOF
+			}
+			first = false
+
+			location := fmt.Sprintf("stdin:%d", count)
+			if err := v.ValidateBytes(context.Background(), data, location); err != nil {
+				invalid += 1
+				switch err := err.(type) {
+				case *validator.ValidationError, *validator.Issue:
+					fmt.Fprintf(os.Stderr, "%#v\n", err)
+				default:
+					fmt.Fprintf(os.Stderr, "%s: %s\n", location, err)
+				}
+			}
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	if invalid > 0 {
+		return cli.Exit(fmt.Sprintf("found %d invalid resource(s)", invalid), 2)
+	}
+	return nil
+}
+
+func watch(v *validator.Validator, entryPath string) error {
+	signalCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := v.Watch(signalCtx, entryPath, func(event *validator.WatchEvent) error {
+		switch err := event.Err.(type) {
+		case nil:
+			if event.Changed {
+				fmt.Printf("valid: %s\n", event.Location)
+			}
+		case *validator.ValidationError, *validator.Issue:
+			fmt.Fprintf(os.Stderr, "%#v\n", err)
+		default:
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return cli.Exit(fmt.Sprintf("watch failed: %s\n", err), 3)
+	}
+	return nil
+}
+
+func validateAPI(v *validator.Validator, entryPath string) error {
+	report, err := v.ValidateAPI(context.Background(), entryPath)
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("validation failed: %s\n", err), 3)
+	}
+
+	for _, class := range report.Classes {
+		fmt.Printf("%s %s\n", passFail(class.Passed()), class.Name)
+		for _, check := range class.Checks {
+			if check.Err != nil {
+				fmt.Printf("  FAIL %s (%s): %s\n", check.Description, check.Location, check.Err)
+				continue
+			}
+			if check.Skipped != "" {
+				fmt.Printf("  SKIP %s (%s): %s\n", check.Description, check.Location, check.Skipped)
+				continue
+			}
+			fmt.Printf("  PASS %s\n", check.Description)
+		}
+	}
+
+	if !report.Passed() {
+		return cli.Exit("API does not conform to all advertised classes", 2)
+	}
+	return nil
+}
+
+func passFail(passed bool) string {
+	if passed {
+		return "PASS"
+	}
+	return "FAIL"
+}
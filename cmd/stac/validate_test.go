@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/planetlabs/go-stac/validator"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+// useTestSchemas loads schemas from the validator test data instead of the network.
+func useTestSchemas(t *testing.T) {
+	originalHttp := jsonschema.Loaders["http"]
+	originalHttps := jsonschema.Loaders["https"]
+	load := func(schemaURL string) (io.ReadCloser, error) {
+		u, err := url.Parse(schemaURL)
+		if err != nil {
+			return nil, err
+		}
+		return os.Open(path.Join("..", "..", "validator", "testdata", "schema", u.Host, u.Path))
+	}
+	jsonschema.Loaders["http"] = load
+	jsonschema.Loaders["https"] = load
+	t.Cleanup(func() {
+		jsonschema.Loaders["http"] = originalHttp
+		jsonschema.Loaders["https"] = originalHttps
+	})
+}
+
+func compactCase(t *testing.T, name string) string {
+	data, err := os.ReadFile("../../validator/testdata/cases/v1.0.0/" + name)
+	require.NoError(t, err)
+	buffer := &bytes.Buffer{}
+	require.NoError(t, json.Compact(buffer, data))
+	return buffer.String()
+}
+
+func TestValidateStreamContinues(t *testing.T) {
+	useTestSchemas(t)
+	lines := []string{
+		compactCase(t, "item.json"),
+		`{"type": "Feature"`,
+		`{"id": "no-type"}`,
+		compactCase(t, "item-missing-id.json"),
+		"",
+		compactCase(t, "item.json"),
+	}
+
+	err := validateStream(validator.New(), strings.NewReader(strings.Join(lines, "\n")))
+	require.Error(t, err)
+	exitErr, ok := err.(cli.ExitCoder)
+	require.True(t, ok)
+	assert.Equal(t, 2, exitErr.ExitCode())
+	assert.Equal(t, "found 3 invalid resource(s)", exitErr.Error())
+}
+
+func TestValidateStreamFormatted(t *testing.T) {
+	useTestSchemas(t)
+	data, err := os.ReadFile("../../validator/testdata/cases/v1.0.0/item.json")
+	require.NoError(t, err)
+
+	assert.NoError(t, validateStream(validator.New(), bytes.NewReader(data)))
+}
@@ -0,0 +1,280 @@
+// Package cql2 parses, encodes, and evaluates Common Query Language (CQL2) expressions.
+//
+// Expressions can be parsed from CQL2-Text with ParseText or from CQL2-JSON with
+// ParseJSON.  Every Expression can be encoded as CQL2-Text with its Text method and
+// as CQL2-JSON with encoding/json.  A Filter evaluates an expression against the
+// properties of STAC items.
+package cql2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Expression is a node in a CQL2 abstract syntax tree.
+type Expression interface {
+	json.Marshaler
+
+	// Text returns the CQL2-Text representation of the expression.
+	Text() string
+
+	expression()
+}
+
+// Literal is a string, number, or boolean value.
+type Literal struct {
+	Value any
+}
+
+// Property is a reference to a resource property.
+type Property struct {
+	Name string
+}
+
+// Timestamp is an instant in time.
+type Timestamp struct {
+	Value time.Time
+}
+
+// Date is a calendar date.
+type Date struct {
+	Value time.Time
+}
+
+// Interval is a time interval.  The start and end are typically timestamps, dates,
+// or strings.  The string ".." represents an unbounded start or end.
+type Interval struct {
+	Start Expression
+	End   Expression
+}
+
+// Geometry is a GeoJSON geometry.
+type Geometry struct {
+	Value map[string]any
+}
+
+// BBox is a bounding box with 4 or 6 numbers.
+type BBox struct {
+	Values []float64
+}
+
+// Array is a list of expressions.
+type Array struct {
+	Items []Expression
+}
+
+// Operation is an operator or function applied to a list of arguments.  The operator
+// uses the CQL2-JSON name (e.g. "and", "<=", "like", "s_intersects", or "casei").
+type Operation struct {
+	Op   string
+	Args []Expression
+}
+
+func (*Literal) expression()   {}
+func (*Property) expression()  {}
+func (*Timestamp) expression() {}
+func (*Date) expression()      {}
+func (*Interval) expression()  {}
+func (*Geometry) expression()  {}
+func (*BBox) expression()      {}
+func (*Array) expression()     {}
+func (*Operation) expression() {}
+
+const (
+	timestampLayout = time.RFC3339Nano
+	dateLayout      = time.DateOnly
+)
+
+// Parse parses a CQL2-JSON expression if the input starts with "{" and a CQL2-Text
+// expression otherwise.
+func Parse(input string) (Expression, error) {
+	if strings.HasPrefix(strings.TrimSpace(input), "{") {
+		return ParseJSON([]byte(input))
+	}
+	return ParseText(input)
+}
+
+// ParseJSON parses a CQL2-JSON expression.
+func ParseJSON(data []byte) (Expression, error) {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("failed to parse CQL2-JSON: %w", err)
+	}
+	return FromJSON(value)
+}
+
+// FromJSON creates an expression from a decoded CQL2-JSON value.
+func FromJSON(value any) (Expression, error) {
+	switch v := value.(type) {
+	case string, float64, bool:
+		return &Literal{Value: v}, nil
+	case int:
+		return &Literal{Value: float64(v)}, nil
+	case []any:
+		items := make([]Expression, len(v))
+		for i, item := range v {
+			expr, err := FromJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = expr
+		}
+		return &Array{Items: items}, nil
+	case map[string]any:
+		return objectFromJSON(v)
+	case nil:
+		return nil, errors.New("unexpected null in CQL2-JSON")
+	default:
+		return nil, fmt.Errorf("unexpected value in CQL2-JSON: %v", value)
+	}
+}
+
+func objectFromJSON(object map[string]any) (Expression, error) {
+	if op, ok := object["op"]; ok {
+		name, ok := op.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string op, got %v", op)
+		}
+		rawArgs, ok := object["args"].([]any)
+		if !ok && object["args"] != nil {
+			return nil, fmt.Errorf("expected args array for %q", name)
+		}
+		args := make([]Expression, len(rawArgs))
+		for i, rawArg := range rawArgs {
+			arg, err := FromJSON(rawArg)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = arg
+		}
+		return &Operation{Op: name, Args: args}, nil
+	}
+
+	if name, ok := object["property"]; ok {
+		str, ok := name.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string property name, got %v", name)
+		}
+		return &Property{Name: str}, nil
+	}
+
+	if value, ok := object["timestamp"]; ok {
+		str, _ := value.(string)
+		t, err := time.Parse(time.RFC3339Nano, str)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp %v: %w", value, err)
+		}
+		return &Timestamp{Value: t}, nil
+	}
+
+	if value, ok := object["date"]; ok {
+		str, _ := value.(string)
+		t, err := time.Parse(dateLayout, str)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %v: %w", value, err)
+		}
+		return &Date{Value: t}, nil
+	}
+
+	if value, ok := object["interval"]; ok {
+		bounds, ok := value.([]any)
+		if !ok || len(bounds) != 2 {
+			return nil, fmt.Errorf("expected interval with two values, got %v", value)
+		}
+		start, err := FromJSON(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		end, err := FromJSON(bounds[1])
+		if err != nil {
+			return nil, err
+		}
+		return &Interval{Start: start, End: end}, nil
+	}
+
+	if value, ok := object["bbox"]; ok {
+		values, ok := value.([]any)
+		if !ok || (len(values) != 4 && len(values) != 6) {
+			return nil, fmt.Errorf("expected bbox with 4 or 6 numbers, got %v", value)
+		}
+		bbox := make([]float64, len(values))
+		for i, v := range values {
+			number, ok := v.(float64)
+			if !ok {
+				return nil, fmt.Errorf("expected bbox with 4 or 6 numbers, got %v", value)
+			}
+			bbox[i] = number
+		}
+		return &BBox{Values: bbox}, nil
+	}
+
+	if _, ok := object["type"].(string); ok {
+		_, hasCoordinates := object["coordinates"]
+		_, hasGeometries := object["geometries"]
+		if hasCoordinates || hasGeometries {
+			return &Geometry{Value: object}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unexpected object in CQL2-JSON: %v", object)
+}
+
+func (e *Literal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.Value)
+}
+
+func (e *Property) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{"property": e.Name})
+}
+
+func (e *Timestamp) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{"timestamp": e.Value.UTC().Format(timestampLayout)})
+}
+
+func (e *Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{"date": e.Value.Format(dateLayout)})
+}
+
+func (e *Interval) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{"interval": []Expression{e.Start, e.End}})
+}
+
+// normalized returns the geometry as it would be decoded from JSON so that coordinates
+// constructed in Go (e.g. []float64) can be handled like decoded GeoJSON.
+func (e *Geometry) normalized() map[string]any {
+	data, err := json.Marshal(e.Value)
+	if err != nil {
+		return e.Value
+	}
+	geometry := map[string]any{}
+	if err := json.Unmarshal(data, &geometry); err != nil {
+		return e.Value
+	}
+	return geometry
+}
+
+func (e *Geometry) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.Value)
+}
+
+func (e *BBox) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{"bbox": e.Values})
+}
+
+func (e *Array) MarshalJSON() ([]byte, error) {
+	if e.Items == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(e.Items)
+}
+
+func (e *Operation) MarshalJSON() ([]byte, error) {
+	args := e.Args
+	if args == nil {
+		args = []Expression{}
+	}
+	return json.Marshal(map[string]any{"op": e.Op, "args": args})
+}
@@ -0,0 +1,248 @@
+package cql2_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/planetlabs/go-stac/cql2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseText(t *testing.T) {
+	cases := []struct {
+		text string
+		json string
+		// canonical text if different from the input
+		canonical string
+	}{
+		{
+			text: "eo:cloud_cover < 10",
+			json: `{"op":"<","args":[{"property":"eo:cloud_cover"},10]}`,
+		},
+		{
+			text: "collection = 'landsat' AND gsd >= 30",
+			json: `{"op":"and","args":[{"op":"=","args":[{"property":"collection"},"landsat"]},{"op":">=","args":[{"property":"gsd"},30]}]}`,
+		},
+		{
+			text:      "a = 1 OR b = 2 AND c = 3",
+			json:      `{"op":"or","args":[{"op":"=","args":[{"property":"a"},1]},{"op":"and","args":[{"op":"=","args":[{"property":"b"},2]},{"op":"=","args":[{"property":"c"},3]}]}]}`,
+			canonical: "a = 1 OR (b = 2 AND c = 3)",
+		},
+		{
+			text: "(a = 1 OR b = 2) AND c = 3",
+			json: `{"op":"and","args":[{"op":"or","args":[{"op":"=","args":[{"property":"a"},1]},{"op":"=","args":[{"property":"b"},2]}]},{"op":"=","args":[{"property":"c"},3]}]}`,
+		},
+		{
+			text: "NOT platform = 'sentinel-2a'",
+			json: `{"op":"not","args":[{"op":"=","args":[{"property":"platform"},"sentinel-2a"]}]}`,
+		},
+		{
+			text:      "id NOT LIKE 'S2%'",
+			json:      `{"op":"not","args":[{"op":"like","args":[{"property":"id"},"S2%"]}]}`,
+			canonical: "NOT id LIKE 'S2%'",
+		},
+		{
+			text: "gsd BETWEEN 10 AND 30",
+			json: `{"op":"between","args":[{"property":"gsd"},10,30]}`,
+		},
+		{
+			text: "platform IN ('landsat-8', 'landsat-9')",
+			json: `{"op":"in","args":[{"property":"platform"},["landsat-8","landsat-9"]]}`,
+		},
+		{
+			text: "constellation IS NULL",
+			json: `{"op":"isNull","args":[{"property":"constellation"}]}`,
+		},
+		{
+			text: "NOT constellation IS NULL",
+			json: `{"op":"not","args":[{"op":"isNull","args":[{"property":"constellation"}]}]}`,
+		},
+		{
+			text:      "constellation IS NOT NULL",
+			json:      `{"op":"not","args":[{"op":"isNull","args":[{"property":"constellation"}]}]}`,
+			canonical: "NOT constellation IS NULL",
+		},
+		{
+			text:      "width * height > 1000 + -1",
+			json:      `{"op":">","args":[{"op":"*","args":[{"property":"width"},{"property":"height"}]},{"op":"+","args":[1000,-1]}]}`,
+			canonical: "(width * height) > (1000 + -1)",
+		},
+		{
+			text:      "(a + b) * 2 = 10",
+			json:      `{"op":"=","args":[{"op":"*","args":[{"op":"+","args":[{"property":"a"},{"property":"b"}]},2]},10]}`,
+			canonical: "((a + b) * 2) = 10",
+		},
+		{
+			text: "datetime > TIMESTAMP('2021-01-01T00:00:00Z')",
+			json: `{"op":">","args":[{"property":"datetime"},{"timestamp":"2021-01-01T00:00:00Z"}]}`,
+		},
+		{
+			text: "T_INTERSECTS(datetime, INTERVAL('2021-01-01', '..'))",
+			json: `{"op":"t_intersects","args":[{"property":"datetime"},{"interval":["2021-01-01",".."]}]}`,
+		},
+		{
+			text: "T_BEFORE(updated, DATE('2021-06-01'))",
+			json: `{"op":"t_before","args":[{"property":"updated"},{"date":"2021-06-01"}]}`,
+		},
+		{
+			text: "S_INTERSECTS(geometry, BBOX(-10, -5, 10, 5))",
+			json: `{"op":"s_intersects","args":[{"property":"geometry"},{"bbox":[-10,-5,10,5]}]}`,
+		},
+		{
+			text: "S_INTERSECTS(geometry, POINT(1.5 2))",
+			json: `{"op":"s_intersects","args":[{"property":"geometry"},{"coordinates":[1.5,2],"type":"Point"}]}`,
+		},
+		{
+			text: "S_WITHIN(geometry, POLYGON((0 0, 1 0, 1 1, 0 1, 0 0)))",
+			json: `{"op":"s_within","args":[{"property":"geometry"},{"coordinates":[[[0,0],[1,0],[1,1],[0,1],[0,0]]],"type":"Polygon"}]}`,
+		},
+		{
+			text: "CASEI(provider) = CASEI('Planet')",
+			json: `{"op":"=","args":[{"op":"casei","args":[{"property":"provider"}]},{"op":"casei","args":["Planet"]}]}`,
+		},
+		{
+			text: "A_CONTAINS(keywords, ('a', 'b'))",
+			json: `{"op":"a_contains","args":[{"property":"keywords"},["a","b"]]}`,
+		},
+		{
+			text: "A_CONTAINS(keywords, ('a'))",
+			json: `{"op":"a_contains","args":[{"property":"keywords"},["a"]]}`,
+		},
+		{
+			text: "\"my property\" = 'it''s'",
+			json: `{"op":"=","args":[{"property":"my property"},"it's"]}`,
+		},
+		{
+			text: "S_INTERSECTS(\"bbox\", POINT(0 0))",
+			json: `{"op":"s_intersects","args":[{"property":"bbox"},{"coordinates":[0,0],"type":"Point"}]}`,
+		},
+		{
+			text:      "date > '2021-01-01'",
+			json:      `{"op":">","args":[{"property":"date"},"2021-01-01"]}`,
+			canonical: "\"date\" > '2021-01-01'",
+		},
+		{
+			text: "flag = TRUE",
+			json: `{"op":"=","args":[{"property":"flag"},true]}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.text, func(t *testing.T) {
+			expr, err := cql2.ParseText(c.text)
+			require.NoError(t, err)
+
+			data, err := json.Marshal(expr)
+			require.NoError(t, err)
+			assert.JSONEq(t, c.json, string(data))
+
+			fromJSON, err := cql2.ParseJSON([]byte(c.json))
+			require.NoError(t, err)
+
+			canonical := c.canonical
+			if canonical == "" {
+				canonical = c.text
+			}
+			assert.Equal(t, canonical, fromJSON.Text())
+			assert.Equal(t, canonical, expr.Text())
+		})
+	}
+}
+
+func TestParseTextKeywordsCaseInsensitive(t *testing.T) {
+	upper, err := cql2.ParseText("a = 1 AND b IS NOT NULL")
+	require.NoError(t, err)
+
+	lower, err := cql2.ParseText("a = 1 and b is not null")
+	require.NoError(t, err)
+
+	assert.Equal(t, upper.Text(), lower.Text())
+}
+
+func TestParseTextMultiPoint(t *testing.T) {
+	bare, err := cql2.ParseText("S_INTERSECTS(geometry, MULTIPOINT(1 2, 3 4))")
+	require.NoError(t, err)
+
+	nested, err := cql2.ParseText("S_INTERSECTS(geometry, MULTIPOINT((1 2), (3 4)))")
+	require.NoError(t, err)
+
+	assert.Equal(t, bare.Text(), nested.Text())
+	assert.Equal(t, "S_INTERSECTS(geometry, MULTIPOINT(1 2, 3 4))", bare.Text())
+}
+
+func TestParseTextGeometryCollection(t *testing.T) {
+	text := "S_INTERSECTS(geometry, GEOMETRYCOLLECTION(POINT(1 2), LINESTRING(0 0, 1 1)))"
+	expr, err := cql2.ParseText(text)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(expr)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"op": "s_intersects",
+		"args": [
+			{"property": "geometry"},
+			{
+				"type": "GeometryCollection",
+				"geometries": [
+					{"type": "Point", "coordinates": [1, 2]},
+					{"type": "LineString", "coordinates": [[0, 0], [1, 1]]}
+				]
+			}
+		]
+	}`, string(data))
+	assert.Equal(t, text, expr.Text())
+}
+
+func TestParseTextErrors(t *testing.T) {
+	cases := []string{
+		"",
+		"a =",
+		"a = 'unterminated",
+		"(a = 1",
+		"a = 1 extra",
+		"a BETWEEN 1",
+		"a IN (1, 2",
+		"S_INTERSECTS(geometry, POINT(1))",
+		"TIMESTAMP('not a time') = a",
+		"a = 1 &",
+	}
+
+	for _, c := range cases {
+		t.Run(c, func(t *testing.T) {
+			_, err := cql2.ParseText(c)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseJSONErrors(t *testing.T) {
+	cases := []string{
+		`{`,
+		`{"op": 42, "args": []}`,
+		`{"op": "and", "args": "nope"}`,
+		`{"property": 42}`,
+		`{"timestamp": "yesterday"}`,
+		`{"interval": ["2021-01-01"]}`,
+		`{"bbox": [1, 2, 3]}`,
+		`{"unknown": true}`,
+		`null`,
+	}
+
+	for _, c := range cases {
+		t.Run(c, func(t *testing.T) {
+			_, err := cql2.ParseJSON([]byte(c))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	fromText, err := cql2.Parse("id = 'abc'")
+	require.NoError(t, err)
+
+	fromJSON, err := cql2.Parse(`  {"op": "=", "args": [{"property": "id"}, "abc"]}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, fromText, fromJSON)
+}
@@ -0,0 +1,610 @@
+package cql2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/crawler"
+)
+
+// Filter evaluates a CQL2 expression against STAC resources.
+type Filter struct {
+	expression Expression
+}
+
+// NewFilter creates a filter from an expression.
+func NewFilter(expression Expression) *Filter {
+	return &Filter{expression: expression}
+}
+
+// ParseFilter parses a CQL2-Text or CQL2-JSON expression (see Parse) and returns a filter.
+func ParseFilter(input string) (*Filter, error) {
+	expression, err := Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	return NewFilter(expression), nil
+}
+
+// Expression returns the filter expression.
+func (f *Filter) Expression() Expression {
+	return f.expression
+}
+
+// Match determines if a resource matches the filter expression.
+//
+// Property names are resolved against the resource properties first and then
+// against top-level members (e.g. "id", "collection", or "geometry").  A
+// "properties." prefix is ignored.  An item with a null datetime is treated as
+// the interval between its start_datetime and end_datetime properties.
+func (f *Filter) Match(resource crawler.Resource) (bool, error) {
+	value, err := evaluate(f.expression, resource)
+	if err != nil {
+		return false, err
+	}
+	if value == nil {
+		return false, nil
+	}
+	result, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("expected a boolean expression, got %v", value)
+	}
+	return result, nil
+}
+
+// MatchItem determines if an item matches the filter expression.
+func (f *Filter) MatchItem(item *stac.Item) (bool, error) {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode item: %w", err)
+	}
+	resource := crawler.Resource{}
+	if err := json.Unmarshal(data, &resource); err != nil {
+		return false, fmt.Errorf("failed to decode item: %w", err)
+	}
+	return f.Match(resource)
+}
+
+// instant is a time interval.  Instants have equal start and end times.
+type instant struct {
+	start time.Time
+	end   time.Time
+}
+
+var (
+	minTime = time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)
+	maxTime = time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)
+)
+
+func lookupProperty(resource crawler.Resource, name string) any {
+	name = strings.TrimPrefix(name, "properties.")
+	properties, _ := resource["properties"].(map[string]any)
+	if value, ok := properties[name]; ok {
+		if name == "datetime" && value == nil {
+			return datetimeInterval(properties)
+		}
+		return value
+	}
+	return resource[name]
+}
+
+func datetimeInterval(properties map[string]any) any {
+	start, startErr := parseTime(properties["start_datetime"])
+	end, endErr := parseTime(properties["end_datetime"])
+	if startErr != nil || endErr != nil {
+		return nil
+	}
+	return &instant{start: start, end: end}
+}
+
+func parseTime(value any) (time.Time, error) {
+	str, ok := value.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("expected a time string, got %v", value)
+	}
+	if t, err := time.Parse(time.RFC3339Nano, str); err == nil {
+		return t, nil
+	}
+	return time.Parse(dateLayout, str)
+}
+
+func evaluate(expr Expression, resource crawler.Resource) (any, error) {
+	switch e := expr.(type) {
+	case *Literal:
+		return e.Value, nil
+	case *Property:
+		return lookupProperty(resource, e.Name), nil
+	case *Timestamp:
+		return &instant{start: e.Value, end: e.Value}, nil
+	case *Date:
+		// a date covers the whole day
+		return &instant{start: e.Value, end: e.Value.AddDate(0, 0, 1).Add(-time.Nanosecond)}, nil
+	case *Interval:
+		return evaluateInterval(e, resource)
+	case *Geometry:
+		return e.normalized(), nil
+	case *BBox:
+		return e.Values, nil
+	case *Array:
+		values := make([]any, len(e.Items))
+		for i, item := range e.Items {
+			value, err := evaluate(item, resource)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		return values, nil
+	case *Operation:
+		return evaluateOperation(e, resource)
+	default:
+		return nil, fmt.Errorf("unsupported expression %T", expr)
+	}
+}
+
+func evaluateInterval(interval *Interval, resource crawler.Resource) (any, error) {
+	bound := func(expr Expression, open time.Time, end bool) (time.Time, error) {
+		value, err := evaluate(expr, resource)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if value == ".." {
+			return open, nil
+		}
+		t, err := toInstant(value)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if end {
+			return t.end, nil
+		}
+		return t.start, nil
+	}
+	start, err := bound(interval.Start, minTime, false)
+	if err != nil {
+		return nil, err
+	}
+	end, err := bound(interval.End, maxTime, true)
+	if err != nil {
+		return nil, err
+	}
+	return &instant{start: start, end: end}, nil
+}
+
+func evaluateArgs(op *Operation, resource crawler.Resource, count int) ([]any, error) {
+	if count >= 0 && len(op.Args) != count {
+		return nil, fmt.Errorf("expected %d arguments for %q, got %d", count, op.Op, len(op.Args))
+	}
+	values := make([]any, len(op.Args))
+	for i, arg := range op.Args {
+		value, err := evaluate(arg, resource)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func evaluateOperation(op *Operation, resource crawler.Resource) (any, error) {
+	name := strings.ToLower(op.Op)
+	switch name {
+	case "and", "or":
+		// null is unknown: false and null is false, true or null is true, and
+		// otherwise a null argument makes the result null
+		unknown := false
+		for _, arg := range op.Args {
+			value, err := evaluate(arg, resource)
+			if err != nil {
+				return nil, err
+			}
+			if value == nil {
+				unknown = true
+				continue
+			}
+			result, ok := value.(bool)
+			if !ok {
+				return nil, fmt.Errorf("expected boolean arguments for %q, got %v", op.Op, value)
+			}
+			if name == "and" && !result {
+				return false, nil
+			}
+			if name == "or" && result {
+				return true, nil
+			}
+		}
+		if unknown {
+			return nil, nil
+		}
+		return name == "and", nil
+	case "not":
+		args, err := evaluateArgs(op, resource, 1)
+		if err != nil {
+			return nil, err
+		}
+		if args[0] == nil {
+			return nil, nil
+		}
+		result, ok := args[0].(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected boolean argument for %q, got %v", op.Op, args[0])
+		}
+		return !result, nil
+	case "isnull":
+		args, err := evaluateArgs(op, resource, 1)
+		if err != nil {
+			return nil, err
+		}
+		return args[0] == nil, nil
+	}
+
+	if comparisonOps[name] {
+		args, err := evaluateArgs(op, resource, 2)
+		if err != nil {
+			return nil, err
+		}
+		return compareValues(name, args[0], args[1])
+	}
+
+	if arithmeticOps[name] {
+		args, err := evaluateArgs(op, resource, 2)
+		if err != nil {
+			return nil, err
+		}
+		return arithmetic(name, args[0], args[1])
+	}
+
+	if strings.HasPrefix(name, "s_") {
+		args, err := evaluateArgs(op, resource, 2)
+		if err != nil {
+			return nil, err
+		}
+		return spatialOperation(name, args[0], args[1])
+	}
+
+	if strings.HasPrefix(name, "t_") {
+		args, err := evaluateArgs(op, resource, 2)
+		if err != nil {
+			return nil, err
+		}
+		return temporalOperation(name, args[0], args[1])
+	}
+
+	if strings.HasPrefix(name, "a_") {
+		args, err := evaluateArgs(op, resource, 2)
+		if err != nil {
+			return nil, err
+		}
+		return arrayOperation(name, args[0], args[1])
+	}
+
+	switch name {
+	case "like":
+		args, err := evaluateArgs(op, resource, 2)
+		if err != nil {
+			return nil, err
+		}
+		return like(args[0], args[1])
+	case "between":
+		args, err := evaluateArgs(op, resource, 3)
+		if err != nil {
+			return nil, err
+		}
+		low, err := compareValues(">=", args[0], args[1])
+		if err != nil || low != true {
+			return low, err
+		}
+		return compareValues("<=", args[0], args[2])
+	case "in":
+		args, err := evaluateArgs(op, resource, 2)
+		if err != nil {
+			return nil, err
+		}
+		list, ok := args[1].([]any)
+		if !ok {
+			return nil, fmt.Errorf("expected a list for %q, got %v", op.Op, args[1])
+		}
+		if args[0] == nil {
+			return nil, nil
+		}
+		for _, item := range list {
+			if equal, err := compareValues("=", args[0], item); err == nil && equal == true {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "casei":
+		args, err := evaluateArgs(op, resource, 1)
+		if err != nil {
+			return nil, err
+		}
+		if args[0] == nil {
+			return nil, nil
+		}
+		str, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a string for %q, got %v", op.Op, args[0])
+		}
+		return strings.ToLower(str), nil
+	}
+
+	return nil, fmt.Errorf("unsupported operator %q", op.Op)
+}
+
+func compareValues(op string, a any, b any) (any, error) {
+	if a == nil || b == nil {
+		return nil, nil
+	}
+
+	switch av := a.(type) {
+	case float64:
+		bv, ok := b.(float64)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare number %v with %v", a, b)
+		}
+		return compareOrdered(op, av, bv), nil
+	case bool:
+		bv, ok := b.(bool)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare boolean %v with %v", a, b)
+		}
+		switch op {
+		case "=":
+			return av == bv, nil
+		case "<>":
+			return av != bv, nil
+		}
+		return nil, fmt.Errorf("cannot use %q with booleans", op)
+	}
+
+	_, aTime := a.(*instant)
+	_, bTime := b.(*instant)
+	if aTime || bTime {
+		at, err := toInstant(a)
+		if err != nil {
+			return nil, err
+		}
+		bt, err := toInstant(b)
+		if err != nil {
+			return nil, err
+		}
+		return compareOrdered(op, at.start.UnixNano(), bt.start.UnixNano()), nil
+	}
+
+	as, aok := a.(string)
+	bs, bok := b.(string)
+	if aok && bok {
+		return compareOrdered(op, as, bs), nil
+	}
+
+	if op == "=" || op == "<>" {
+		aj, _ := json.Marshal(a)
+		bj, _ := json.Marshal(b)
+		equal := string(aj) == string(bj)
+		return equal == (op == "="), nil
+	}
+	return nil, fmt.Errorf("cannot compare %v with %v", a, b)
+}
+
+func compareOrdered[T float64 | int64 | string](op string, a T, b T) bool {
+	switch op {
+	case "=":
+		return a == b
+	case "<>":
+		return a != b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	default:
+		return a >= b
+	}
+}
+
+func arithmetic(op string, a any, b any) (any, error) {
+	if a == nil || b == nil {
+		return nil, nil
+	}
+	av, aok := a.(float64)
+	bv, bok := b.(float64)
+	if !aok || !bok {
+		return nil, fmt.Errorf("expected numbers for %q, got %v and %v", op, a, b)
+	}
+	switch op {
+	case "+":
+		return av + bv, nil
+	case "-":
+		return av - bv, nil
+	case "*":
+		return av * bv, nil
+	case "/":
+		return av / bv, nil
+	case "%":
+		return math.Mod(av, bv), nil
+	default:
+		return math.Trunc(av / bv), nil
+	}
+}
+
+func like(value any, pattern any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	str, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected a string for like, got %v", value)
+	}
+	patternStr, ok := pattern.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected a string pattern for like, got %v", pattern)
+	}
+
+	builder := &strings.Builder{}
+	builder.WriteString("^")
+	escaped := false
+	for _, r := range patternStr {
+		switch {
+		case escaped:
+			builder.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			builder.WriteString(".*")
+		case r == '_':
+			builder.WriteString(".")
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	builder.WriteString("$")
+	re, err := regexp.Compile("(?s)" + builder.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid like pattern %q: %w", patternStr, err)
+	}
+	return re.MatchString(str), nil
+}
+
+func toInstant(value any) (*instant, error) {
+	switch v := value.(type) {
+	case *instant:
+		return v, nil
+	case string:
+		t, err := parseTime(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %q: %w", v, err)
+		}
+		return &instant{start: t, end: t}, nil
+	default:
+		return nil, fmt.Errorf("expected a time, got %v", value)
+	}
+}
+
+func temporalOperation(op string, a any, b any) (any, error) {
+	if a == nil || b == nil {
+		return nil, nil
+	}
+	x, err := toInstant(a)
+	if err != nil {
+		return nil, err
+	}
+	y, err := toInstant(b)
+	if err != nil {
+		return nil, err
+	}
+
+	before := func(a time.Time, b time.Time) bool { return a.Before(b) }
+	after := func(a time.Time, b time.Time) bool { return a.After(b) }
+	equal := func(a time.Time, b time.Time) bool { return a.Equal(b) }
+
+	switch op {
+	case "t_after":
+		return after(x.start, y.end), nil
+	case "t_before":
+		return before(x.end, y.start), nil
+	case "t_contains":
+		return before(x.start, y.start) && after(x.end, y.end), nil
+	case "t_disjoint":
+		return before(x.end, y.start) || after(x.start, y.end), nil
+	case "t_during":
+		return after(x.start, y.start) && before(x.end, y.end), nil
+	case "t_equals":
+		return equal(x.start, y.start) && equal(x.end, y.end), nil
+	case "t_finishedby":
+		return before(x.start, y.start) && equal(x.end, y.end), nil
+	case "t_finishes":
+		return after(x.start, y.start) && equal(x.end, y.end), nil
+	case "t_intersects":
+		return !after(x.start, y.end) && !before(x.end, y.start), nil
+	case "t_meets":
+		return equal(x.end, y.start), nil
+	case "t_metby":
+		return equal(x.start, y.end), nil
+	case "t_overlappedby":
+		return after(x.start, y.start) && before(x.start, y.end) && after(x.end, y.end), nil
+	case "t_overlaps":
+		return before(x.start, y.start) && after(x.end, y.start) && before(x.end, y.end), nil
+	case "t_startedby":
+		return equal(x.start, y.start) && after(x.end, y.end), nil
+	case "t_starts":
+		return equal(x.start, y.start) && before(x.end, y.end), nil
+	}
+	return nil, fmt.Errorf("unsupported operator %q", op)
+}
+
+func arrayOperation(op string, a any, b any) (any, error) {
+	if a == nil || b == nil {
+		return nil, nil
+	}
+	x, xok := a.([]any)
+	y, yok := b.([]any)
+	if !xok || !yok {
+		return nil, fmt.Errorf("expected arrays for %q, got %v and %v", op, a, b)
+	}
+
+	contains := func(list []any, value any) bool {
+		for _, item := range list {
+			if equal, err := compareValues("=", item, value); err == nil && equal == true {
+				return true
+			}
+		}
+		return false
+	}
+	containsAll := func(list []any, values []any) bool {
+		for _, value := range values {
+			if !contains(list, value) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch op {
+	case "a_equals":
+		return len(x) == len(y) && containsAll(x, y) && containsAll(y, x), nil
+	case "a_contains":
+		return containsAll(x, y), nil
+	case "a_containedby":
+		return containsAll(y, x), nil
+	case "a_overlaps":
+		for _, value := range y {
+			if contains(x, value) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return nil, fmt.Errorf("unsupported operator %q", op)
+}
+
+var errUnsupportedGeometry = errors.New("unsupported geometry")
+
+func spatialOperation(op string, a any, b any) (any, error) {
+	if a == nil || b == nil {
+		return nil, nil
+	}
+	x, err := toShape(a)
+	if err != nil {
+		return nil, err
+	}
+	y, err := toShape(b)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "s_intersects":
+		return x.intersects(y), nil
+	case "s_disjoint":
+		return !x.intersects(y), nil
+	}
+	return nil, fmt.Errorf("unsupported operator %q", op)
+}
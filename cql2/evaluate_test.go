@@ -0,0 +1,222 @@
+package cql2_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/cql2"
+	"github.com/planetlabs/go-stac/crawler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testItem = `{
+	"stac_version": "1.0.0",
+	"type": "Feature",
+	"id": "S2A_20210615",
+	"collection": "sentinel-2",
+	"bbox": [10, 20, 11, 21],
+	"geometry": {
+		"type": "Polygon",
+		"coordinates": [[[10, 20], [11, 20], [11, 21], [10, 21], [10, 20]]]
+	},
+	"properties": {
+		"datetime": "2021-06-15T10:30:00Z",
+		"platform": "Sentinel-2A",
+		"eo:cloud_cover": 12.5,
+		"gsd": 10,
+		"keywords": ["optical", "sentinel", "esa"],
+		"title": "Été à Genève",
+		"constellation": null
+	},
+	"links": [],
+	"assets": {}
+}`
+
+const testIntervalItem = `{
+	"stac_version": "1.0.0",
+	"type": "Feature",
+	"id": "composite",
+	"geometry": {"type": "Point", "coordinates": [0, 0]},
+	"properties": {
+		"datetime": null,
+		"start_datetime": "2021-01-01T00:00:00Z",
+		"end_datetime": "2021-12-31T23:59:59Z"
+	},
+	"links": [],
+	"assets": {}
+}`
+
+func loadResource(t *testing.T, data string) crawler.Resource {
+	resource := crawler.Resource{}
+	require.NoError(t, json.Unmarshal([]byte(data), &resource))
+	return resource
+}
+
+func TestFilterMatch(t *testing.T) {
+	cases := []struct {
+		filter string
+		match  bool
+	}{
+		{filter: "eo:cloud_cover < 20", match: true},
+		{filter: "eo:cloud_cover >= 20", match: false},
+		{filter: "properties.eo:cloud_cover < 20", match: true},
+		{filter: "id = 'S2A_20210615'", match: true},
+		{filter: "collection = 'sentinel-2'", match: true},
+		{filter: "collection <> 'landsat'", match: true},
+		{filter: "platform = 'sentinel-2a'", match: false},
+		{filter: "CASEI(platform) = CASEI('sentinel-2a')", match: true},
+		{filter: "id LIKE 'S2%'", match: true},
+		{filter: "id LIKE 'S2_2021%'", match: false},
+		{filter: "id LIKE 'S2A\\_2021%'", match: true},
+		{filter: "id NOT LIKE 'LC%'", match: true},
+		{filter: "gsd BETWEEN 5 AND 10", match: true},
+		{filter: "gsd BETWEEN 11 AND 30", match: false},
+		{filter: "gsd IN (10, 20, 30)", match: true},
+		{filter: "platform IN ('landsat-8', 'landsat-9')", match: false},
+		{filter: "constellation IS NULL", match: true},
+		{filter: "missing IS NULL", match: true},
+		{filter: "platform IS NOT NULL", match: true},
+		{filter: "gsd * 2 = 20", match: true},
+		{filter: "gsd + eo:cloud_cover > 22", match: true},
+		{filter: "gsd / 4 = 2.5", match: true},
+		{filter: "gsd % 3 = 1", match: true},
+		{filter: "eo:cloud_cover < 20 AND gsd = 10", match: true},
+		{filter: "eo:cloud_cover > 20 OR gsd = 10", match: true},
+		{filter: "NOT (eo:cloud_cover < 20)", match: false},
+		{filter: "missing = 1 OR gsd = 10", match: true},
+		{filter: "missing = 1 AND gsd = 10", match: false},
+		{filter: "NOT (missing = 1 AND gsd = 10)", match: false},
+		{filter: "NOT (missing = 1 AND gsd = 20)", match: true},
+		{filter: "NOT (missing = 1 OR gsd = 20)", match: false},
+		{filter: "NOT (missing = 1 OR gsd = 10)", match: false},
+		{filter: "missing = 1", match: false},
+		{filter: "datetime = TIMESTAMP('2021-06-15T10:30:00Z')", match: true},
+		{filter: "datetime > TIMESTAMP('2021-01-01T00:00:00Z')", match: true},
+		{filter: "datetime < TIMESTAMP('2021-01-01T00:00:00Z')", match: false},
+		{filter: "T_AFTER(datetime, TIMESTAMP('2021-01-01T00:00:00Z'))", match: true},
+		{filter: "T_BEFORE(datetime, DATE('2021-06-15'))", match: false},
+		{filter: "T_DURING(datetime, DATE('2021-06-15'))", match: true},
+		{filter: "T_INTERSECTS(datetime, INTERVAL('2021-06-01T00:00:00Z', '2021-07-01T00:00:00Z'))", match: true},
+		{filter: "T_INTERSECTS(datetime, INTERVAL('2021-07-01', '..'))", match: false},
+		{filter: "T_INTERSECTS(datetime, INTERVAL('..', '2021-07-01'))", match: true},
+		{filter: "T_DISJOINT(datetime, INTERVAL('2022-01-01', '2022-12-31'))", match: true},
+		{filter: "T_EQUALS(datetime, TIMESTAMP('2021-06-15T10:30:00Z'))", match: true},
+		{filter: "A_CONTAINS(keywords, ('optical', 'esa'))", match: true},
+		{filter: "A_CONTAINS(keywords, ('optical', 'sar'))", match: false},
+		{filter: "A_CONTAINS(keywords, ('esa'))", match: true},
+		{filter: "A_OVERLAPS(keywords, ('sar'))", match: false},
+		{filter: "A_CONTAINEDBY(keywords, ('optical', 'sentinel', 'esa', 'l2a'))", match: true},
+		{filter: "A_OVERLAPS(keywords, ('sar', 'esa'))", match: true},
+		{filter: "A_EQUALS(keywords, ('optical', 'sentinel', 'esa'))", match: true},
+		{filter: "S_INTERSECTS(geometry, BBOX(10.5, 20.5, 12, 22))", match: true},
+		{filter: "S_INTERSECTS(geometry, BBOX(12, 22, 13, 23))", match: false},
+		{filter: "S_INTERSECTS(geometry, POINT(10.5 20.5))", match: true},
+		{filter: "S_INTERSECTS(geometry, POINT(11 20.5))", match: true},
+		{filter: "S_INTERSECTS(geometry, POINT(0 0))", match: false},
+		{filter: "S_INTERSECTS(geometry, LINESTRING(9 19, 12 22))", match: true},
+		{filter: "S_INTERSECTS(geometry, LINESTRING(9 19, 9 22))", match: false},
+		{filter: "S_INTERSECTS(geometry, POLYGON((0 0, 50 0, 50 50, 0 50, 0 0)))", match: true},
+		{filter: "S_INTERSECTS(geometry, POLYGON((0 0, 50 0, 50 50, 0 50, 0 0), (5 5, 45 5, 45 45, 5 45, 5 5)))", match: false},
+		{filter: "S_INTERSECTS(bbox, POINT(10.5 20.5))", match: true},
+		{filter: "S_DISJOINT(geometry, BBOX(12, 22, 13, 23))", match: true},
+		{filter: `{"op": "s_intersects", "args": [{"property": "geometry"}, {"bbox": [10.5, 20.5, 12, 22]}]}`, match: true},
+		{filter: `{"op": "<", "args": [{"property": "eo:cloud_cover"}, 10]}`, match: false},
+	}
+
+	resource := loadResource(t, testItem)
+	for _, c := range cases {
+		t.Run(c.filter, func(t *testing.T) {
+			filter, err := cql2.ParseFilter(c.filter)
+			require.NoError(t, err)
+
+			match, err := filter.Match(resource)
+			require.NoError(t, err)
+			assert.Equal(t, c.match, match)
+		})
+	}
+}
+
+func TestFilterMatchDatetimeInterval(t *testing.T) {
+	cases := []struct {
+		filter string
+		match  bool
+	}{
+		{filter: "T_INTERSECTS(datetime, TIMESTAMP('2021-06-15T00:00:00Z'))", match: true},
+		{filter: "T_INTERSECTS(datetime, INTERVAL('2022-01-01', '..'))", match: false},
+		{filter: "T_DURING(datetime, INTERVAL('2020-01-01', '2023-01-01'))", match: true},
+		{filter: "T_CONTAINS(datetime, DATE('2021-03-01'))", match: true},
+		{filter: "T_AFTER(datetime, DATE('2020-12-31'))", match: true},
+		{filter: "datetime IS NULL", match: false},
+	}
+
+	resource := loadResource(t, testIntervalItem)
+	for _, c := range cases {
+		t.Run(c.filter, func(t *testing.T) {
+			filter, err := cql2.ParseFilter(c.filter)
+			require.NoError(t, err)
+
+			match, err := filter.Match(resource)
+			require.NoError(t, err)
+			assert.Equal(t, c.match, match)
+		})
+	}
+}
+
+func TestFilterMatchErrors(t *testing.T) {
+	cases := []string{
+		"eo:cloud_cover + 1",
+		"S_CROSSES(geometry, BBOX(0, 0, 1, 1))",
+		"platform > 10",
+		"UNKNOWN_FUNCTION(platform)",
+		"ACCENTI(title) = ACCENTI('Ete a Geneve')",
+	}
+
+	resource := loadResource(t, testItem)
+	for _, c := range cases {
+		t.Run(c, func(t *testing.T) {
+			filter, err := cql2.ParseFilter(c)
+			require.NoError(t, err)
+
+			_, err = filter.Match(resource)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFilterMatchItem(t *testing.T) {
+	item := &stac.Item{
+		Version:  "1.0.0",
+		Id:       "item-id",
+		Geometry: map[string]any{"type": "Point", "coordinates": []float64{1, 2}},
+		Properties: map[string]any{
+			"datetime": time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339),
+			"gsd":      3,
+		},
+	}
+
+	filter := cql2.NewFilter(&cql2.Operation{
+		Op: "and",
+		Args: []cql2.Expression{
+			&cql2.Operation{Op: "=", Args: []cql2.Expression{&cql2.Property{Name: "gsd"}, &cql2.Literal{Value: 3.0}}},
+			&cql2.Operation{Op: "s_intersects", Args: []cql2.Expression{
+				&cql2.Property{Name: "geometry"},
+				&cql2.Geometry{Value: map[string]any{
+					"type":        "Polygon",
+					"coordinates": [][][]float64{{{0, 0}, {5, 0}, {5, 5}, {0, 5}, {0, 0}}},
+				}},
+			}},
+		},
+	})
+
+	match, err := filter.MatchItem(item)
+	require.NoError(t, err)
+	assert.True(t, match)
+
+	item.Properties["gsd"] = 5
+	match, err = filter.MatchItem(item)
+	require.NoError(t, err)
+	assert.False(t, match)
+}
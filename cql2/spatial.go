@@ -0,0 +1,314 @@
+package cql2
+
+import (
+	"fmt"
+	"math"
+)
+
+type point [2]float64
+
+// shape is a geometry broken into points, line strings, and polygons (lists of rings).
+type shape struct {
+	points   []point
+	lines    [][]point
+	polygons [][][]point
+}
+
+// toShape converts a GeoJSON geometry or a bounding box to a shape.
+func toShape(value any) (*shape, error) {
+	s := &shape{}
+	switch v := value.(type) {
+	case map[string]any:
+		if err := s.addGeometry(v); err != nil {
+			return nil, err
+		}
+	case []float64:
+		if err := s.addBBox(v); err != nil {
+			return nil, err
+		}
+	case []any:
+		bbox := make([]float64, len(v))
+		for i, item := range v {
+			number, ok := item.(float64)
+			if !ok {
+				return nil, fmt.Errorf("%w: %v", errUnsupportedGeometry, value)
+			}
+			bbox[i] = number
+		}
+		if err := s.addBBox(bbox); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: %v", errUnsupportedGeometry, value)
+	}
+	return s, nil
+}
+
+func (s *shape) addBBox(bbox []float64) error {
+	var minX, minY, maxX, maxY float64
+	switch len(bbox) {
+	case 4:
+		minX, minY, maxX, maxY = bbox[0], bbox[1], bbox[2], bbox[3]
+	case 6:
+		minX, minY, maxX, maxY = bbox[0], bbox[1], bbox[3], bbox[4]
+	default:
+		return fmt.Errorf("%w: bbox must have 4 or 6 numbers", errUnsupportedGeometry)
+	}
+	s.polygons = append(s.polygons, [][]point{{
+		{minX, minY}, {maxX, minY}, {maxX, maxY}, {minX, maxY}, {minX, minY},
+	}})
+	return nil
+}
+
+func (s *shape) addGeometry(geometry map[string]any) error {
+	geometryType, _ := geometry["type"].(string)
+	coordinates := geometry["coordinates"]
+	switch geometryType {
+	case "Point":
+		p, err := toPoint(coordinates)
+		if err != nil {
+			return err
+		}
+		s.points = append(s.points, p)
+	case "MultiPoint":
+		points, err := toPoints(coordinates)
+		if err != nil {
+			return err
+		}
+		s.points = append(s.points, points...)
+	case "LineString":
+		line, err := toPoints(coordinates)
+		if err != nil {
+			return err
+		}
+		s.lines = append(s.lines, line)
+	case "MultiLineString", "Polygon":
+		lines, err := toLines(coordinates)
+		if err != nil {
+			return err
+		}
+		if geometryType == "Polygon" {
+			s.polygons = append(s.polygons, lines)
+		} else {
+			s.lines = append(s.lines, lines...)
+		}
+	case "MultiPolygon":
+		list, ok := coordinates.([]any)
+		if !ok {
+			return fmt.Errorf("%w: invalid coordinates", errUnsupportedGeometry)
+		}
+		for _, item := range list {
+			rings, err := toLines(item)
+			if err != nil {
+				return err
+			}
+			s.polygons = append(s.polygons, rings)
+		}
+	case "GeometryCollection":
+		members, _ := geometry["geometries"].([]any)
+		for _, member := range members {
+			memberMap, ok := member.(map[string]any)
+			if !ok {
+				return fmt.Errorf("%w: invalid geometry collection member", errUnsupportedGeometry)
+			}
+			if err := s.addGeometry(memberMap); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%w: %q", errUnsupportedGeometry, geometryType)
+	}
+	return nil
+}
+
+func toPoint(value any) (point, error) {
+	list, ok := value.([]any)
+	if !ok || len(list) < 2 {
+		return point{}, fmt.Errorf("%w: invalid position %v", errUnsupportedGeometry, value)
+	}
+	x, xok := list[0].(float64)
+	y, yok := list[1].(float64)
+	if !xok || !yok {
+		return point{}, fmt.Errorf("%w: invalid position %v", errUnsupportedGeometry, value)
+	}
+	return point{x, y}, nil
+}
+
+func toPoints(value any) ([]point, error) {
+	list, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid coordinates", errUnsupportedGeometry)
+	}
+	points := make([]point, len(list))
+	for i, item := range list {
+		p, err := toPoint(item)
+		if err != nil {
+			return nil, err
+		}
+		points[i] = p
+	}
+	return points, nil
+}
+
+func toLines(value any) ([][]point, error) {
+	list, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid coordinates", errUnsupportedGeometry)
+	}
+	lines := make([][]point, len(list))
+	for i, item := range list {
+		line, err := toPoints(item)
+		if err != nil {
+			return nil, err
+		}
+		lines[i] = line
+	}
+	return lines, nil
+}
+
+// vertices returns every point that makes up the shape.
+func (s *shape) vertices() []point {
+	vertices := append([]point{}, s.points...)
+	for _, line := range s.lines {
+		vertices = append(vertices, line...)
+	}
+	for _, polygon := range s.polygons {
+		for _, ring := range polygon {
+			vertices = append(vertices, ring...)
+		}
+	}
+	return vertices
+}
+
+// segments returns the line segments of the shape's lines and polygon rings.
+func (s *shape) segments() [][2]point {
+	segments := [][2]point{}
+	add := func(line []point) {
+		for i := 1; i < len(line); i++ {
+			segments = append(segments, [2]point{line[i-1], line[i]})
+		}
+	}
+	for _, line := range s.lines {
+		add(line)
+	}
+	for _, polygon := range s.polygons {
+		for _, ring := range polygon {
+			add(ring)
+		}
+	}
+	return segments
+}
+
+func (s *shape) bounds() (point, point) {
+	min := point{math.Inf(1), math.Inf(1)}
+	max := point{math.Inf(-1), math.Inf(-1)}
+	for _, v := range s.vertices() {
+		min[0] = math.Min(min[0], v[0])
+		min[1] = math.Min(min[1], v[1])
+		max[0] = math.Max(max[0], v[0])
+		max[1] = math.Max(max[1], v[1])
+	}
+	return min, max
+}
+
+// intersects determines if two shapes share any point.
+func (s *shape) intersects(other *shape) bool {
+	aMin, aMax := s.bounds()
+	bMin, bMax := other.bounds()
+	if aMin[0] > bMax[0] || bMin[0] > aMax[0] || aMin[1] > bMax[1] || bMin[1] > aMax[1] {
+		return false
+	}
+
+	// a point of one shape on the other or in a polygon of the other
+	if s.coversAny(other.points) || other.coversAny(s.points) {
+		return true
+	}
+
+	// crossing or touching segments
+	otherSegments := other.segments()
+	for _, a := range s.segments() {
+		for _, b := range otherSegments {
+			if segmentsIntersect(a[0], a[1], b[0], b[1]) {
+				return true
+			}
+		}
+	}
+
+	// one shape entirely inside a polygon of the other
+	return s.polygonsContainAny(other.vertices()) || other.polygonsContainAny(s.vertices())
+}
+
+// coversAny determines if any of the points are on the shape.
+func (s *shape) coversAny(points []point) bool {
+	for _, p := range points {
+		for _, q := range s.points {
+			if p == q {
+				return true
+			}
+		}
+		for _, segment := range s.segments() {
+			if onSegment(segment[0], segment[1], p) {
+				return true
+			}
+		}
+	}
+	return s.polygonsContainAny(points)
+}
+
+func (s *shape) polygonsContainAny(points []point) bool {
+	for _, p := range points {
+		for _, polygon := range s.polygons {
+			if polygonContains(polygon, p) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// polygonContains determines if a point is inside a polygon (excluding holes) using ray casting.
+func polygonContains(rings [][]point, p point) bool {
+	if len(rings) == 0 || !ringContains(rings[0], p) {
+		return false
+	}
+	for _, hole := range rings[1:] {
+		if ringContains(hole, p) {
+			return false
+		}
+	}
+	return true
+}
+
+func ringContains(ring []point, p point) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a[1] > p[1]) != (b[1] > p[1]) && p[0] < (b[0]-a[0])*(p[1]-a[1])/(b[1]-a[1])+a[0] {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func orientation(a point, b point, c point) float64 {
+	return (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+}
+
+func onSegment(a point, b point, p point) bool {
+	if orientation(a, b, p) != 0 {
+		return false
+	}
+	return math.Min(a[0], b[0]) <= p[0] && p[0] <= math.Max(a[0], b[0]) &&
+		math.Min(a[1], b[1]) <= p[1] && p[1] <= math.Max(a[1], b[1])
+}
+
+func segmentsIntersect(a point, b point, c point, d point) bool {
+	o1 := orientation(a, b, c)
+	o2 := orientation(a, b, d)
+	o3 := orientation(c, d, a)
+	o4 := orientation(c, d, b)
+	if ((o1 > 0 && o2 < 0) || (o1 < 0 && o2 > 0)) && ((o3 > 0 && o4 < 0) || (o3 < 0 && o4 > 0)) {
+		return true
+	}
+	return onSegment(a, b, c) || onSegment(a, b, d) || onSegment(c, d, a) || onSegment(c, d, b)
+}
@@ -0,0 +1,757 @@
+package cql2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var comparisonOps = map[string]bool{
+	"=":  true,
+	"<>": true,
+	"<":  true,
+	"<=": true,
+	">":  true,
+	">=": true,
+}
+
+var arithmeticOps = map[string]bool{
+	"+":   true,
+	"-":   true,
+	"*":   true,
+	"/":   true,
+	"%":   true,
+	"div": true,
+}
+
+// functionOps are the standard operators that use function call syntax in CQL2-Text.
+var functionOps = map[string]bool{
+	"s_intersects":   true,
+	"s_equals":       true,
+	"s_disjoint":     true,
+	"s_touches":      true,
+	"s_within":       true,
+	"s_overlaps":     true,
+	"s_crosses":      true,
+	"s_contains":     true,
+	"t_after":        true,
+	"t_before":       true,
+	"t_contains":     true,
+	"t_disjoint":     true,
+	"t_during":       true,
+	"t_equals":       true,
+	"t_finishedby":   true,
+	"t_finishes":     true,
+	"t_intersects":   true,
+	"t_meets":        true,
+	"t_metby":        true,
+	"t_overlappedby": true,
+	"t_overlaps":     true,
+	"t_startedby":    true,
+	"t_starts":       true,
+	"a_equals":       true,
+	"a_contains":     true,
+	"a_containedby":  true,
+	"a_overlaps":     true,
+	"casei":          true,
+	"accenti":        true,
+}
+
+// arrayOps are the operators that take array arguments.
+var arrayOps = map[string]bool{
+	"a_equals":      true,
+	"a_contains":    true,
+	"a_containedby": true,
+	"a_overlaps":    true,
+}
+
+// arrayArgs converts the arguments of an array operator that were parsed as a
+// single parenthesized value (e.g. ('a')) into single item arrays.
+func arrayArgs(args []Expression) []Expression {
+	converted := make([]Expression, len(args))
+	for i, arg := range args {
+		switch arg.(type) {
+		case *Array, *Property, *Operation:
+			converted[i] = arg
+		default:
+			converted[i] = &Array{Items: []Expression{arg}}
+		}
+	}
+	return converted
+}
+
+func (e *Literal) Text() string {
+	switch v := e.Value.(type) {
+	case string:
+		return quoteString(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (e *Property) Text() string {
+	upper := strings.ToUpper(e.Name)
+	if isIdentifier(e.Name) && !keywords[upper] && !isConstructor(upper) {
+		return e.Name
+	}
+	return `"` + strings.ReplaceAll(e.Name, `"`, `""`) + `"`
+}
+
+func (e *Timestamp) Text() string {
+	return fmt.Sprintf("TIMESTAMP(%s)", quoteString(e.Value.UTC().Format(timestampLayout)))
+}
+
+func (e *Date) Text() string {
+	return fmt.Sprintf("DATE(%s)", quoteString(e.Value.Format(dateLayout)))
+}
+
+func (e *Interval) Text() string {
+	return fmt.Sprintf("INTERVAL(%s, %s)", e.Start.Text(), e.End.Text())
+}
+
+func (e *Geometry) Text() string {
+	wkt, err := encodeWKT(e.normalized())
+	if err != nil {
+		return fmt.Sprintf("/* %s */", err)
+	}
+	return wkt
+}
+
+func (e *BBox) Text() string {
+	return fmt.Sprintf("BBOX(%s)", joinNumbers(e.Values, ", "))
+}
+
+func (e *Array) Text() string {
+	return "(" + joinText(e.Items) + ")"
+}
+
+func (e *Operation) Text() string {
+	op := strings.ToLower(e.Op)
+	args := e.Args
+	switch {
+	case (op == "and" || op == "or") && len(args) > 0:
+		parts := make([]string, len(args))
+		for i, arg := range args {
+			parts[i] = groupText(arg)
+		}
+		return strings.Join(parts, " "+strings.ToUpper(op)+" ")
+	case op == "not" && len(args) == 1:
+		return "NOT " + groupText(args[0])
+	case comparisonOps[op] && len(args) == 2:
+		return fmt.Sprintf("%s %s %s", args[0].Text(), op, args[1].Text())
+	case arithmeticOps[op] && len(args) == 2:
+		return fmt.Sprintf("(%s %s %s)", args[0].Text(), strings.ToUpper(op), args[1].Text())
+	case op == "like" && len(args) == 2:
+		return fmt.Sprintf("%s LIKE %s", args[0].Text(), args[1].Text())
+	case op == "between" && len(args) == 3:
+		return fmt.Sprintf("%s BETWEEN %s AND %s", args[0].Text(), args[1].Text(), args[2].Text())
+	case op == "in" && len(args) == 2:
+		return fmt.Sprintf("%s IN %s", args[0].Text(), args[1].Text())
+	case op == "isnull" && len(args) == 1:
+		return fmt.Sprintf("%s IS NULL", args[0].Text())
+	case functionOps[op]:
+		return fmt.Sprintf("%s(%s)", strings.ToUpper(op), joinText(args))
+	default:
+		return fmt.Sprintf("%s(%s)", e.Op, joinText(args))
+	}
+}
+
+// groupText wraps boolean combinations in parentheses so they can be nested.
+func groupText(expr Expression) string {
+	if op, ok := expr.(*Operation); ok {
+		switch strings.ToLower(op.Op) {
+		case "and", "or", "not":
+			return "(" + op.Text() + ")"
+		}
+	}
+	return expr.Text()
+}
+
+func joinText(items []Expression) string {
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = item.Text()
+	}
+	return strings.Join(parts, ", ")
+}
+
+func joinNumbers(values []float64, sep string) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return strings.Join(parts, sep)
+}
+
+func quoteString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// isConstructor determines if an upper case name is a temporal, bbox, or WKT constructor.
+func isConstructor(upper string) bool {
+	switch upper {
+	case "TIMESTAMP", "DATE", "INTERVAL", "BBOX":
+		return true
+	}
+	_, ok := wktTypes[upper]
+	return ok
+}
+
+func isIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if i == 0 && !isIdentifierStart(r) {
+			return false
+		}
+		if !isIdentifierPart(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isIdentifierStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_'
+}
+
+func isIdentifierPart(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == ':' || r == '.'
+}
+
+var keywords = map[string]bool{
+	"AND":     true,
+	"OR":      true,
+	"NOT":     true,
+	"LIKE":    true,
+	"BETWEEN": true,
+	"IN":      true,
+	"IS":      true,
+	"NULL":    true,
+	"TRUE":    true,
+	"FALSE":   true,
+	"DIV":     true,
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenQuotedIdentifier
+	tokenString
+	tokenNumber
+	tokenPunct
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t token) is(kind tokenKind, value string) bool {
+	if t.kind != kind {
+		return false
+	}
+	if kind == tokenIdentifier {
+		return strings.EqualFold(t.value, value)
+	}
+	return t.value == value
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of input"
+	}
+	return fmt.Sprintf("%q at position %d", t.value, t.pos)
+}
+
+func tokenize(input string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i += 1
+		case r == '\'':
+			value, next, err := readQuoted(runes, i, '\'')
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value, pos: i})
+			i = next
+		case r == '"':
+			value, next, err := readQuoted(runes, i, '"')
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenQuotedIdentifier, value: value, pos: i})
+			i = next
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i += 1
+			}
+			if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+				i += 1
+				if i < len(runes) && (runes[i] == '+' || runes[i] == '-') {
+					i += 1
+				}
+				for i < len(runes) && unicode.IsDigit(runes[i]) {
+					i += 1
+				}
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: string(runes[start:i]), pos: start})
+		case isIdentifierStart(r):
+			start := i
+			for i < len(runes) && isIdentifierPart(runes[i]) {
+				i += 1
+			}
+			tokens = append(tokens, token{kind: tokenIdentifier, value: string(runes[start:i]), pos: start})
+		default:
+			if !strings.ContainsRune("=<>()+-*/%,", r) {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+			}
+			value := string(r)
+			if i+1 < len(runes) {
+				if pair := string(runes[i : i+2]); pair == "<>" || pair == "<=" || pair == ">=" {
+					value = pair
+				}
+			}
+			tokens = append(tokens, token{kind: tokenPunct, value: value, pos: i})
+			i += len(value)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+func readQuoted(runes []rune, start int, quote rune) (string, int, error) {
+	builder := &strings.Builder{}
+	for i := start + 1; i < len(runes); i++ {
+		if runes[i] == quote {
+			if i+1 < len(runes) && runes[i+1] == quote {
+				builder.WriteRune(quote)
+				i += 1
+				continue
+			}
+			return builder.String(), i + 1, nil
+		}
+		builder.WriteRune(runes[i])
+	}
+	return "", 0, fmt.Errorf("unterminated quote starting at position %d", start)
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// ParseText parses a CQL2-Text expression.
+func ParseText(input string) (Expression, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CQL2-Text: %w", err)
+	}
+	p := &parser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CQL2-Text: %w", err)
+	}
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, fmt.Errorf("failed to parse CQL2-Text: unexpected %s", next)
+	}
+	return expr, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos += 1
+	}
+	return t
+}
+
+func (p *parser) accept(kind tokenKind, value string) bool {
+	if p.peek().is(kind, value) {
+		p.pos += 1
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(kind tokenKind, value string) error {
+	if !p.accept(kind, value) {
+		return fmt.Errorf("expected %q, got %s", value, p.peek())
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	return p.parseBinary("or", p.parseAnd)
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	return p.parseBinary("and", p.parseNot)
+}
+
+func (p *parser) parseBinary(op string, parseOperand func() (Expression, error)) (Expression, error) {
+	first, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	args := []Expression{first}
+	for p.accept(tokenIdentifier, op) {
+		arg, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	if len(args) == 1 {
+		return first, nil
+	}
+	return &Operation{Op: op, Args: args}, nil
+}
+
+func (p *parser) parseNot() (Expression, error) {
+	if p.accept(tokenIdentifier, "not") {
+		arg, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return not(arg), nil
+	}
+	return p.parsePredicate()
+}
+
+func not(expr Expression) Expression {
+	return &Operation{Op: "not", Args: []Expression{expr}}
+}
+
+func (p *parser) parsePredicate() (Expression, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	next := p.peek()
+	if next.kind == tokenPunct && comparisonOps[next.value] {
+		p.next()
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return &Operation{Op: next.value, Args: []Expression{left, right}}, nil
+	}
+
+	if p.accept(tokenIdentifier, "is") {
+		negate := p.accept(tokenIdentifier, "not")
+		if err := p.expect(tokenIdentifier, "null"); err != nil {
+			return nil, err
+		}
+		var expr Expression = &Operation{Op: "isNull", Args: []Expression{left}}
+		if negate {
+			expr = not(expr)
+		}
+		return expr, nil
+	}
+
+	negate := false
+	if p.peek().is(tokenIdentifier, "not") {
+		following := p.tokens[p.pos+1]
+		if following.is(tokenIdentifier, "like") || following.is(tokenIdentifier, "between") || following.is(tokenIdentifier, "in") {
+			p.next()
+			negate = true
+		}
+	}
+
+	var expr Expression
+	switch {
+	case p.accept(tokenIdentifier, "like"):
+		pattern, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		expr = &Operation{Op: "like", Args: []Expression{left, pattern}}
+	case p.accept(tokenIdentifier, "between"):
+		low, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenIdentifier, "and"); err != nil {
+			return nil, err
+		}
+		high, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		expr = &Operation{Op: "between", Args: []Expression{left, low, high}}
+	case p.accept(tokenIdentifier, "in"):
+		if err := p.expect(tokenPunct, "("); err != nil {
+			return nil, err
+		}
+		items, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		expr = &Operation{Op: "in", Args: []Expression{left, &Array{Items: items}}}
+	default:
+		return left, nil
+	}
+
+	if negate {
+		expr = not(expr)
+	}
+	return expr, nil
+}
+
+// parseList parses a comma-delimited list of expressions after an opening parenthesis.
+func (p *parser) parseList() ([]Expression, error) {
+	items := []Expression{}
+	if p.accept(tokenPunct, ")") {
+		return items, nil
+	}
+	for {
+		item, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		if p.accept(tokenPunct, ")") {
+			return items, nil
+		}
+		if err := p.expect(tokenPunct, ","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseAdditive() (Expression, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		next := p.peek()
+		if !next.is(tokenPunct, "+") && !next.is(tokenPunct, "-") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &Operation{Op: next.value, Args: []Expression{left, right}}
+	}
+}
+
+func (p *parser) parseMultiplicative() (Expression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		next := p.peek()
+		op := ""
+		switch {
+		case next.is(tokenPunct, "*"), next.is(tokenPunct, "/"), next.is(tokenPunct, "%"):
+			op = next.value
+		case next.is(tokenIdentifier, "div"):
+			op = "div"
+		default:
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &Operation{Op: op, Args: []Expression{left, right}}
+	}
+}
+
+func (p *parser) parseUnary() (Expression, error) {
+	if p.accept(tokenPunct, "-") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if literal, ok := operand.(*Literal); ok {
+			if number, ok := literal.Value.(float64); ok {
+				return &Literal{Value: -number}, nil
+			}
+		}
+		return &Operation{Op: "*", Args: []Expression{&Literal{Value: -1.0}, operand}}, nil
+	}
+	if p.accept(tokenPunct, "+") {
+		return p.parseUnary()
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expression, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return &Literal{Value: t.value}, nil
+	case tokenNumber:
+		number, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s", t)
+		}
+		return &Literal{Value: number}, nil
+	case tokenQuotedIdentifier:
+		return &Property{Name: t.value}, nil
+	case tokenPunct:
+		if t.value != "(" {
+			return nil, fmt.Errorf("unexpected %s", t)
+		}
+		items, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		if len(items) == 1 {
+			return items[0], nil
+		}
+		return &Array{Items: items}, nil
+	case tokenIdentifier:
+		return p.parseIdentifier(t)
+	default:
+		return nil, fmt.Errorf("unexpected %s", t)
+	}
+}
+
+func (p *parser) parseIdentifier(t token) (Expression, error) {
+	upper := strings.ToUpper(t.value)
+	if isConstructor(upper) && !p.peek().is(tokenPunct, "(") && !isDimension(p.peek()) {
+		// a property that shares a name with a constructor (e.g. bbox)
+		return &Property{Name: t.value}, nil
+	}
+
+	switch upper {
+	case "TRUE":
+		return &Literal{Value: true}, nil
+	case "FALSE":
+		return &Literal{Value: false}, nil
+	case "TIMESTAMP", "DATE":
+		if err := p.expect(tokenPunct, "("); err != nil {
+			return nil, err
+		}
+		value := p.next()
+		if value.kind != tokenString {
+			return nil, fmt.Errorf("expected string for %s, got %s", upper, value)
+		}
+		if err := p.expect(tokenPunct, ")"); err != nil {
+			return nil, err
+		}
+		if upper == "DATE" {
+			date, err := time.Parse(dateLayout, value.value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid date %s: %w", value, err)
+			}
+			return &Date{Value: date}, nil
+		}
+		timestamp, err := time.Parse(time.RFC3339Nano, value.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp %s: %w", value, err)
+		}
+		return &Timestamp{Value: timestamp}, nil
+	case "INTERVAL":
+		if err := p.expect(tokenPunct, "("); err != nil {
+			return nil, err
+		}
+		bounds, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("expected two values for INTERVAL at position %d", t.pos)
+		}
+		return &Interval{Start: bounds[0], End: bounds[1]}, nil
+	case "BBOX":
+		if err := p.expect(tokenPunct, "("); err != nil {
+			return nil, err
+		}
+		values := []float64{}
+		for {
+			number, err := p.parseNumber()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, number)
+			if p.accept(tokenPunct, ")") {
+				break
+			}
+			if err := p.expect(tokenPunct, ","); err != nil {
+				return nil, err
+			}
+		}
+		if len(values) != 4 && len(values) != 6 {
+			return nil, fmt.Errorf("expected 4 or 6 numbers for BBOX at position %d", t.pos)
+		}
+		return &BBox{Values: values}, nil
+	}
+
+	if geometryType, ok := wktTypes[upper]; ok {
+		geometry, err := p.parseWKT(geometryType)
+		if err != nil {
+			return nil, err
+		}
+		return &Geometry{Value: geometry}, nil
+	}
+
+	if keywords[upper] {
+		return nil, fmt.Errorf("unexpected %s", t)
+	}
+
+	if p.accept(tokenPunct, "(") {
+		args, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		op := t.value
+		if functionOps[strings.ToLower(op)] {
+			op = strings.ToLower(op)
+		}
+		if arrayOps[op] {
+			args = arrayArgs(args)
+		}
+		return &Operation{Op: op, Args: args}, nil
+	}
+
+	return &Property{Name: t.value}, nil
+}
+
+func (p *parser) parseNumber() (float64, error) {
+	sign := 1.0
+	if p.accept(tokenPunct, "-") {
+		sign = -1
+	} else {
+		p.accept(tokenPunct, "+")
+	}
+	t := p.next()
+	if t.kind != tokenNumber {
+		return 0, fmt.Errorf("expected number, got %s", t)
+	}
+	number, err := strconv.ParseFloat(t.value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %s", t)
+	}
+	return sign * number, nil
+}
@@ -0,0 +1,197 @@
+package cql2
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// wktTypes maps WKT geometry keywords to GeoJSON geometry types.
+var wktTypes = map[string]string{
+	"POINT":              "Point",
+	"LINESTRING":         "LineString",
+	"POLYGON":            "Polygon",
+	"MULTIPOINT":         "MultiPoint",
+	"MULTILINESTRING":    "MultiLineString",
+	"MULTIPOLYGON":       "MultiPolygon",
+	"GEOMETRYCOLLECTION": "GeometryCollection",
+}
+
+// isDimension determines if a token is a WKT dimension marker.
+func isDimension(t token) bool {
+	return t.is(tokenIdentifier, "z") || t.is(tokenIdentifier, "m") || t.is(tokenIdentifier, "zm")
+}
+
+// parseWKT parses the remainder of a WKT geometry after the type keyword.
+func (p *parser) parseWKT(geometryType string) (map[string]any, error) {
+	// dimension markers are accepted but the coordinates determine the dimension
+	if isDimension(p.peek()) {
+		p.next()
+	}
+
+	if geometryType == "GeometryCollection" {
+		if err := p.expect(tokenPunct, "("); err != nil {
+			return nil, err
+		}
+		geometries := []any{}
+		for {
+			t := p.next()
+			memberType, ok := wktTypes[strings.ToUpper(t.value)]
+			if t.kind != tokenIdentifier || !ok {
+				return nil, fmt.Errorf("expected geometry, got %s", t)
+			}
+			geometry, err := p.parseWKT(memberType)
+			if err != nil {
+				return nil, err
+			}
+			geometries = append(geometries, geometry)
+			if p.accept(tokenPunct, ")") {
+				break
+			}
+			if err := p.expect(tokenPunct, ","); err != nil {
+				return nil, err
+			}
+		}
+		return map[string]any{"type": geometryType, "geometries": geometries}, nil
+	}
+
+	nested, err := p.parseNested()
+	if err != nil {
+		return nil, err
+	}
+
+	var coordinates any
+	switch geometryType {
+	case "Point":
+		if len(nested) != 1 {
+			return nil, errors.New("expected a single position for POINT")
+		}
+		coordinates = nested[0]
+	case "MultiPoint":
+		// both MULTIPOINT (1 2, 3 4) and MULTIPOINT ((1 2), (3 4)) are allowed
+		points := make([]any, len(nested))
+		for i, point := range nested {
+			if list, ok := point.([]any); ok && len(list) == 1 {
+				if position, ok := list[0].([]any); ok {
+					point = position
+				}
+			}
+			points[i] = point
+		}
+		coordinates = points
+	default:
+		coordinates = nested
+	}
+	return map[string]any{"type": geometryType, "coordinates": coordinates}, nil
+}
+
+// parseNested parses parenthesized lists of positions.  Positions are returned as
+// []any with float64 values so that they match decoded GeoJSON.
+func (p *parser) parseNested() ([]any, error) {
+	if err := p.expect(tokenPunct, "("); err != nil {
+		return nil, err
+	}
+	items := []any{}
+	for {
+		if p.peek().is(tokenPunct, "(") {
+			list, err := p.parseNested()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, list)
+		} else {
+			position := []any{}
+			for {
+				next := p.peek()
+				if !next.is(tokenPunct, "-") && !next.is(tokenPunct, "+") && next.kind != tokenNumber {
+					break
+				}
+				number, err := p.parseNumber()
+				if err != nil {
+					return nil, err
+				}
+				position = append(position, number)
+			}
+			if len(position) < 2 {
+				return nil, fmt.Errorf("expected position, got %s", p.peek())
+			}
+			items = append(items, position)
+		}
+		if p.accept(tokenPunct, ")") {
+			return items, nil
+		}
+		if err := p.expect(tokenPunct, ","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func encodeWKT(geometry map[string]any) (string, error) {
+	geometryType, _ := geometry["type"].(string)
+	keyword := ""
+	for k, v := range wktTypes {
+		if v == geometryType {
+			keyword = k
+		}
+	}
+	if keyword == "" {
+		return "", fmt.Errorf("unsupported geometry type %q", geometryType)
+	}
+
+	if geometryType == "GeometryCollection" {
+		members, _ := geometry["geometries"].([]any)
+		parts := make([]string, len(members))
+		for i, member := range members {
+			memberMap, ok := member.(map[string]any)
+			if !ok {
+				return "", errors.New("invalid geometry collection member")
+			}
+			wkt, err := encodeWKT(memberMap)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = wkt
+		}
+		return keyword + "(" + strings.Join(parts, ", ") + ")", nil
+	}
+
+	coordinates, err := encodeCoordinates(geometry["coordinates"])
+	if err != nil {
+		return "", err
+	}
+	if geometryType == "Point" {
+		coordinates = "(" + coordinates + ")"
+	}
+	return keyword + coordinates, nil
+}
+
+// encodeCoordinates encodes a position as "x y" and nested lists as "(a, b)".
+func encodeCoordinates(value any) (string, error) {
+	list, ok := value.([]any)
+	if !ok {
+		return "", fmt.Errorf("invalid coordinates %v", value)
+	}
+	if len(list) > 0 {
+		if _, isNumber := list[0].(float64); isNumber {
+			parts := make([]string, len(list))
+			for i, v := range list {
+				number, ok := v.(float64)
+				if !ok {
+					return "", fmt.Errorf("invalid position %v", value)
+				}
+				parts[i] = strconv.FormatFloat(number, 'f', -1, 64)
+			}
+			return strings.Join(parts, " "), nil
+		}
+	}
+	parts := make([]string, len(list))
+	for i, v := range list {
+		encoded, err := encodeCoordinates(v)
+		if err != nil {
+			return "", err
+		}
+		parts[i] = encoded
+	}
+	return "(" + strings.Join(parts, ", ") + ")", nil
+}
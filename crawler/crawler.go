@@ -6,30 +6,19 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"math"
-	"math/rand"
 	"net/http"
 	"os"
 	"runtime"
-	"syscall"
 	"time"
 
-	"github.com/hashicorp/go-retryablehttp"
+	"github.com/planetlabs/go-stac/internal/fetch"
 	"github.com/planetlabs/go-stac/internal/normurl"
-	"github.com/tschaub/retry"
 )
 
-var httpClient = retryablehttp.NewClient()
-
-func init() {
-	httpClient.Logger = nil
-}
-
 // ErrStopRecursion is returned by the visitor when it wants to stop recursing.
 var ErrStopRecursion = errors.New("stop recursion")
 
-func load(entry *normurl.Locator, loc *normurl.Locator, value interface{}) error {
+func load(entry *normurl.Locator, loc *normurl.Locator, header http.Header, value interface{}) error {
 	if loc.IsFilepath() {
 		if !entry.IsFilepath() {
 			return fmt.Errorf("cannot crawl file %s in non-file mode", loc)
@@ -40,7 +29,7 @@ func load(entry *normurl.Locator, loc *normurl.Locator, value interface{}) error
 	if entry.IsFilepath() {
 		return fmt.Errorf("cannot crawl URL %s in file mode", loc)
 	}
-	return loadUrl(loc, value)
+	return loadUrl(loc, header, value)
 }
 
 func loadFile(loc *normurl.Locator, value any) error {
@@ -56,48 +45,11 @@ func loadFile(loc *normurl.Locator, value any) error {
 	return nil
 }
 
-func loadUrl(loc *normurl.Locator, value any) error {
+func loadUrl(loc *normurl.Locator, header http.Header, value any) error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*30)
 	defer cancel()
 
-	retries := 5
-
-	return retry.Limit(ctx, retries, func(ctx context.Context, attempt int) error {
-		err := tryLoadUrl(loc, value)
-		if err == nil {
-			return nil
-		}
-
-		// these come when parsing the response body
-		if !errors.Is(err, syscall.ECONNRESET) {
-			return retry.Stop(err)
-		}
-
-		jitter := time.Duration(rand.Float64()) * time.Second
-		time.Sleep(time.Second*time.Duration(math.Pow(2, float64(attempt))) + jitter)
-		return err
-	})
-}
-
-func tryLoadUrl(loc *normurl.Locator, value any) error {
-	resp, err := httpClient.Get(loc.String())
-	if err != nil {
-		return err
-	}
-	defer func() {
-		_, _ = io.Copy(io.Discard, resp.Body)
-		_ = resp.Body.Close()
-	}()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected response for %s: %d", loc, resp.StatusCode)
-	}
-
-	jsonErr := json.NewDecoder(resp.Body).Decode(value)
-	if jsonErr != nil {
-		return fmt.Errorf("failed to parse %s: %w", loc, jsonErr)
-	}
-	return nil
+	return fetch.JSON(ctx, fetch.DefaultClient, &fetch.Request{Location: loc.String(), Header: header}, value)
 }
 
 // ResourceInfo includes information about how the resource was accessed.
@@ -128,12 +80,21 @@ func wrapErrorHandler(handler ErrorHandler) ErrorHandler {
 	}
 }
 
+// ItemFilter determines which items are passed to the visitor.  A *cql2.Filter
+// can be used to filter items with a CQL2 expression.
+type ItemFilter interface {
+	// Match returns true if the item should be visited.
+	Match(Resource) (bool, error)
+}
+
 // Crawler crawls STAC resources.
 type Crawler struct {
 	visitor      Visitor
 	queue        Queue
 	filter       func(string) bool
+	itemFilter   ItemFilter
 	errorHandler ErrorHandler
+	header       http.Header
 }
 
 // Options for creating a crawler.
@@ -144,6 +105,10 @@ type Options struct {
 	// not be called.
 	Filter func(string) bool
 
+	// Optional filter for items.  Items that do not match will not be passed to the
+	// visitor.  Any error from the filter is passed to the error handler.
+	ItemFilter ItemFilter
+
 	// Optional function to handle any errors during the crawl.  By default, any error
 	// will stop the crawl.  To continue crawling on error, provide a function that
 	// returns nil.  The special ErrStopRecursion will stop the crawler from recursing deeper
@@ -154,6 +119,9 @@ type Options struct {
 	// will be used.  When running a crawl across multiple processes, it can be useful
 	// to provide a queue that is shared across processes.
 	Queue Queue
+
+	// Optional headers to include with requests for remote resources.
+	Header http.Header
 }
 
 func applyOptions(options []*Options) *Options {
@@ -165,9 +133,15 @@ func applyOptions(options []*Options) *Options {
 		if option.Filter != nil {
 			o.Filter = option.Filter
 		}
+		if option.ItemFilter != nil {
+			o.ItemFilter = option.ItemFilter
+		}
 		if option.ErrorHandler != nil {
 			o.ErrorHandler = option.ErrorHandler
 		}
+		if option.Header != nil {
+			o.Header = option.Header
+		}
 	}
 	return o
 }
@@ -193,8 +167,10 @@ func New(visitor Visitor, options ...*Options) (*Crawler, error) {
 	c := &Crawler{
 		visitor:      visitor,
 		filter:       opt.Filter,
+		itemFilter:   opt.ItemFilter,
 		queue:        queue,
 		errorHandler: wrapErrorHandler(opt.ErrorHandler),
+		header:       opt.Header,
 	}
 	queue.Handle(c.crawl)
 
@@ -281,11 +257,18 @@ func (c *Crawler) crawl(t *Task) error {
 
 func (c *Crawler) crawlResource(task *Task) ([]*Task, error) {
 	resource := Resource{}
-	loadErr := load(task.entry, task.resource, &resource)
+	loadErr := load(task.entry, task.resource, c.header, &resource)
 	if loadErr != nil {
 		return nil, c.errorHandler(loadErr)
 	}
 
+	if resource.Type() == Item {
+		match, err := c.matchItem(resource)
+		if err != nil || !match {
+			return nil, err
+		}
+	}
+
 	info := &ResourceInfo{Entry: task.entry.String(), Location: task.resource.String()}
 	if err := c.errorHandler(c.visitor(resource, info)); err != nil {
 		if errors.Is(err, ErrStopRecursion) {
@@ -354,9 +337,23 @@ func (c *Crawler) crawlResource(task *Task) ([]*Task, error) {
 	return tasks, nil
 }
 
+// matchItem applies the item filter.  Errors are passed to the error handler and any
+// unhandled error is returned.
+func (c *Crawler) matchItem(resource Resource) (bool, error) {
+	if c.itemFilter == nil {
+		return true, nil
+	}
+	match, err := c.itemFilter.Match(resource)
+	if err != nil {
+		id, _ := resource["id"].(string)
+		return false, c.errorHandler(fmt.Errorf("failed to filter item %s: %w", id, err))
+	}
+	return match, nil
+}
+
 func (c *Crawler) crawlCollections(task *Task) ([]*Task, error) {
 	response := &featureCollectionsResponse{}
-	loadErr := load(task.entry, task.resource, response)
+	loadErr := load(task.entry, task.resource, c.header, response)
 	if loadErr != nil {
 		return nil, c.errorHandler(loadErr)
 	}
@@ -443,7 +440,7 @@ func (c *Crawler) crawlCollections(task *Task) ([]*Task, error) {
 
 func (c *Crawler) crawlChildren(task *Task) ([]*Task, error) {
 	response := &childrenResponse{}
-	loadErr := load(task.entry, task.resource, response)
+	loadErr := load(task.entry, task.resource, c.header, response)
 	if loadErr != nil {
 		return nil, c.errorHandler(loadErr)
 	}
@@ -502,7 +499,7 @@ func (c *Crawler) crawlChildren(task *Task) ([]*Task, error) {
 
 func (c *Crawler) crawlFeatures(task *Task) ([]*Task, error) {
 	response := &featureCollectionResponse{}
-	loadErr := load(task.entry, task.resource, response)
+	loadErr := load(task.entry, task.resource, c.header, response)
 	if loadErr != nil {
 		return nil, c.errorHandler(loadErr)
 	}
@@ -539,6 +536,14 @@ func (c *Crawler) crawlFeatures(task *Task) ([]*Task, error) {
 			}
 		}
 
+		match, matchErr := c.matchItem(resource)
+		if matchErr != nil {
+			return nil, matchErr
+		}
+		if !match {
+			continue
+		}
+
 		info := &ResourceInfo{Entry: task.entry.String(), Location: selfLinkLoc.String()}
 		if err := c.errorHandler(c.visitor(resource, info)); err != nil {
 			if errors.Is(err, ErrStopRecursion) {
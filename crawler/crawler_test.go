@@ -11,6 +11,7 @@ import (
 	"sync/atomic"
 	"testing"
 
+	"github.com/planetlabs/go-stac/cql2"
 	"github.com/planetlabs/go-stac/crawler"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -110,6 +111,49 @@ func TestCrawlerFilterCollection(t *testing.T) {
 	assert.True(t, visitedCatalog)
 }
 
+func TestCrawlerItemFilter(t *testing.T) {
+	cases := []struct {
+		filter string
+		count  uint64
+	}{
+		{filter: "datetime < TIMESTAMP('2023-01-01T00:00:00Z')", count: 3},
+		{filter: "datetime > TIMESTAMP('2023-01-01T00:00:00Z')", count: 2},
+		{filter: "S_INTERSECTS(geometry, BBOX(-1, -1, 1, 1))", count: 3},
+		{filter: "S_INTERSECTS(geometry, BBOX(1, 1, 2, 2))", count: 2},
+	}
+
+	for _, c := range cases {
+		t.Run(c.filter, func(t *testing.T) {
+			filter, err := cql2.ParseFilter(c.filter)
+			require.NoError(t, err)
+
+			count := uint64(0)
+			visitor := func(resource crawler.Resource, info *crawler.ResourceInfo) error {
+				atomic.AddUint64(&count, 1)
+				return nil
+			}
+
+			entry := "testdata/v1.0.0/catalog-with-collection-of-items.json"
+			crawlErr := crawler.Crawl(entry, visitor, &crawler.Options{ItemFilter: filter})
+			require.NoError(t, crawlErr)
+			assert.Equal(t, c.count, count)
+		})
+	}
+}
+
+func TestCrawlerItemFilterError(t *testing.T) {
+	filter, err := cql2.ParseFilter("S_CROSSES(geometry, BBOX(-1, -1, 1, 1))")
+	require.NoError(t, err)
+
+	visitor := func(resource crawler.Resource, info *crawler.ResourceInfo) error {
+		return nil
+	}
+
+	entry := "testdata/v1.0.0/catalog-with-collection-of-items.json"
+	crawlErr := crawler.Crawl(entry, visitor, &crawler.Options{ItemFilter: filter})
+	assert.ErrorContains(t, crawlErr, "failed to filter item item-in-collection")
+}
+
 func TestCrawlerHTTP(t *testing.T) {
 	server := httptest.NewServer(http.FileServer(http.Dir("testdata")))
 	defer server.Close()
@@ -142,6 +186,32 @@ func TestCrawlerHTTP(t *testing.T) {
 	assert.True(t, visitedItem)
 }
 
+func TestCrawlerHTTPHeader(t *testing.T) {
+	files := http.FileServer(http.Dir("testdata"))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		files.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	count := uint64(0)
+	visitor := func(resource crawler.Resource, info *crawler.ResourceInfo) error {
+		atomic.AddUint64(&count, 1)
+		return nil
+	}
+	entry := server.URL + "/v1.0.0/catalog-with-collection-of-items.json"
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer secret")
+
+	err := crawler.Crawl(entry, visitor, &crawler.Options{Header: header})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), count)
+}
+
 func TestCrawlerHTTPRetry(t *testing.T) {
 
 	tried := false
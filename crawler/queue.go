@@ -107,10 +107,10 @@ type Queue interface {
 // to control the number of resources that will be visited concurrently.
 func NewMemoryQueue(ctx context.Context, limit int) Queue {
 	group, ctx := errgroup.WithContext(ctx)
-	group.SetLimit(limit)
 	return &memoryQueue{
 		ctx:     ctx,
 		group:   group,
+		limit:   limit,
 		mutex:   &sync.Mutex{},
 		buffer:  []*Task{},
 		handler: nil,
@@ -120,6 +120,8 @@ func NewMemoryQueue(ctx context.Context, limit int) Queue {
 type memoryQueue struct {
 	ctx     context.Context
 	group   *errgroup.Group
+	limit   int
+	active  int
 	mutex   *sync.Mutex
 	buffer  []*Task
 	handler Handler
@@ -134,37 +136,50 @@ func (q *memoryQueue) Add(tasks []*Task) error {
 }
 
 func (q *memoryQueue) Handle(handler Handler) {
+	q.mutex.Lock()
 	q.handler = handler
+	q.mutex.Unlock()
 	q.process()
 }
 
+// Wait returns the first error from a handler.  If the context is cancelled
+// before all tasks are started, the cause of the cancellation is returned.
 func (q *memoryQueue) Wait() error {
-	return q.group.Wait()
+	if err := q.group.Wait(); err != nil {
+		return err
+	}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if len(q.buffer) > 0 {
+		return context.Cause(q.ctx)
+	}
+	return nil
 }
 
+// process starts buffered tasks up to the concurrency limit.  The active count is
+// tracked here instead of with the group limit so that a finishing task can start
+// the next one before it returns (keeping the group from completing early).
 func (q *memoryQueue) process() {
-	if q.handler == nil || len(q.buffer) == 0 {
-		return
-	}
-
 	q.mutex.Lock()
-	count := 0
-	for _, task := range q.buffer {
-		task := task
-		added := q.group.TryGo(func() error {
-			defer q.process()
-			return q.handler(task)
-		})
-		if !added {
-			break
-		}
-		count += 1
-	}
+	defer q.mutex.Unlock()
 
-	if count > 0 {
-		q.buffer = q.buffer[count:]
+	for q.handler != nil && len(q.buffer) > 0 && (q.limit <= 0 || q.active < q.limit) {
+		if q.ctx.Err() != nil {
+			return
+		}
+		task := q.buffer[0]
+		q.buffer = q.buffer[1:]
+		q.active += 1
+		handler := q.handler
+		q.group.Go(func() error {
+			err := handler(task)
+			q.mutex.Lock()
+			q.active -= 1
+			q.mutex.Unlock()
+			q.process()
+			return err
+		})
 	}
-	q.mutex.Unlock()
 }
 
 var _ Queue = (*memoryQueue)(nil)
@@ -162,6 +162,69 @@ func TestMemoryQueueRecursive(t *testing.T) {
 	assert.Equal(t, expected, count)
 }
 
+// TestMemoryQueueLimitOne checks that tasks added by a running task are not
+// dropped when the queue only runs one task at a time.
+func TestMemoryQueueLimitOne(t *testing.T) {
+	queue := NewMemoryQueue(context.Background(), 1)
+
+	count := int64(0)
+	queue.Handle(func(task *Task) error {
+		atomic.AddInt64(&count, 1)
+		if strings.HasSuffix(task.resource.String(), "/0") {
+			tasks := make([]*Task, 5)
+			for i := range tasks {
+				resource, err := normurl.New(fmt.Sprintf("https://example.com/%d", i+1))
+				if err != nil {
+					return err
+				}
+				tasks[i] = task.new(resource, resourceTask)
+			}
+			return queue.Add(tasks)
+		}
+		return nil
+	})
+
+	entry, entryErr := normurl.New("https://example.com/0")
+	require.NoError(t, entryErr)
+
+	require.NoError(t, queue.Add([]*Task{{entry: entry, resource: entry, taskType: resourceTask}}))
+	require.NoError(t, queue.Wait())
+	assert.Equal(t, int64(6), atomic.LoadInt64(&count))
+}
+
+// TestMemoryQueueCancel checks that waiting returns the context error when the
+// context is cancelled before buffered tasks are started.
+func TestMemoryQueueCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	queue := NewMemoryQueue(ctx, 1)
+
+	count := int64(0)
+	queue.Handle(func(task *Task) error {
+		atomic.AddInt64(&count, 1)
+		tasks := make([]*Task, 5)
+		for i := range tasks {
+			resource, err := normurl.New(fmt.Sprintf("https://example.com/%d", i+1))
+			if err != nil {
+				return err
+			}
+			tasks[i] = task.new(resource, resourceTask)
+		}
+		if err := queue.Add(tasks); err != nil {
+			return err
+		}
+		cancel()
+		return nil
+	})
+
+	entry, entryErr := normurl.New("https://example.com/0")
+	require.NoError(t, entryErr)
+
+	require.NoError(t, queue.Add([]*Task{{entry: entry, resource: entry, taskType: resourceTask}}))
+	assert.ErrorIs(t, queue.Wait(), context.Canceled)
+	assert.Less(t, atomic.LoadInt64(&count), int64(6))
+}
+
 func TestMemoryQueueError(t *testing.T) {
 	expectedError := fmt.Errorf("expected error")
 	queue := NewMemoryQueue(context.Background(), 3)
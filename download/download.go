@@ -0,0 +1,467 @@
+// Package download fetches the assets of STAC items.
+//
+// A Downloader selects assets by key, role, or media type, downloads them
+// concurrently (with retries and resumption of interrupted downloads), verifies
+// them against any file:checksum or file:size values, and rewrites the asset
+// hrefs to point to the local copies.
+package download
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/planetlabs/go-stac"
+	_ "github.com/planetlabs/go-stac/extensions/file/v2"
+	"github.com/planetlabs/go-stac/internal/fetch"
+	"github.com/planetlabs/go-stac/internal/multihash"
+	"github.com/planetlabs/go-stac/internal/normurl"
+	"golang.org/x/sync/errgroup"
+)
+
+// Expected describes the expected contents of a downloaded file.
+type Expected struct {
+	// Checksum is a hex-encoded multihash (like file:checksum).  An empty
+	// checksum is not checked.
+	Checksum string
+
+	// Size is the size in bytes (like file:size).  A zero size is not checked.
+	Size int64
+}
+
+func (e *Expected) verify(path string) error {
+	if e == nil {
+		return nil
+	}
+	if e.Checksum != "" {
+		if err := multihash.VerifyFile(path, e.Checksum); err != nil {
+			return err
+		}
+	}
+	if e.Size > 0 {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if info.Size() != e.Size {
+			return fmt.Errorf("expected %s to have %d bytes, got %d", path, e.Size, info.Size())
+		}
+	}
+	return nil
+}
+
+// merge returns expectations that include the values of both.  Values from e
+// take precedence.
+func (e *Expected) merge(other *Expected) *Expected {
+	if other == nil {
+		return e
+	}
+	if e == nil {
+		return other
+	}
+	merged := *e
+	if merged.Checksum == "" {
+		merged.Checksum = other.Checksum
+	}
+	if merged.Size == 0 {
+		merged.Size = other.Size
+	}
+	return &merged
+}
+
+// assetDownload is a file to download for one or more assets of an item.
+type assetDownload struct {
+	// key is the first asset with the href (used in errors)
+	key      string
+	name     string
+	expected *Expected
+}
+
+// File downloads a file from a URL or local path to the target path.
+//
+// If the target already exists and matches the expected checksum and size, it
+// is not downloaded again.  Interrupted HTTP downloads are resumed with range
+// requests.  If the downloaded file does not match the expected checksum or
+// size, it is removed and an error is returned.
+func File(ctx context.Context, client *http.Client, header http.Header, source string, target string, expected *Expected) error {
+	if _, err := os.Stat(target); err == nil {
+		if expected.verify(target) == nil {
+			return nil
+		}
+		if err := os.Remove(target); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	loc, err := normurl.New(source)
+	if err != nil {
+		return err
+	}
+	if loc.IsFilepath() {
+		err = copyFile(loc.String(), target)
+	} else {
+		if client == nil {
+			client = fetch.DefaultClient
+		}
+		err = fetch.File(ctx, client, &fetch.Request{Location: source, Header: header}, target)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", source, err)
+	}
+
+	if err := expected.verify(target); err != nil {
+		_ = os.Remove(target)
+		return err
+	}
+	return nil
+}
+
+func copyFile(source string, target string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// Downloader downloads item assets to a directory.
+type Downloader struct {
+	dir        string
+	keys       []string
+	roles      []string
+	mediaTypes []string
+	client     *http.Client
+	header     http.Header
+	semaphore  chan struct{}
+}
+
+// Options for the downloader.
+type Options struct {
+	// Keys limits downloads to assets with one of the listed keys.
+	Keys []string
+
+	// Roles limits downloads to assets with one of the listed roles.
+	Roles []string
+
+	// MediaTypes limits downloads to assets with one of the listed media types.
+	// A value without parameters (e.g. "image/tiff") matches types with
+	// parameters (e.g. "image/tiff; application=geotiff").
+	MediaTypes []string
+
+	// Concurrency is the maximum number of files downloaded at once (default 4).
+	Concurrency int
+
+	// HTTPClient is used for requests.  The default client retries requests
+	// that fail with connection or server errors.
+	HTTPClient *http.Client
+
+	// Header is added to each request.
+	Header http.Header
+}
+
+// New creates a new downloader that writes to the provided directory.
+func New(dir string, options ...*Options) *Downloader {
+	d := &Downloader{
+		dir:    dir,
+		client: fetch.DefaultClient,
+		header: http.Header{},
+	}
+	concurrency := 4
+	for _, opt := range options {
+		if len(opt.Keys) > 0 {
+			d.keys = opt.Keys
+		}
+		if len(opt.Roles) > 0 {
+			d.roles = opt.Roles
+		}
+		if len(opt.MediaTypes) > 0 {
+			d.mediaTypes = opt.MediaTypes
+		}
+		if opt.Concurrency > 0 {
+			concurrency = opt.Concurrency
+		}
+		if opt.HTTPClient != nil {
+			d.client = opt.HTTPClient
+		}
+		if opt.Header != nil {
+			d.header = opt.Header
+		}
+	}
+	d.semaphore = make(chan struct{}, concurrency)
+	return d
+}
+
+// Select reports whether an asset will be downloaded.  An asset is selected if
+// it matches each of the configured keys, roles, and media types.
+func (d *Downloader) Select(key string, asset *stac.Asset) bool {
+	if len(d.keys) > 0 && !slices.Contains(d.keys, key) {
+		return false
+	}
+	if len(d.roles) > 0 && !slices.ContainsFunc(asset.Roles, func(role string) bool {
+		return slices.Contains(d.roles, role)
+	}) {
+		return false
+	}
+	if len(d.mediaTypes) > 0 && !slices.ContainsFunc(d.mediaTypes, func(mediaType string) bool {
+		return matchMediaType(mediaType, asset.Type)
+	}) {
+		return false
+	}
+	return true
+}
+
+func matchMediaType(selected string, mediaType string) bool {
+	normalize := func(value string) string {
+		return strings.ToLower(strings.ReplaceAll(value, " ", ""))
+	}
+	selected, mediaType = normalize(selected), normalize(mediaType)
+	if selected == mediaType {
+		return true
+	}
+	if strings.Contains(selected, ";") {
+		return false
+	}
+	base, _, _ := strings.Cut(mediaType, ";")
+	return base == selected
+}
+
+// ItemDir returns the directory for an item's files.  Items are written to
+// {dir}/{collection}/{id} (or {dir}/{id} for items without a collection).
+func (d *Downloader) ItemDir(item *stac.Item) string {
+	if item.Collection == "" {
+		return filepath.Join(d.dir, SafeName(item.Id))
+	}
+	return filepath.Join(d.dir, SafeName(item.Collection), SafeName(item.Id))
+}
+
+// ItemPath returns the path where an item is saved.
+func (d *Downloader) ItemPath(item *stac.Item) string {
+	return filepath.Join(d.ItemDir(item), SafeName(item.Id)+".json")
+}
+
+// Item downloads the selected assets of an item to the item directory and
+// rewrites their hrefs to point to the local files (relative to ItemPath).
+//
+// The location of the item is used to resolve relative hrefs.  If it is empty,
+// the item's self link is used.  The hrefs of other assets and of links are
+// made absolute and the self link is removed so the item remains valid when
+// saved.  Downloads for many items can run concurrently, and the total number
+// of concurrent downloads is limited by the Concurrency option.
+func (d *Downloader) Item(ctx context.Context, item *stac.Item, location string) error {
+	base, err := itemLocation(item, location)
+	if err != nil {
+		return err
+	}
+
+	expected, err := expectations(item.Assets)
+	if err != nil {
+		return err
+	}
+
+	dir := d.ItemDir(item)
+	keys := make([]string, 0, len(item.Assets))
+	for key := range item.Assets {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	// assets that share an href are downloaded once
+	downloads := map[string]*assetDownload{}
+	order := []string{}
+	names := map[string]string{}
+	for _, key := range keys {
+		asset := item.Assets[key]
+		if asset == nil || asset.Href == "" {
+			continue
+		}
+		source, err := resolve(base, asset.Href)
+		if !d.Select(key, asset) {
+			if err == nil {
+				asset.Href = source
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to resolve href for asset %q: %w", key, err)
+		}
+
+		if download, ok := downloads[source]; ok {
+			download.expected = download.expected.merge(expected[key])
+			asset.Href = "./" + download.name
+			continue
+		}
+
+		name := AssetName(key, source)
+		if other, taken := names[name]; taken && other != source {
+			name = SafeName(key) + "-" + name
+		}
+		names[name] = source
+		asset.Href = "./" + name
+
+		downloads[source] = &assetDownload{key: key, name: name, expected: expected[key]}
+		order = append(order, source)
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	for _, source := range order {
+		download := downloads[source]
+		target := filepath.Join(dir, download.name)
+		group.Go(func() error {
+			select {
+			case d.semaphore <- struct{}{}:
+			case <-groupCtx.Done():
+				return groupCtx.Err()
+			}
+			defer func() { <-d.semaphore }()
+
+			if err := File(groupCtx, d.client, d.header, source, target, download.expected); err != nil {
+				return fmt.Errorf("failed to download asset %q of item %s: %w", download.key, item.Id, err)
+			}
+			return nil
+		})
+	}
+
+	links := []*stac.Link{}
+	for _, link := range item.Links {
+		if link == nil || link.Rel == "self" {
+			continue
+		}
+		if href, err := resolve(base, link.Href); err == nil {
+			link.Href = href
+		}
+		links = append(links, link)
+	}
+	item.Links = links
+
+	return group.Wait()
+}
+
+// Save writes an item to ItemPath.
+func (d *Downloader) Save(item *stac.Item) error {
+	itemPath := d.ItemPath(item)
+	if err := os.MkdirAll(filepath.Dir(itemPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	data, err := json.MarshalIndent(item, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode item %s: %w", item.Id, err)
+	}
+	if err := os.WriteFile(itemPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", itemPath, err)
+	}
+	return nil
+}
+
+// itemLocation returns the location for resolving relative hrefs in an item.
+func itemLocation(item *stac.Item, location string) (*normurl.Locator, error) {
+	if location == "" {
+		for _, link := range item.Links {
+			if link != nil && link.Rel == "self" {
+				location = link.Href
+				break
+			}
+		}
+	}
+	if location == "" {
+		return nil, nil
+	}
+	loc, err := normurl.New(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid location for item %s: %w", item.Id, err)
+	}
+	return loc, nil
+}
+
+func resolve(base *normurl.Locator, href string) (string, error) {
+	if base == nil {
+		loc, err := normurl.New(href)
+		if err != nil {
+			return "", errors.New("cannot resolve relative href without an item location")
+		}
+		return loc.String(), nil
+	}
+	loc, err := base.Resolve(href)
+	if err != nil {
+		return "", err
+	}
+	return loc.String(), nil
+}
+
+// expectations gets the file:checksum and file:size values for each asset.
+// Importing the file extension package registers it so these values are
+// decoded for items that list the extension.
+func expectations(assets map[string]*stac.Asset) (map[string]*Expected, error) {
+	encoded, _, err := stac.EncodeAssets(assets)
+	if err != nil {
+		return nil, err
+	}
+	expected := map[string]*Expected{}
+	for key, value := range encoded {
+		assetMap, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		e := &Expected{}
+		e.Checksum, _ = assetMap["file:checksum"].(string)
+		switch size := assetMap["file:size"].(type) {
+		case float64:
+			e.Size = int64(size)
+		case int:
+			e.Size = int64(size)
+		case int64:
+			e.Size = size
+		case uint64:
+			e.Size = int64(size)
+		}
+		expected[key] = e
+	}
+	return expected, nil
+}
+
+// assetName returns a file name for an asset based on its href.
+func AssetName(key string, source string) string {
+	loc, err := normurl.New(source)
+	name := ""
+	if err == nil {
+		if loc.IsFilepath() {
+			name = filepath.Base(source)
+		} else {
+			name = path.Base(strings.SplitN(strings.SplitN(source, "?", 2)[0], "#", 2)[0])
+		}
+	}
+	if name == "" || name == "." || name == "/" || strings.HasSuffix(source, "/") {
+		name = key
+	}
+	return SafeName(name)
+}
+
+// SafeName replaces characters that are not safe in a single path segment.
+func SafeName(name string) string {
+	name = strings.NewReplacer("/", "_", "\\", "_").Replace(name)
+	if name == "." || name == ".." {
+		name = strings.ReplaceAll(name, ".", "_")
+	}
+	return name
+}
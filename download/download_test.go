@@ -0,0 +1,232 @@
+package download_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/download"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const helloChecksum = "1220b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+
+type assetServer struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	requests []string
+}
+
+func newAssetServer(t *testing.T) *assetServer {
+	s := &assetServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		s.requests = append(s.requests, r.URL.Path)
+		s.mutex.Unlock()
+		if strings.HasPrefix(r.URL.Path, "/missing") {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, r.URL.Path, time.Time{}, strings.NewReader("hello world"))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestSelect(t *testing.T) {
+	data := &stac.Asset{Type: "image/tiff; application=geotiff", Roles: []string{"data"}}
+	thumbnail := &stac.Asset{Type: "image/png", Roles: []string{"thumbnail"}}
+
+	cases := []struct {
+		name      string
+		options   *download.Options
+		data      bool
+		thumbnail bool
+	}{
+		{name: "all", options: &download.Options{}, data: true, thumbnail: true},
+		{name: "key", options: &download.Options{Keys: []string{"visual"}}, data: true, thumbnail: false},
+		{name: "role", options: &download.Options{Roles: []string{"thumbnail", "overview"}}, data: false, thumbnail: true},
+		{name: "media type", options: &download.Options{MediaTypes: []string{"image/tiff"}}, data: true, thumbnail: false},
+		{name: "media type with parameters", options: &download.Options{MediaTypes: []string{"image/tiff;application=geotiff"}}, data: true, thumbnail: false},
+		{name: "key and role", options: &download.Options{Keys: []string{"visual"}, Roles: []string{"thumbnail"}}, data: false, thumbnail: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := download.New(t.TempDir(), c.options)
+			assert.Equal(t, c.data, d.Select("visual", data))
+			assert.Equal(t, c.thumbnail, d.Select("thumbnail", thumbnail))
+		})
+	}
+}
+
+func TestItem(t *testing.T) {
+	server := newAssetServer(t)
+
+	source := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(source, "local.txt"), []byte("local"), 0644))
+
+	item := &stac.Item{
+		Version:    "1.0.0",
+		Id:         "item-1",
+		Collection: "collection",
+		Properties: map[string]any{"datetime": "2024-01-01T00:00:00Z"},
+		Links: []*stac.Link{
+			{Rel: "self", Href: filepath.Join(source, "item-1.json")},
+			{Rel: "parent", Href: "./collection.json"},
+		},
+		Assets: map[string]*stac.Asset{
+			"data":      {Href: server.URL + "/data/data.txt", Roles: []string{"data"}},
+			"metadata":  {Href: server.URL + "/metadata/data.txt", Roles: []string{"data"}},
+			"local":     {Href: "./local.txt", Roles: []string{"data"}},
+			"thumbnail": {Href: "./thumbnail.png", Roles: []string{"thumbnail"}},
+		},
+	}
+
+	output := t.TempDir()
+	d := download.New(output, &download.Options{Roles: []string{"data"}, Concurrency: 2})
+	require.NoError(t, d.Item(context.Background(), item, ""))
+	require.NoError(t, d.Save(item))
+
+	dir := filepath.Join(output, "collection", "item-1")
+	assert.Equal(t, dir, d.ItemDir(item))
+	assert.Equal(t, filepath.Join(dir, "item-1.json"), d.ItemPath(item))
+
+	assert.Equal(t, "./data.txt", item.Assets["data"].Href)
+	assert.Equal(t, "./metadata-data.txt", item.Assets["metadata"].Href)
+	assert.Equal(t, "./local.txt", item.Assets["local"].Href)
+	assert.Equal(t, filepath.Join(source, "thumbnail.png"), item.Assets["thumbnail"].Href)
+
+	for name, content := range map[string]string{"data.txt": "hello world", "metadata-data.txt": "hello world", "local.txt": "local"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.Equal(t, content, string(data))
+	}
+	assert.NoFileExists(t, filepath.Join(dir, "thumbnail.png"))
+
+	require.Len(t, item.Links, 1)
+	assert.Equal(t, "parent", item.Links[0].Rel)
+	assert.Equal(t, filepath.Join(source, "collection.json"), item.Links[0].Href)
+
+	data, err := os.ReadFile(d.ItemPath(item))
+	require.NoError(t, err)
+	saved := &stac.Item{}
+	require.NoError(t, json.Unmarshal(data, saved))
+	assert.Equal(t, "./data.txt", saved.Assets["data"].Href)
+}
+
+func TestItemSharedHref(t *testing.T) {
+	server := newAssetServer(t)
+
+	item := &stac.Item{
+		Version:    "1.0.0",
+		Id:         "item-1",
+		Properties: map[string]any{"datetime": "2024-01-01T00:00:00Z"},
+		Assets: map[string]*stac.Asset{
+			"data":   {Href: server.URL + "/data.txt", Roles: []string{"data"}},
+			"visual": {Href: server.URL + "/data.txt", Roles: []string{"visual"}},
+		},
+	}
+
+	output := t.TempDir()
+	d := download.New(output, &download.Options{Concurrency: 2})
+	require.NoError(t, d.Item(context.Background(), item, ""))
+
+	assert.Equal(t, "./data.txt", item.Assets["data"].Href)
+	assert.Equal(t, "./data.txt", item.Assets["visual"].Href)
+	assert.Equal(t, []string{"/data.txt"}, server.requests)
+
+	data, err := os.ReadFile(filepath.Join(d.ItemDir(item), "data.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(data))
+}
+
+func TestItemRelativeWithoutLocation(t *testing.T) {
+	item := &stac.Item{
+		Id:     "item-1",
+		Assets: map[string]*stac.Asset{"data": {Href: "./data.txt"}},
+	}
+	err := download.New(t.TempDir()).Item(context.Background(), item, "")
+	assert.ErrorContains(t, err, "without an item location")
+}
+
+func TestItemNotFound(t *testing.T) {
+	server := newAssetServer(t)
+	item := &stac.Item{
+		Id:     "item-1",
+		Assets: map[string]*stac.Asset{"data": {Href: server.URL + "/missing.txt"}},
+	}
+	err := download.New(t.TempDir()).Item(context.Background(), item, "")
+	assert.ErrorContains(t, err, `failed to download asset "data" of item item-1`)
+}
+
+func TestFile(t *testing.T) {
+	server := newAssetServer(t)
+	target := filepath.Join(t.TempDir(), "nested", "data.txt")
+	expected := &download.Expected{Checksum: helloChecksum, Size: 11}
+
+	require.NoError(t, download.File(context.Background(), nil, nil, server.URL+"/data.txt", target, expected))
+	assert.FileExists(t, target)
+	assert.Equal(t, []string{"/data.txt"}, server.requests)
+
+	// an existing file that matches is not downloaded again
+	require.NoError(t, download.File(context.Background(), nil, nil, server.URL+"/data.txt", target, expected))
+	assert.Equal(t, []string{"/data.txt"}, server.requests)
+
+	// an existing file that does not match is replaced
+	require.NoError(t, os.WriteFile(target, []byte("corrupt"), 0644))
+	require.NoError(t, download.File(context.Background(), nil, nil, server.URL+"/data.txt", target, expected))
+	assert.Equal(t, []string{"/data.txt", "/data.txt"}, server.requests)
+}
+
+func TestFileMismatch(t *testing.T) {
+	server := newAssetServer(t)
+	dir := t.TempDir()
+
+	target := filepath.Join(dir, "checksum.txt")
+	err := download.File(context.Background(), nil, nil, server.URL+"/data.txt", target, &download.Expected{
+		Checksum: "d501105eb63bbbe01eeed093cb22bb8f5acdc4",
+	})
+	assert.ErrorContains(t, err, "checksum mismatch")
+	assert.NoFileExists(t, target)
+
+	target = filepath.Join(dir, "size.txt")
+	err = download.File(context.Background(), nil, nil, server.URL+"/data.txt", target, &download.Expected{Size: 12})
+	assert.ErrorContains(t, err, "expected")
+	assert.NoFileExists(t, target)
+}
+
+func TestItemChecksum(t *testing.T) {
+	server := newAssetServer(t)
+
+	item := &stac.Item{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "Feature",
+		"stac_version": "1.1.0",
+		"stac_extensions": ["https://stac-extensions.github.io/file/v2.1.0/schema.json"],
+		"id": "item-1",
+		"geometry": null,
+		"properties": {"datetime": "2024-01-01T00:00:00Z"},
+		"links": [],
+		"assets": {
+			"good": {"href": "`+server.URL+`/good.txt", "file:checksum": "`+helloChecksum+`", "file:size": 11},
+			"bad": {"href": "`+server.URL+`/bad.txt", "file:checksum": "1220c94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"}
+		}
+	}`), item))
+
+	output := t.TempDir()
+	d := download.New(output)
+	err := d.Item(context.Background(), item, "")
+	assert.ErrorContains(t, err, `failed to download asset "bad" of item item-1`)
+	assert.ErrorContains(t, err, "checksum mismatch")
+	assert.NoFileExists(t, filepath.Join(d.ItemDir(item), "bad.txt"))
+}
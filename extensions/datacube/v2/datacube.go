@@ -0,0 +1,445 @@
+package datacube
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+
+	"github.com/planetlabs/go-stac"
+)
+
+const (
+	extensionUri     = "https://stac-extensions.github.io/datacube/v2.2.0/schema.json"
+	extensionPattern = `https://stac-extensions.github.io/datacube/v2\..*/schema.json`
+	dimensionsKey    = "cube:dimensions"
+	variablesKey     = "cube:variables"
+	propertiesKey    = "properties"
+)
+
+func init() {
+	r := regexp.MustCompile(extensionPattern)
+
+	stac.RegisterCollectionExtension(r, func() stac.Extension { return &Collection{} })
+	stac.RegisterItemExtension(r, func() stac.Extension { return &Item{} })
+	stac.RegisterAssetExtension(r, func() stac.Extension { return &Asset{} })
+}
+
+type Collection struct {
+	Dimensions Dimensions           `json:"cube:dimensions,omitempty"`
+	Variables  map[string]*Variable `json:"cube:variables,omitempty"`
+}
+
+type Item struct {
+	Dimensions Dimensions           `json:"cube:dimensions,omitempty"`
+	Variables  map[string]*Variable `json:"cube:variables,omitempty"`
+}
+
+type Asset struct {
+	Dimensions Dimensions           `json:"cube:dimensions,omitempty"`
+	Variables  map[string]*Variable `json:"cube:variables,omitempty"`
+}
+
+var (
+	_ stac.Extension = (*Collection)(nil)
+	_ stac.Extension = (*Item)(nil)
+	_ stac.Extension = (*Asset)(nil)
+)
+
+func (*Collection) URI() string {
+	return extensionUri
+}
+
+func (e *Collection) Encode(collectionMap map[string]any) error {
+	return encode(e.Dimensions, e.Variables, collectionMap)
+}
+
+func (e *Collection) Decode(collectionMap map[string]any) error {
+	return decode(collectionMap, &e.Dimensions, &e.Variables)
+}
+
+func (*Item) URI() string {
+	return extensionUri
+}
+
+func (e *Item) Encode(itemMap map[string]any) error {
+	current, err := itemProperties(itemMap)
+	if err != nil {
+		return err
+	}
+	properties := maps.Clone(current)
+	if err := encode(e.Dimensions, e.Variables, properties); err != nil {
+		return err
+	}
+	itemMap[propertiesKey] = properties
+	return nil
+}
+
+func (e *Item) Decode(itemMap map[string]any) error {
+	properties, err := itemProperties(itemMap)
+	if err != nil {
+		return err
+	}
+	if err := decode(properties, &e.Dimensions, &e.Variables); err != nil {
+		return err
+	}
+	delete(properties, dimensionsKey)
+	delete(properties, variablesKey)
+	itemMap[propertiesKey] = properties
+	return nil
+}
+
+func (*Asset) URI() string {
+	return extensionUri
+}
+
+func (e *Asset) Encode(assetMap map[string]any) error {
+	return encode(e.Dimensions, e.Variables, assetMap)
+}
+
+func (e *Asset) Decode(assetMap map[string]any) error {
+	return decode(assetMap, &e.Dimensions, &e.Variables)
+}
+
+func itemProperties(itemMap map[string]any) (map[string]any, error) {
+	value, ok := itemMap[propertiesKey]
+	if !ok || value == nil {
+		return map[string]any{}, nil
+	}
+	properties, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected a properties object, got %T", value)
+	}
+	return properties, nil
+}
+
+func encode(dimensions Dimensions, variables map[string]*Variable, data map[string]any) error {
+	if len(dimensions) == 0 && len(variables) == 0 {
+		return stac.ErrExtensionDoesNotApply
+	}
+	if len(dimensions) > 0 {
+		data[dimensionsKey] = dimensions
+	}
+	if len(variables) > 0 {
+		data[variablesKey] = variables
+	}
+	return nil
+}
+
+func decode(data map[string]any, dimensions *Dimensions, variables *map[string]*Variable) error {
+	dimensionsValue, hasDimensions := data[dimensionsKey]
+	variablesValue, hasVariables := data[variablesKey]
+	if !hasDimensions && !hasVariables {
+		return stac.ErrExtensionDoesNotApply
+	}
+	if hasDimensions {
+		if err := remarshal(dimensionsValue, dimensions); err != nil {
+			return fmt.Errorf("invalid %s: %w", dimensionsKey, err)
+		}
+	}
+	if hasVariables {
+		if err := remarshal(variablesValue, variables); err != nil {
+			return fmt.Errorf("invalid %s: %w", variablesKey, err)
+		}
+	}
+	return nil
+}
+
+func remarshal(value any, target any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+const (
+	DimensionSpatial    = "spatial"
+	DimensionTemporal   = "temporal"
+	DimensionGeometries = "geometries"
+
+	AxisX = "x"
+	AxisY = "y"
+	AxisZ = "z"
+)
+
+// Dimension is one of HorizontalSpatialDimension, VerticalSpatialDimension,
+// VectorDimension, TemporalDimension, or AdditionalDimension.
+type Dimension interface {
+	// DimensionType returns the value of the type member.
+	DimensionType() string
+}
+
+// Dimensions maps dimension names to dimensions.  When decoded, each
+// dimension is given a concrete type based on its type and axis members.
+type Dimensions map[string]Dimension
+
+var _ json.Unmarshaler = (*Dimensions)(nil)
+
+func (d *Dimensions) UnmarshalJSON(data []byte) error {
+	rawDimensions := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &rawDimensions); err != nil {
+		return err
+	}
+
+	dimensions := Dimensions{}
+	for name, raw := range rawDimensions {
+		header := &struct {
+			Type string `json:"type"`
+			Axis string `json:"axis"`
+		}{}
+		if err := json.Unmarshal(raw, header); err != nil {
+			return fmt.Errorf("invalid dimension %q: %w", name, err)
+		}
+
+		var dimension Dimension
+		switch header.Type {
+		case "":
+			return fmt.Errorf("missing type for dimension %q", name)
+		case DimensionSpatial:
+			switch header.Axis {
+			case AxisX, AxisY:
+				dimension = &HorizontalSpatialDimension{}
+			case AxisZ:
+				dimension = &VerticalSpatialDimension{}
+			default:
+				return fmt.Errorf("unexpected axis %q for spatial dimension %q", header.Axis, name)
+			}
+		case DimensionTemporal:
+			dimension = &TemporalDimension{}
+		case DimensionGeometries:
+			dimension = &VectorDimension{}
+		default:
+			dimension = &AdditionalDimension{}
+		}
+
+		if err := json.Unmarshal(raw, dimension); err != nil {
+			return fmt.Errorf("invalid dimension %q: %w", name, err)
+		}
+		dimensions[name] = dimension
+	}
+
+	*d = dimensions
+	return nil
+}
+
+// HorizontalSpatialDimension is a spatial dimension with an x or y axis.
+type HorizontalSpatialDimension struct {
+	Axis        string    `json:"axis"`
+	Description string    `json:"description,omitempty"`
+	Extent      []float64 `json:"extent"`
+	Values      []float64 `json:"values,omitempty"`
+	Step        *float64  `json:"step,omitempty"`
+
+	// Irregular is true for irregularly spaced values (encoded as a null step).
+	Irregular bool `json:"-"`
+
+	// ReferenceSystem is an EPSG code, WKT2 string, or PROJJSON object.
+	ReferenceSystem any `json:"reference_system,omitempty"`
+}
+
+func (*HorizontalSpatialDimension) DimensionType() string {
+	return DimensionSpatial
+}
+
+func (d *HorizontalSpatialDimension) MarshalJSON() ([]byte, error) {
+	type dimension HorizontalSpatialDimension
+	return marshalDimension((*dimension)(d), map[string]any{"type": DimensionSpatial}, d.Irregular)
+}
+
+func (d *HorizontalSpatialDimension) UnmarshalJSON(data []byte) error {
+	type dimension HorizontalSpatialDimension
+	irregular, err := unmarshalDimension(data, (*dimension)(d))
+	d.Irregular = irregular
+	return err
+}
+
+// VerticalSpatialDimension is a spatial dimension with a z axis.
+type VerticalSpatialDimension struct {
+	Description string `json:"description,omitempty"`
+
+	// Extent has a minimum and maximum value (either may be nil for an open
+	// extent).
+	Extent []any `json:"extent,omitempty"`
+
+	// Values are numbers or strings.
+	Values []any    `json:"values,omitempty"`
+	Step   *float64 `json:"step,omitempty"`
+
+	// Irregular is true for irregularly spaced values (encoded as a null step).
+	Irregular bool `json:"-"`
+
+	Unit string `json:"unit,omitempty"`
+
+	// ReferenceSystem is an EPSG code, WKT2 string, or PROJJSON object.
+	ReferenceSystem any `json:"reference_system,omitempty"`
+}
+
+func (*VerticalSpatialDimension) DimensionType() string {
+	return DimensionSpatial
+}
+
+func (d *VerticalSpatialDimension) MarshalJSON() ([]byte, error) {
+	type dimension VerticalSpatialDimension
+	return marshalDimension((*dimension)(d), map[string]any{"type": DimensionSpatial, "axis": AxisZ}, d.Irregular)
+}
+
+func (d *VerticalSpatialDimension) UnmarshalJSON(data []byte) error {
+	type dimension VerticalSpatialDimension
+	irregular, err := unmarshalDimension(data, (*dimension)(d))
+	d.Irregular = irregular
+	return err
+}
+
+// VectorDimension is a dimension of geometries.
+type VectorDimension struct {
+	Axes          []string  `json:"axes,omitempty"`
+	Description   string    `json:"description,omitempty"`
+	Bbox          []float64 `json:"bbox"`
+	Values        []string  `json:"values,omitempty"`
+	GeometryTypes []string  `json:"geometry_types,omitempty"`
+
+	// ReferenceSystem is an EPSG code, WKT2 string, or PROJJSON object.
+	ReferenceSystem any `json:"reference_system,omitempty"`
+}
+
+func (*VectorDimension) DimensionType() string {
+	return DimensionGeometries
+}
+
+func (d *VectorDimension) MarshalJSON() ([]byte, error) {
+	type dimension VectorDimension
+	return marshalDimension((*dimension)(d), map[string]any{"type": DimensionGeometries}, false)
+}
+
+func (d *VectorDimension) UnmarshalJSON(data []byte) error {
+	type dimension VectorDimension
+	_, err := unmarshalDimension(data, (*dimension)(d))
+	return err
+}
+
+// TemporalDimension is a dimension with datetime values.
+type TemporalDimension struct {
+	Description string `json:"description,omitempty"`
+
+	// Extent has a start and end datetime string (either may be nil for an open
+	// extent).
+	Extent []any    `json:"extent"`
+	Values []string `json:"values,omitempty"`
+
+	// Step is an ISO 8601 duration.
+	Step *string `json:"step,omitempty"`
+
+	// Irregular is true for irregularly spaced values (encoded as a null step).
+	Irregular bool `json:"-"`
+}
+
+func (*TemporalDimension) DimensionType() string {
+	return DimensionTemporal
+}
+
+func (d *TemporalDimension) MarshalJSON() ([]byte, error) {
+	type dimension TemporalDimension
+	return marshalDimension((*dimension)(d), map[string]any{"type": DimensionTemporal}, d.Irregular)
+}
+
+func (d *TemporalDimension) UnmarshalJSON(data []byte) error {
+	type dimension TemporalDimension
+	irregular, err := unmarshalDimension(data, (*dimension)(d))
+	d.Irregular = irregular
+	return err
+}
+
+// AdditionalDimension is any other dimension (e.g. bands).
+type AdditionalDimension struct {
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+
+	// Extent has a minimum and maximum value (either may be nil for an open
+	// extent).
+	Extent []any `json:"extent,omitempty"`
+
+	// Values are numbers or strings.
+	Values []any    `json:"values,omitempty"`
+	Step   *float64 `json:"step,omitempty"`
+
+	// Irregular is true for irregularly spaced values (encoded as a null step).
+	Irregular bool `json:"-"`
+
+	Unit            string `json:"unit,omitempty"`
+	ReferenceSystem any    `json:"reference_system,omitempty"`
+}
+
+func (d *AdditionalDimension) DimensionType() string {
+	return d.Type
+}
+
+func (d *AdditionalDimension) MarshalJSON() ([]byte, error) {
+	type dimension AdditionalDimension
+	return marshalDimension((*dimension)(d), nil, d.Irregular)
+}
+
+func (d *AdditionalDimension) UnmarshalJSON(data []byte) error {
+	type dimension AdditionalDimension
+	irregular, err := unmarshalDimension(data, (*dimension)(d))
+	d.Irregular = irregular
+	return err
+}
+
+// marshalDimension encodes a dimension with additional members and a null
+// step for irregularly spaced values.
+func marshalDimension(value any, members map[string]any, irregular bool) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	dimensionMap := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &dimensionMap); err != nil {
+		return nil, err
+	}
+	for key, member := range members {
+		memberData, err := json.Marshal(member)
+		if err != nil {
+			return nil, err
+		}
+		dimensionMap[key] = memberData
+	}
+	if irregular {
+		dimensionMap["step"] = json.RawMessage("null")
+	}
+	return json.Marshal(dimensionMap)
+}
+
+// unmarshalDimension decodes a dimension and reports whether it has a null
+// step.
+func unmarshalDimension(data []byte, value any) (bool, error) {
+	if err := json.Unmarshal(data, value); err != nil {
+		return false, err
+	}
+	members := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &members); err != nil {
+		return false, err
+	}
+	step, ok := members["step"]
+	return ok && string(step) == "null", nil
+}
+
+const (
+	VariableData      = "data"
+	VariableAuxiliary = "auxiliary"
+)
+
+// Variable describes a variable of the data cube.
+type Variable struct {
+	Dimensions  []string `json:"dimensions"`
+	Type        string   `json:"type,omitempty"`
+	Description string   `json:"description,omitempty"`
+
+	// Extent has a minimum and maximum value (either may be nil for an open
+	// extent).
+	Extent []any `json:"extent,omitempty"`
+
+	// Values are numbers or strings.
+	Values []any  `json:"values,omitempty"`
+	Unit   string `json:"unit,omitempty"`
+}
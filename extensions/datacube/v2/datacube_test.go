@@ -0,0 +1,300 @@
+package datacube_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/extensions/datacube/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatacubeCollectionJSON(t *testing.T) {
+	step := 0.25
+	vertical := 10.0
+	daily := "P1D"
+
+	cases := []struct {
+		name       string
+		collection *stac.Collection
+		data       string
+		err        string
+	}{
+		{
+			name: "dimensions and variables",
+			collection: &stac.Collection{
+				Version:     "1.1.0",
+				Id:          "collection-id",
+				Description: "Test Collection",
+				License:     "various",
+				Extent: &stac.Extent{
+					Spatial: &stac.SpatialExtent{
+						Bbox: [][]float64{{-180, -90, 180, 90}},
+					},
+				},
+				Links: []*stac.Link{
+					{Href: "https://example.com/stac/collection-id", Rel: "self"},
+				},
+				Extensions: []stac.Extension{
+					&datacube.Collection{
+						Dimensions: datacube.Dimensions{
+							"lon": &datacube.HorizontalSpatialDimension{
+								Axis:            datacube.AxisX,
+								Extent:          []float64{-180, 180},
+								Step:            &step,
+								ReferenceSystem: float64(4326),
+							},
+							"lat": &datacube.HorizontalSpatialDimension{
+								Axis:      datacube.AxisY,
+								Extent:    []float64{-90, 90},
+								Values:    []float64{-90, 0, 45, 90},
+								Irregular: true,
+							},
+							"level": &datacube.VerticalSpatialDimension{
+								Extent: []any{float64(0), nil},
+								Step:   &vertical,
+								Unit:   "m",
+							},
+							"time": &datacube.TemporalDimension{
+								Extent: []any{"2020-01-01T00:00:00Z", nil},
+								Step:   &daily,
+							},
+							"stations": &datacube.VectorDimension{
+								Axes:          []string{"x", "y"},
+								Bbox:          []float64{-10, 40, 10, 60},
+								GeometryTypes: []string{"Point"},
+							},
+							"bands": &datacube.AdditionalDimension{
+								Type:   "bands",
+								Values: []any{"red", "green", "blue"},
+							},
+						},
+						Variables: map[string]*datacube.Variable{
+							"temperature": {
+								Dimensions: []string{"time", "level", "lat", "lon"},
+								Type:       datacube.VariableData,
+								Extent:     []any{float64(-50), float64(50)},
+								Unit:       "°C",
+							},
+						},
+					},
+				},
+			},
+			data: `{
+				"type": "Collection",
+				"stac_version": "1.1.0",
+				"id": "collection-id",
+				"description": "Test Collection",
+				"extent": {
+					"spatial": {
+						"bbox": [
+							[-180, -90, 180, 90]
+						]
+					}
+				},
+				"license": "various",
+				"links": [
+					{
+						"rel": "self",
+						"href": "https://example.com/stac/collection-id"
+					}
+				],
+				"cube:dimensions": {
+					"lon": {
+						"type": "spatial",
+						"axis": "x",
+						"extent": [-180, 180],
+						"step": 0.25,
+						"reference_system": 4326
+					},
+					"lat": {
+						"type": "spatial",
+						"axis": "y",
+						"extent": [-90, 90],
+						"values": [-90, 0, 45, 90],
+						"step": null
+					},
+					"level": {
+						"type": "spatial",
+						"axis": "z",
+						"extent": [0, null],
+						"step": 10,
+						"unit": "m"
+					},
+					"time": {
+						"type": "temporal",
+						"extent": ["2020-01-01T00:00:00Z", null],
+						"step": "P1D"
+					},
+					"stations": {
+						"type": "geometries",
+						"axes": ["x", "y"],
+						"bbox": [-10, 40, 10, 60],
+						"geometry_types": ["Point"]
+					},
+					"bands": {
+						"type": "bands",
+						"values": ["red", "green", "blue"]
+					}
+				},
+				"cube:variables": {
+					"temperature": {
+						"dimensions": ["time", "level", "lat", "lon"],
+						"type": "data",
+						"extent": [-50, 50],
+						"unit": "°C"
+					}
+				},
+				"stac_extensions": [
+					"https://stac-extensions.github.io/datacube/v2.2.0/schema.json"
+				]
+			}`,
+		},
+		{
+			name: "missing dimension type",
+			data: `{
+				"type": "Collection",
+				"stac_version": "1.1.0",
+				"id": "collection-id",
+				"description": "Test Collection",
+				"extent": {"spatial": {"bbox": [[-180, -90, 180, 90]]}},
+				"license": "various",
+				"links": [],
+				"cube:dimensions": {
+					"lon": {"axis": "x", "extent": [-180, 180]}
+				},
+				"stac_extensions": [
+					"https://stac-extensions.github.io/datacube/v2.2.0/schema.json"
+				]
+			}`,
+			err: `missing type for dimension "lon"`,
+		},
+		{
+			name: "unexpected spatial axis",
+			data: `{
+				"type": "Collection",
+				"stac_version": "1.1.0",
+				"id": "collection-id",
+				"description": "Test Collection",
+				"extent": {"spatial": {"bbox": [[-180, -90, 180, 90]]}},
+				"license": "various",
+				"links": [],
+				"cube:dimensions": {
+					"lon": {"type": "spatial", "axis": "t", "extent": [-180, 180]}
+				},
+				"stac_extensions": [
+					"https://stac-extensions.github.io/datacube/v2.2.0/schema.json"
+				]
+			}`,
+			err: `unexpected axis "t" for spatial dimension "lon"`,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d-%s", i, c.name), func(t *testing.T) {
+			if c.collection != nil {
+				data, err := json.Marshal(c.collection)
+				require.NoError(t, err)
+				assert.JSONEq(t, c.data, string(data))
+			}
+
+			collection := &stac.Collection{}
+			err := json.Unmarshal([]byte(c.data), collection)
+			if c.err != "" {
+				assert.ErrorContains(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.collection, collection)
+		})
+	}
+}
+
+func TestDatacubeItemJSON(t *testing.T) {
+	item := &stac.Item{
+		Version: "1.1.0",
+		Id:      "item-id",
+		Geometry: map[string]any{
+			"type":        "Point",
+			"coordinates": []any{1.1, 2.2},
+		},
+		Properties: map[string]any{
+			"test": "value",
+		},
+		Links: []*stac.Link{
+			{Href: "https://example.com/stac/item-id", Rel: "self"},
+		},
+		Assets: map[string]*stac.Asset{
+			"data": {
+				Href: "https://example.com/stac/item-id/data.zarr",
+				Type: "application/vnd+zarr",
+				Extensions: []stac.Extension{
+					&datacube.Asset{
+						Variables: map[string]*datacube.Variable{
+							"mask": {Dimensions: []string{"time"}, Type: datacube.VariableAuxiliary},
+						},
+					},
+				},
+			},
+		},
+		Extensions: []stac.Extension{
+			&datacube.Item{
+				Dimensions: datacube.Dimensions{
+					"time": &datacube.TemporalDimension{
+						Extent:    []any{"2020-01-01T00:00:00Z", "2020-12-31T00:00:00Z"},
+						Values:    []string{"2020-01-01T00:00:00Z", "2020-12-31T00:00:00Z"},
+						Irregular: true,
+					},
+				},
+			},
+		},
+	}
+
+	data, err := json.Marshal(item)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "Feature",
+		"stac_version": "1.1.0",
+		"id": "item-id",
+		"geometry": {
+			"type": "Point",
+			"coordinates": [1.1, 2.2]
+		},
+		"properties": {
+			"test": "value",
+			"cube:dimensions": {
+				"time": {
+					"type": "temporal",
+					"extent": ["2020-01-01T00:00:00Z", "2020-12-31T00:00:00Z"],
+					"values": ["2020-01-01T00:00:00Z", "2020-12-31T00:00:00Z"],
+					"step": null
+				}
+			}
+		},
+		"links": [
+			{
+				"rel": "self",
+				"href": "https://example.com/stac/item-id"
+			}
+		],
+		"assets": {
+			"data": {
+				"href": "https://example.com/stac/item-id/data.zarr",
+				"type": "application/vnd+zarr",
+				"cube:variables": {
+					"mask": {"dimensions": ["time"], "type": "auxiliary"}
+				}
+			}
+		},
+		"stac_extensions": [
+			"https://stac-extensions.github.io/datacube/v2.2.0/schema.json"
+		]
+	}`, string(data))
+	assert.Equal(t, map[string]any{"test": "value"}, item.Properties)
+
+	decoded := &stac.Item{}
+	require.NoError(t, json.Unmarshal(data, decoded))
+	assert.Equal(t, item, decoded)
+}
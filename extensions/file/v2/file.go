@@ -0,0 +1,270 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/internal/fetch"
+	"github.com/planetlabs/go-stac/internal/multihash"
+	"github.com/planetlabs/go-stac/internal/normurl"
+)
+
+const (
+	extensionUri     = "https://stac-extensions.github.io/file/v2.1.0/schema.json"
+	extensionPattern = `https://stac-extensions.github.io/file/v2\..*/schema.json`
+	prefix           = "file"
+)
+
+func init() {
+	stac.RegisterAssetExtension(
+		regexp.MustCompile(extensionPattern),
+		func() stac.Extension {
+			return &Asset{}
+		},
+	)
+
+	stac.RegisterLinkExtension(
+		regexp.MustCompile(extensionPattern),
+		func() stac.Extension {
+			return &Link{}
+		},
+	)
+}
+
+const (
+	BigEndian    = "big-endian"
+	LittleEndian = "little-endian"
+)
+
+type Asset struct {
+	ByteOrder  string   `json:"file:byte_order,omitempty"`
+	Checksum   string   `json:"file:checksum,omitempty"`
+	HeaderSize *int64   `json:"file:header_size,omitempty"`
+	Size       *int64   `json:"file:size,omitempty"`
+	Values     []*Value `json:"file:values,omitempty"`
+	LocalPath  string   `json:"file:local_path,omitempty"`
+}
+
+type Link struct {
+	ByteOrder  string   `json:"file:byte_order,omitempty"`
+	Checksum   string   `json:"file:checksum,omitempty"`
+	HeaderSize *int64   `json:"file:header_size,omitempty"`
+	Size       *int64   `json:"file:size,omitempty"`
+	Values     []*Value `json:"file:values,omitempty"`
+	LocalPath  string   `json:"file:local_path,omitempty"`
+}
+
+type Value struct {
+	Values  []any  `json:"values"`
+	Summary string `json:"summary"`
+}
+
+var (
+	_ stac.Extension = (*Asset)(nil)
+	_ stac.Extension = (*Link)(nil)
+)
+
+func (*Asset) URI() string {
+	return extensionUri
+}
+
+func (e *Asset) Encode(assetMap map[string]any) error {
+	return stac.EncodeExtendedMap(e, assetMap)
+}
+
+func (e *Asset) Decode(assetMap map[string]any) error {
+	return stac.DecodeExtendedMap(e, assetMap, prefix)
+}
+
+// Update sets the checksum and size from the file at the provided path or URL.
+func (e *Asset) Update(ctx context.Context, href string, options ...*Options) error {
+	checksum, size, err := Compute(ctx, href, options...)
+	if err != nil {
+		return err
+	}
+	e.Checksum = checksum
+	e.Size = &size
+	return nil
+}
+
+// Verify checks that the file at the provided path or URL matches the checksum
+// and size.
+func (e *Asset) Verify(ctx context.Context, href string, options ...*Options) error {
+	return verify(ctx, href, e.Checksum, e.Size, options...)
+}
+
+func (*Link) URI() string {
+	return extensionUri
+}
+
+func (e *Link) Encode(linkMap map[string]any) error {
+	return stac.EncodeExtendedMap(e, linkMap)
+}
+
+func (e *Link) Decode(linkMap map[string]any) error {
+	return stac.DecodeExtendedMap(e, linkMap, prefix)
+}
+
+// Update sets the checksum and size from the file at the provided path or URL.
+func (e *Link) Update(ctx context.Context, href string, options ...*Options) error {
+	checksum, size, err := Compute(ctx, href, options...)
+	if err != nil {
+		return err
+	}
+	e.Checksum = checksum
+	e.Size = &size
+	return nil
+}
+
+// Verify checks that the file at the provided path or URL matches the checksum
+// and size.
+func (e *Link) Verify(ctx context.Context, href string, options ...*Options) error {
+	return verify(ctx, href, e.Checksum, e.Size, options...)
+}
+
+// Algorithm is the multihash code for a hash function.
+type Algorithm uint64
+
+const (
+	SHA1     Algorithm = 0x11
+	SHA256   Algorithm = 0x12
+	SHA512   Algorithm = 0x13
+	SHA3_512 Algorithm = 0x14
+	SHA3_384 Algorithm = 0x15
+	SHA3_256 Algorithm = 0x16
+	SHA3_224 Algorithm = 0x17
+	SHA384   Algorithm = 0x20
+	MD5      Algorithm = 0xd5
+)
+
+// Options for computing and verifying checksums.
+type Options struct {
+	// Algorithm is the hash function used when computing checksums (default
+	// SHA256).  Verification uses the hash function from the checksum.
+	Algorithm Algorithm
+
+	// HTTPClient is used for requests.  The default client retries requests
+	// that fail with connection or server errors.
+	HTTPClient *http.Client
+
+	// Header is added to each request.
+	Header http.Header
+}
+
+type config struct {
+	algorithm Algorithm
+	client    *http.Client
+	header    http.Header
+}
+
+func newConfig(options []*Options) *config {
+	c := &config{
+		algorithm: SHA256,
+		client:    fetch.DefaultClient,
+		header:    http.Header{},
+	}
+	for _, opt := range options {
+		if opt.Algorithm != 0 {
+			c.algorithm = opt.Algorithm
+		}
+		if opt.HTTPClient != nil {
+			c.client = opt.HTTPClient
+		}
+		if opt.Header != nil {
+			c.header = opt.Header
+		}
+	}
+	return c
+}
+
+// read calls the provided function with the contents of a local file or URL.
+func (c *config) read(ctx context.Context, href string, read func(io.Reader) error) error {
+	loc, err := normurl.New(href)
+	if err != nil {
+		return err
+	}
+	if !loc.IsFilepath() {
+		return fetch.Read(ctx, c.client, &fetch.Request{Location: loc.String(), Header: c.header}, read)
+	}
+
+	file, err := os.Open(loc.String())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	return read(file)
+}
+
+// Compute returns a file:checksum value (a hex-encoded multihash) and the
+// file:size for the file at the provided path or URL.
+func Compute(ctx context.Context, href string, options ...*Options) (string, int64, error) {
+	c := newConfig(options)
+	checksum := ""
+	size := int64(0)
+	err := c.read(ctx, href, func(r io.Reader) error {
+		var err error
+		checksum, size, err = multihash.Sum(r, uint64(c.algorithm))
+		return err
+	})
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to compute checksum for %s: %w", href, err)
+	}
+	return checksum, size, nil
+}
+
+// Verify checks that the file at the provided path or URL matches a
+// file:checksum value.
+func Verify(ctx context.Context, href string, checksum string, options ...*Options) error {
+	return verify(ctx, href, checksum, nil, options...)
+}
+
+// ErrNothingToVerify is returned when verifying without a checksum or size.
+var ErrNothingToVerify = errors.New("no checksum or size to verify")
+
+func verify(ctx context.Context, href string, checksum string, size *int64, options ...*Options) error {
+	if checksum == "" && size == nil {
+		return ErrNothingToVerify
+	}
+	if checksum != "" {
+		if _, _, err := multihash.Parse(checksum); err != nil {
+			return err
+		}
+	}
+
+	c := newConfig(options)
+	err := c.read(ctx, href, func(r io.Reader) error {
+		counter := &countingReader{reader: r}
+		if checksum != "" {
+			if err := multihash.Verify(counter, checksum); err != nil {
+				return err
+			}
+		} else if _, err := io.Copy(io.Discard, counter); err != nil {
+			return err
+		}
+		if size != nil && counter.count != *size {
+			return fmt.Errorf("size mismatch: expected %d bytes, got %d", *size, counter.count)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to verify %s: %w", href, err)
+	}
+	return nil
+}
+
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
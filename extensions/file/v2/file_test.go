@@ -0,0 +1,207 @@
+package file_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/extensions/file/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const helloChecksum = "1220b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+
+func TestFileItemJSON(t *testing.T) {
+	size := int64(11)
+	headerSize := int64(4)
+
+	cases := []struct {
+		name string
+		item *stac.Item
+		data string
+		err  string
+	}{
+		{
+			name: "extended asset and link",
+			item: &stac.Item{
+				Version: "1.1.0",
+				Id:      "item-id",
+				Geometry: map[string]any{
+					"type":        "Point",
+					"coordinates": []any{1.1, 2.2},
+				},
+				Properties: map[string]any{
+					"test": "value",
+				},
+				Links: []*stac.Link{
+					{Href: "https://example.com/stac/item-id", Rel: "self"},
+					{
+						Href: "https://example.com/stac/item-id/metadata.xml",
+						Rel:  "alternate",
+						Extensions: []stac.Extension{
+							&file.Link{
+								Checksum: helloChecksum,
+								Size:     &size,
+							},
+						},
+					},
+				},
+				Assets: map[string]*stac.Asset{
+					"image": {
+						Title: "Image",
+						Href:  "https://example.com/stac/item-id/image.tif",
+						Type:  "image/tif",
+						Extensions: []stac.Extension{
+							&file.Asset{
+								ByteOrder:  file.LittleEndian,
+								Checksum:   helloChecksum,
+								HeaderSize: &headerSize,
+								Size:       &size,
+								Values: []*file.Value{
+									{Values: []any{float64(0)}, Summary: "No data"},
+								},
+								LocalPath: "item-id/image.tif",
+							},
+						},
+					},
+				},
+			},
+			data: `{
+				"type": "Feature",
+				"stac_version": "1.1.0",
+				"id": "item-id",
+				"geometry": {
+					"type": "Point",
+					"coordinates": [1.1, 2.2]
+				},
+				"properties": {
+					"test": "value"
+				},
+				"links": [
+					{
+						"rel": "self",
+						"href": "https://example.com/stac/item-id"
+					},
+					{
+						"rel": "alternate",
+						"href": "https://example.com/stac/item-id/metadata.xml",
+						"file:checksum": "` + helloChecksum + `",
+						"file:size": 11
+					}
+				],
+				"assets": {
+					"image": {
+						"title": "Image",
+						"href": "https://example.com/stac/item-id/image.tif",
+						"type": "image/tif",
+						"file:byte_order": "little-endian",
+						"file:checksum": "` + helloChecksum + `",
+						"file:header_size": 4,
+						"file:size": 11,
+						"file:values": [{"values": [0], "summary": "No data"}],
+						"file:local_path": "item-id/image.tif"
+					}
+				},
+				"stac_extensions": [
+					"https://stac-extensions.github.io/file/v2.1.0/schema.json"
+				]
+			}`,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d-%s", i, c.name), func(t *testing.T) {
+			if c.item != nil {
+				data, err := json.Marshal(c.item)
+				if c.err != "" {
+					assert.ErrorContains(t, err, c.err)
+					return
+				}
+				require.NoError(t, err)
+				assert.JSONEq(t, c.data, string(data))
+			}
+
+			if c.data != "" {
+				item := &stac.Item{}
+				err := json.Unmarshal([]byte(c.data), item)
+				if c.err != "" {
+					assert.ErrorContains(t, err, c.err)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, c.item, item)
+			}
+		})
+	}
+}
+
+func newServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, "data.txt", time.Time{}, strings.NewReader("hello world"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCompute(t *testing.T) {
+	server := newServer(t)
+	path := filepath.Join(t.TempDir(), "data.txt")
+	require.NoError(t, os.WriteFile(path, []byte("hello world"), 0644))
+
+	for _, href := range []string{path, server.URL + "/data.txt"} {
+		checksum, size, err := file.Compute(context.Background(), href)
+		require.NoError(t, err)
+		assert.Equal(t, helloChecksum, checksum)
+		assert.Equal(t, int64(11), size)
+	}
+
+	checksum, _, err := file.Compute(context.Background(), path, &file.Options{Algorithm: file.MD5})
+	require.NoError(t, err)
+	assert.Equal(t, "d501105eb63bbbe01eeed093cb22bb8f5acdc3", checksum)
+
+	_, _, err = file.Compute(context.Background(), server.URL+"/missing.txt")
+	assert.ErrorContains(t, err, "404")
+}
+
+func TestAssetUpdateVerify(t *testing.T) {
+	server := newServer(t)
+	href := server.URL + "/data.txt"
+
+	asset := &file.Asset{}
+	require.NoError(t, asset.Update(context.Background(), href))
+	assert.Equal(t, helloChecksum, asset.Checksum)
+	require.NotNil(t, asset.Size)
+	assert.Equal(t, int64(11), *asset.Size)
+	require.NoError(t, asset.Verify(context.Background(), href))
+
+	size := int64(12)
+	asset.Size = &size
+	assert.ErrorContains(t, asset.Verify(context.Background(), href), "size mismatch")
+
+	link := &file.Link{Checksum: "1220c94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"}
+	assert.ErrorContains(t, link.Verify(context.Background(), href), "checksum mismatch")
+
+	assert.ErrorIs(t, (&file.Link{}).Verify(context.Background(), href), file.ErrNothingToVerify)
+}
+
+func TestVerify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.txt")
+	require.NoError(t, os.WriteFile(path, []byte("hello world"), 0644))
+
+	require.NoError(t, file.Verify(context.Background(), path, helloChecksum))
+	assert.ErrorContains(t, file.Verify(context.Background(), path, "12zz"), "invalid checksum")
+	assert.ErrorContains(t, file.Verify(context.Background(), path+".missing", helloChecksum), "no such file")
+}
@@ -0,0 +1,273 @@
+package label
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/crawler"
+	"github.com/planetlabs/go-stac/internal/normurl"
+)
+
+const (
+	extensionUri     = "https://stac-extensions.github.io/label/v1.0.1/schema.json"
+	extensionPattern = `https://stac-extensions.github.io/label/v1\..*/schema.json`
+	prefix           = "label"
+	typeKey          = "label:type"
+	assetsKey        = "label:assets"
+)
+
+// RelSource is the relation type for a link from a label item to the imagery
+// that was labeled.
+const RelSource = "source"
+
+const (
+	TypeVector = "vector"
+	TypeRaster = "raster"
+)
+
+const (
+	TaskRegression     = "regression"
+	TaskClassification = "classification"
+	TaskDetection      = "detection"
+	TaskSegmentation   = "segmentation"
+)
+
+const (
+	MethodAutomated = "automated"
+	MethodManual    = "manual"
+)
+
+func init() {
+	r := regexp.MustCompile(extensionPattern)
+
+	stac.RegisterItemExtension(r, func() stac.Extension { return &Item{} })
+	stac.RegisterLinkExtension(r, func() stac.Extension { return &Link{} })
+}
+
+type Item struct {
+	Properties  []string    `json:"label:properties"`
+	Classes     []*Class    `json:"label:classes,omitempty"`
+	Description string      `json:"label:description"`
+	Type        string      `json:"label:type"`
+	Tasks       []string    `json:"label:tasks,omitempty"`
+	Methods     []string    `json:"label:methods,omitempty"`
+	Overviews   []*Overview `json:"label:overviews,omitempty"`
+}
+
+// Class lists the values of a label property.  The name is nil for raster
+// labels.
+type Class struct {
+	Name    *string `json:"name"`
+	Classes []any   `json:"classes"`
+}
+
+type Overview struct {
+	PropertyKey string       `json:"property_key,omitempty"`
+	Counts      []*Count     `json:"counts,omitempty"`
+	Statistics  []*Statistic `json:"statistics,omitempty"`
+}
+
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type Statistic struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+type Link struct {
+	Assets []string `json:"label:assets,omitempty"`
+}
+
+var (
+	_ stac.Extension = (*Item)(nil)
+	_ stac.Extension = (*Link)(nil)
+)
+
+func (*Item) URI() string {
+	return extensionUri
+}
+
+func (e *Item) Encode(itemMap map[string]any) error {
+	return stac.EncodeExtendedItemProperties(e, itemMap)
+}
+
+func (e *Item) Decode(itemMap map[string]any) error {
+	if err := stac.DecodeExtendedItemProperties(e, itemMap); err != nil {
+		return err
+	}
+	if e.Type == "" {
+		return stac.ErrExtensionDoesNotApply
+	}
+	return nil
+}
+
+func (*Link) URI() string {
+	return extensionUri
+}
+
+func (e *Link) Encode(linkMap map[string]any) error {
+	return stac.EncodeExtendedMap(e, linkMap)
+}
+
+func (e *Link) Decode(linkMap map[string]any) error {
+	return stac.DecodeExtendedMap(e, linkMap, prefix)
+}
+
+// IsLabel returns true if the resource is an item with a label:type.
+func IsLabel(resource crawler.Resource) bool {
+	if resource.Type() != crawler.Item {
+		return false
+	}
+	properties, ok := resource["properties"].(map[string]any)
+	if !ok {
+		return false
+	}
+	_, ok = properties[typeKey].(string)
+	return ok
+}
+
+// Source is an item referenced by a source link of a label item.
+type Source struct {
+	// Location is the absolute path or URL of the source item.
+	Location string
+
+	// Item is the source item.
+	Item crawler.Resource
+
+	// Assets are the keys of the labeled source item assets (from the
+	// label:assets of the link).  If empty, all assets were labeled.
+	Assets []string
+}
+
+// Pair is a label item and the source items it was derived from.
+type Pair struct {
+	// Location is the absolute path or URL of the label item.
+	Location string
+
+	// Label is the label item.
+	Label crawler.Resource
+
+	// Sources are the items referenced by the source links of the label item.
+	Sources []*Source
+}
+
+// Sources follows the source links of a label item and loads the referenced
+// items with the crawler.  Relative hrefs are resolved against the location of
+// the label item.
+func Sources(label crawler.Resource, location string) ([]*Source, error) {
+	return (&loader{}).sources(label, location)
+}
+
+// Pairs crawls the entry and pairs each label item with its source items.
+// Items without a label:type are not included.  The options are used for the
+// crawl of the entry and not when loading source items.
+func Pairs(entry string, options ...*crawler.Options) ([]*Pair, error) {
+	pairs := []*Pair{}
+	mutex := &sync.Mutex{}
+	visitor := func(resource crawler.Resource, info *crawler.ResourceInfo) error {
+		if !IsLabel(resource) {
+			return nil
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		pairs = append(pairs, &Pair{Location: info.Location, Label: resource})
+		return nil
+	}
+	if err := crawler.Crawl(entry, visitor, options...); err != nil {
+		return nil, err
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].Location < pairs[j].Location
+	})
+
+	l := &loader{}
+	for _, pair := range pairs {
+		sources, err := l.sources(pair.Label, pair.Location)
+		if err != nil {
+			return nil, err
+		}
+		pair.Sources = sources
+	}
+	return pairs, nil
+}
+
+// loader loads source items, reusing items that are the source of more than
+// one label.
+type loader struct {
+	items map[string]crawler.Resource
+}
+
+func (l *loader) sources(label crawler.Resource, location string) ([]*Source, error) {
+	base, err := normurl.New(location)
+	if err != nil {
+		return nil, err
+	}
+
+	linksValue, _ := label["links"].([]any)
+	sources := []*Source{}
+	for _, linkValue := range linksValue {
+		link, ok := linkValue.(map[string]any)
+		if !ok || link["rel"] != RelSource {
+			continue
+		}
+		href, ok := link["href"].(string)
+		if !ok {
+			return nil, fmt.Errorf("expected source link href in %s to be a string, got %T", location, link["href"])
+		}
+		loc, err := base.Resolve(href)
+		if err != nil {
+			return nil, err
+		}
+
+		assets := []string{}
+		assetValues, _ := link[assetsKey].([]any)
+		for _, assetValue := range assetValues {
+			if key, ok := assetValue.(string); ok {
+				assets = append(assets, key)
+			}
+		}
+
+		item, err := l.load(loc.String())
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, &Source{Location: loc.String(), Item: item, Assets: assets})
+	}
+	return sources, nil
+}
+
+func (l *loader) load(location string) (crawler.Resource, error) {
+	if item, ok := l.items[location]; ok {
+		return item, nil
+	}
+
+	var item crawler.Resource
+	visitor := func(resource crawler.Resource, info *crawler.ResourceInfo) error {
+		if info.Location != info.Entry {
+			return crawler.ErrStopRecursion
+		}
+		if resource.Type() != crawler.Item {
+			return fmt.Errorf("expected source %s to be an item, got %s", location, resource.Type())
+		}
+		item = resource
+		return crawler.ErrStopRecursion
+	}
+	if err := crawler.Crawl(location, visitor); err != nil {
+		return nil, fmt.Errorf("failed to load source %s: %w", location, err)
+	}
+	if item == nil {
+		return nil, fmt.Errorf("failed to load source %s", location)
+	}
+
+	if l.items == nil {
+		l.items = map[string]crawler.Resource{}
+	}
+	l.items[location] = item
+	return item, nil
+}
@@ -0,0 +1,240 @@
+package label_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/crawler"
+	"github.com/planetlabs/go-stac/extensions/label/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemExtendedUnmarshal(t *testing.T) {
+	data := []byte(`{
+		"type": "Feature",
+		"stac_version": "1.0.0",
+		"id": "label-id",
+		"geometry": null,
+		"properties": {
+			"datetime": "2024-01-01T00:00:00Z",
+			"label:properties": ["class"],
+			"label:classes": [
+				{"name": "class", "classes": ["building", "road"]}
+			],
+			"label:description": "Building and road labels",
+			"label:type": "vector",
+			"label:tasks": ["segmentation"],
+			"label:methods": ["manual"],
+			"label:overviews": [
+				{
+					"property_key": "class",
+					"counts": [
+						{"name": "building", "count": 10},
+						{"name": "road", "count": 3}
+					]
+				}
+			]
+		},
+		"links": [
+			{
+				"href": "./image.json",
+				"rel": "source",
+				"label:assets": ["visual"]
+			}
+		],
+		"assets": {},
+		"stac_extensions": [
+			"https://stac-extensions.github.io/label/v1.0.1/schema.json"
+		]
+	}`)
+
+	item := &stac.Item{}
+	require.NoError(t, json.Unmarshal(data, item))
+
+	name := "class"
+	require.Len(t, item.Extensions, 1)
+	assert.Equal(t, &label.Item{
+		Properties:  []string{"class"},
+		Classes:     []*label.Class{{Name: &name, Classes: []any{"building", "road"}}},
+		Description: "Building and road labels",
+		Type:        label.TypeVector,
+		Tasks:       []string{label.TaskSegmentation},
+		Methods:     []string{label.MethodManual},
+		Overviews: []*label.Overview{{
+			PropertyKey: "class",
+			Counts: []*label.Count{
+				{Name: "building", Count: 10},
+				{Name: "road", Count: 3},
+			},
+		}},
+	}, item.Extensions[0])
+	assert.Equal(t, map[string]any{"datetime": "2024-01-01T00:00:00Z"}, item.Properties)
+
+	require.Len(t, item.Links[0].Extensions, 1)
+	assert.Equal(t, &label.Link{Assets: []string{"visual"}}, item.Links[0].Extensions[0])
+
+	encoded, err := json.Marshal(item)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(encoded))
+}
+
+func TestRasterItemMarshal(t *testing.T) {
+	item := &stac.Item{
+		Version:    "1.0.0",
+		Id:         "label-id",
+		Properties: map[string]any{"datetime": "2024-01-01T00:00:00Z"},
+		Extensions: []stac.Extension{
+			&label.Item{
+				Classes:     []*label.Class{{Classes: []any{0, 1}}},
+				Description: "Water mask",
+				Type:        label.TypeRaster,
+				Overviews: []*label.Overview{{
+					Statistics: []*label.Statistic{{Name: "mean", Value: 0.25}},
+				}},
+			},
+		},
+	}
+
+	data, err := json.Marshal(item)
+	require.NoError(t, err)
+
+	expected := `{
+		"type": "Feature",
+		"stac_version": "1.0.0",
+		"id": "label-id",
+		"geometry": null,
+		"properties": {
+			"datetime": "2024-01-01T00:00:00Z",
+			"label:properties": null,
+			"label:classes": [{"name": null, "classes": [0, 1]}],
+			"label:description": "Water mask",
+			"label:type": "raster",
+			"label:overviews": [
+				{"statistics": [{"name": "mean", "value": 0.25}]}
+			]
+		},
+		"links": [],
+		"assets": {},
+		"stac_extensions": [
+			"https://stac-extensions.github.io/label/v1.0.1/schema.json"
+		]
+	}`
+
+	assert.JSONEq(t, expected, string(data))
+}
+
+func TestNotLabelItem(t *testing.T) {
+	data := []byte(`{
+		"type": "Feature",
+		"stac_version": "1.0.0",
+		"id": "item-id",
+		"geometry": null,
+		"properties": {"datetime": "2024-01-01T00:00:00Z"},
+		"links": [],
+		"assets": {},
+		"stac_extensions": [
+			"https://stac-extensions.github.io/label/v1.0.1/schema.json"
+		]
+	}`)
+
+	item := &stac.Item{}
+	require.NoError(t, json.Unmarshal(data, item))
+	assert.Empty(t, item.Extensions)
+	assert.Equal(t, map[string]any{"datetime": "2024-01-01T00:00:00Z"}, item.Properties)
+}
+
+func writeJSON(t *testing.T, path string, value any) {
+	t.Helper()
+	data, err := json.Marshal(value)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, data, 0644))
+}
+
+func item(id string, properties map[string]any, links ...map[string]any) map[string]any {
+	properties["datetime"] = "2024-01-01T00:00:00Z"
+	if links == nil {
+		links = []map[string]any{}
+	}
+	return map[string]any{
+		"type":         "Feature",
+		"stac_version": "1.0.0",
+		"id":           id,
+		"geometry":     nil,
+		"properties":   properties,
+		"links":        links,
+		"assets":       map[string]any{},
+	}
+}
+
+func TestPairs(t *testing.T) {
+	dir := t.TempDir()
+
+	writeJSON(t, filepath.Join(dir, "catalog.json"), map[string]any{
+		"type":         "Catalog",
+		"stac_version": "1.0.0",
+		"id":           "training",
+		"description":  "Training data",
+		"links": []map[string]any{
+			{"rel": "item", "href": "./labels/label-1.json"},
+			{"rel": "item", "href": "./labels/label-2.json"},
+			{"rel": "item", "href": "./images/image-1.json"},
+		},
+	})
+
+	labelProperties := func() map[string]any {
+		return map[string]any{
+			"label:properties":  []string{"class"},
+			"label:description": "Labels",
+			"label:type":        "vector",
+		}
+	}
+	writeJSON(t, filepath.Join(dir, "labels", "label-1.json"), item("label-1", labelProperties(),
+		map[string]any{"rel": "source", "href": "../images/image-1.json", "label:assets": []string{"visual"}},
+	))
+	writeJSON(t, filepath.Join(dir, "labels", "label-2.json"), item("label-2", labelProperties(),
+		map[string]any{"rel": "source", "href": "../images/image-1.json"},
+		map[string]any{"rel": "source", "href": "../images/image-2.json"},
+	))
+	writeJSON(t, filepath.Join(dir, "images", "image-1.json"), item("image-1", map[string]any{}))
+	writeJSON(t, filepath.Join(dir, "images", "image-2.json"), item("image-2", map[string]any{}))
+
+	pairs, err := label.Pairs(filepath.Join(dir, "catalog.json"))
+	require.NoError(t, err)
+	require.Len(t, pairs, 2)
+
+	assert.Equal(t, filepath.Join(dir, "labels", "label-1.json"), pairs[0].Location)
+	assert.Equal(t, "label-1", pairs[0].Label["id"])
+	require.Len(t, pairs[0].Sources, 1)
+	assert.Equal(t, filepath.Join(dir, "images", "image-1.json"), pairs[0].Sources[0].Location)
+	assert.Equal(t, "image-1", pairs[0].Sources[0].Item["id"])
+	assert.Equal(t, []string{"visual"}, pairs[0].Sources[0].Assets)
+
+	assert.Equal(t, "label-2", pairs[1].Label["id"])
+	require.Len(t, pairs[1].Sources, 2)
+	assert.Equal(t, "image-1", pairs[1].Sources[0].Item["id"])
+	assert.Empty(t, pairs[1].Sources[0].Assets)
+	assert.Equal(t, "image-2", pairs[1].Sources[1].Item["id"])
+}
+
+func TestSourcesMissing(t *testing.T) {
+	dir := t.TempDir()
+	location := filepath.Join(dir, "label.json")
+	resource := crawler.Resource(item("label", map[string]any{"label:type": "raster"},
+		map[string]any{"rel": "source", "href": "./missing.json"},
+	))
+
+	writeJSON(t, location, resource)
+	data, err := os.ReadFile(location)
+	require.NoError(t, err)
+	resource = crawler.Resource{}
+	require.NoError(t, json.Unmarshal(data, &resource))
+
+	assert.True(t, label.IsLabel(resource))
+	_, err = label.Sources(resource, location)
+	assert.ErrorContains(t, err, "failed to load source")
+}
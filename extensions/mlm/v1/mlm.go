@@ -0,0 +1,346 @@
+package mlm
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"strings"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/extensions/eo/v1"
+)
+
+const (
+	extensionUri     = "https://stac-extensions.github.io/mlm/v1.4.0/schema.json"
+	extensionPattern = `https://stac-extensions.github.io/mlm/v1\..*/schema.json`
+	prefix           = "mlm"
+	propertiesKey    = "properties"
+)
+
+func init() {
+	r := regexp.MustCompile(extensionPattern)
+
+	stac.RegisterItemExtension(r, func() stac.Extension { return &Item{} })
+	stac.RegisterAssetExtension(r, func() stac.Extension { return &Asset{} })
+}
+
+const (
+	TaskRegression             = "regression"
+	TaskClassification         = "classification"
+	TaskSceneClassification    = "scene-classification"
+	TaskDetection              = "detection"
+	TaskObjectDetection        = "object-detection"
+	TaskSegmentation           = "segmentation"
+	TaskSemanticSegmentation   = "semantic-segmentation"
+	TaskInstanceSegmentation   = "instance-segmentation"
+	TaskPanopticSegmentation   = "panoptic-segmentation"
+	TaskSimilaritySearch       = "similarity-search"
+	TaskGenerative             = "generative"
+	TaskImageCaptioning        = "image-captioning"
+	TaskSuperResolution        = "super-resolution"
+	TaskDownscaling            = "downscaling"
+	TaskFeatureExtraction      = "feature-extraction"
+	TaskChangeDetection        = "change-detection"
+	TaskObjectTracking         = "object-tracking"
+	TaskImageGeneration        = "image-generation"
+	TaskImageToImageGeneration = "image-to-image-generation"
+)
+
+const (
+	ScalingMinMax     = "min-max"
+	ScalingZScore     = "z-score"
+	ScalingClip       = "clip"
+	ScalingClipMin    = "clip-min"
+	ScalingClipMax    = "clip-max"
+	ScalingOffset     = "offset"
+	ScalingScale      = "scale"
+	ScalingProcessing = "processing"
+)
+
+const (
+	ResizeCrop                 = "crop"
+	ResizePad                  = "pad"
+	ResizeInterpolationNearest = "interpolation-nearest"
+	ResizeInterpolationLinear  = "interpolation-linear"
+	ResizeInterpolationCubic   = "interpolation-cubic"
+	ResizeInterpolationArea    = "interpolation-area"
+	ResizeInterpolationLanczos = "interpolation-lanczos4"
+	ResizeInterpolationMax     = "interpolation-max"
+	ResizeWrapFillOutliers     = "wrap-fill-outliers"
+	ResizeWrapInverseMap       = "wrap-inverse-map"
+)
+
+type Item struct {
+	Name                   string         `json:"mlm:name,omitempty"`
+	Architecture           string         `json:"mlm:architecture,omitempty"`
+	Tasks                  []string       `json:"mlm:tasks,omitempty"`
+	Framework              string         `json:"mlm:framework,omitempty"`
+	FrameworkVersion       string         `json:"mlm:framework_version,omitempty"`
+	MemorySize             *int64         `json:"mlm:memory_size,omitempty"`
+	TotalParameters        *int64         `json:"mlm:total_parameters,omitempty"`
+	Pretrained             *bool          `json:"mlm:pretrained,omitempty"`
+	PretrainedSource       string         `json:"mlm:pretrained_source,omitempty"`
+	BatchSizeSuggestion    *int           `json:"mlm:batch_size_suggestion,omitempty"`
+	Accelerator            string         `json:"mlm:accelerator,omitempty"`
+	AcceleratorConstrained *bool          `json:"mlm:accelerator_constrained,omitempty"`
+	AcceleratorSummary     string         `json:"mlm:accelerator_summary,omitempty"`
+	AcceleratorCount       *int           `json:"mlm:accelerator_count,omitempty"`
+	Input                  []*Input       `json:"mlm:input,omitempty"`
+	Output                 []*Output      `json:"mlm:output,omitempty"`
+	Hyperparameters        map[string]any `json:"mlm:hyperparameters,omitempty"`
+}
+
+// Input describes a model input and how data is prepared for it.
+type Input struct {
+	Name                  string          `json:"name"`
+	Bands                 []*ModelBand    `json:"bands"`
+	Input                 *Structure      `json:"input"`
+	Description           string          `json:"description,omitempty"`
+	ValueScaling          []*ValueScaling `json:"value_scaling,omitempty"`
+	ResizeType            string          `json:"resize_type,omitempty"`
+	PreProcessingFunction *Expression     `json:"pre_processing_function,omitempty"`
+}
+
+// Output describes a model output.  The classes use the structure from the
+// classification extension.
+type Output struct {
+	Name                   string           `json:"name"`
+	Tasks                  []string         `json:"tasks"`
+	Result                 *Structure       `json:"result"`
+	Description            string           `json:"description,omitempty"`
+	Classes                []map[string]any `json:"classification:classes,omitempty"`
+	PostProcessingFunction *Expression      `json:"post_processing_function,omitempty"`
+}
+
+// Structure describes the shape, dimension order, and data type of a tensor.
+// A shape value of -1 is used for a variable size dimension (e.g. the batch).
+type Structure struct {
+	Shape    []int    `json:"shape"`
+	DimOrder []string `json:"dim_order"`
+	DataType string   `json:"data_type"`
+}
+
+// ModelBand is a band used as input to a model.  A band with an expression is
+// derived from other bands.  Bands with only a name are encoded as strings.
+type ModelBand struct {
+	Name       string `json:"name"`
+	Format     string `json:"format,omitempty"`
+	Expression any    `json:"expression,omitempty"`
+}
+
+var (
+	_ json.Marshaler   = (*ModelBand)(nil)
+	_ json.Unmarshaler = (*ModelBand)(nil)
+)
+
+type modelBand ModelBand
+
+func (b *ModelBand) MarshalJSON() ([]byte, error) {
+	if b.Format == "" && b.Expression == nil {
+		return json.Marshal(b.Name)
+	}
+	return json.Marshal((*modelBand)(b))
+}
+
+func (b *ModelBand) UnmarshalJSON(data []byte) error {
+	name := ""
+	if err := json.Unmarshal(data, &name); err == nil {
+		*b = ModelBand{Name: name}
+		return nil
+	}
+	return json.Unmarshal(data, (*modelBand)(b))
+}
+
+type ValueScaling struct {
+	Type       string   `json:"type"`
+	Minimum    *float64 `json:"minimum,omitempty"`
+	Maximum    *float64 `json:"maximum,omitempty"`
+	Mean       *float64 `json:"mean,omitempty"`
+	Stddev     *float64 `json:"stddev,omitempty"`
+	Value      *float64 `json:"value,omitempty"`
+	Format     string   `json:"format,omitempty"`
+	Expression any      `json:"expression,omitempty"`
+}
+
+type Expression struct {
+	Format     string `json:"format"`
+	Expression any    `json:"expression"`
+}
+
+type Asset struct {
+	ArtifactType  string `json:"mlm:artifact_type,omitempty"`
+	CompileMethod string `json:"mlm:compile_method,omitempty"`
+}
+
+var (
+	_ stac.Extension = (*Item)(nil)
+	_ stac.Extension = (*Asset)(nil)
+)
+
+func (*Item) URI() string {
+	return extensionUri
+}
+
+func (e *Item) Encode(itemMap map[string]any) error {
+	current, err := itemProperties(itemMap)
+	if err != nil {
+		return err
+	}
+	properties := maps.Clone(current)
+	if err := encode(e, properties); err != nil {
+		return err
+	}
+	itemMap[propertiesKey] = properties
+	return nil
+}
+
+func (e *Item) Decode(itemMap map[string]any) error {
+	properties, err := itemProperties(itemMap)
+	if err != nil {
+		return err
+	}
+	if err := decode(properties, e); err != nil {
+		return err
+	}
+	itemMap[propertiesKey] = properties
+	return nil
+}
+
+func (*Asset) URI() string {
+	return extensionUri
+}
+
+func (e *Asset) Encode(assetMap map[string]any) error {
+	return stac.EncodeExtendedMap(e, assetMap)
+}
+
+func (e *Asset) Decode(assetMap map[string]any) error {
+	return stac.DecodeExtendedMap(e, assetMap, prefix)
+}
+
+func itemProperties(itemMap map[string]any) (map[string]any, error) {
+	value, ok := itemMap[propertiesKey]
+	if !ok || value == nil {
+		return map[string]any{}, nil
+	}
+	properties, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected a properties object, got %T", value)
+	}
+	return properties, nil
+}
+
+// encode adds the fields of an extension to a map.  The fields are encoded as
+// JSON so that bands with only a name are written as strings.
+func encode(extension any, data map[string]any) error {
+	fields := map[string]any{}
+	if err := remarshal(extension, &fields); err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return stac.ErrExtensionDoesNotApply
+	}
+	maps.Copy(data, fields)
+	return nil
+}
+
+// decode sets the fields of an extension from the prefixed values in a map and
+// removes the decoded values from the map.
+func decode(data map[string]any, extension any) error {
+	fields := map[string]any{}
+	for key, value := range data {
+		if strings.HasPrefix(key, prefix+":") {
+			fields[key] = value
+		}
+	}
+	if len(fields) == 0 {
+		return stac.ErrExtensionDoesNotApply
+	}
+	if err := remarshal(fields, extension); err != nil {
+		return fmt.Errorf("invalid %s fields: %w", prefix, err)
+	}
+
+	decoded := map[string]any{}
+	if err := remarshal(extension, &decoded); err != nil {
+		return err
+	}
+	for key := range decoded {
+		delete(data, key)
+	}
+	return nil
+}
+
+func remarshal(value any, target any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+// ValidateBands checks that the bands used by the model inputs of an item are
+// present on the item.  Band names are collected from the bands and eo:bands of
+// the item properties and assets.  Bands derived with an
+// expression are not required to be present.
+func ValidateBands(item *stac.Item) error {
+	var model *Item
+	for _, extension := range item.Extensions {
+		if e, ok := extension.(*Item); ok {
+			model = e
+			break
+		}
+	}
+	if model == nil {
+		return fmt.Errorf("item %s does not use the mlm extension", item.Id)
+	}
+
+	names := itemBandNames(item)
+	problems := []string{}
+	for i, input := range model.Input {
+		inputName := input.Name
+		if inputName == "" {
+			inputName = fmt.Sprintf("%d", i)
+		}
+		for _, band := range input.Bands {
+			if band.Expression != nil {
+				continue
+			}
+			if !names[band.Name] {
+				problems = append(problems, fmt.Sprintf("input %q uses band %q", inputName, band.Name))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("bands not found on item %s: %s", item.Id, strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+func itemBandNames(item *stac.Item) map[string]bool {
+	names := map[string]bool{}
+	for _, key := range []string{"bands", "eo:bands"} {
+		bands, _ := item.Properties[key].([]any)
+		for _, band := range bands {
+			if bandMap, ok := band.(map[string]any); ok {
+				if name, ok := bandMap["name"].(string); ok {
+					names[name] = true
+				}
+			}
+		}
+	}
+	for _, asset := range item.Assets {
+		for _, band := range asset.Bands {
+			names[band.Name] = true
+		}
+		for _, extension := range asset.Extensions {
+			if e, ok := extension.(*eo.Asset); ok {
+				for _, band := range e.Bands {
+					names[band.Name] = true
+				}
+			}
+		}
+	}
+	delete(names, "")
+	return names
+}
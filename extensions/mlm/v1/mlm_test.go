@@ -0,0 +1,245 @@
+package mlm_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/extensions/eo/v1"
+	"github.com/planetlabs/go-stac/extensions/mlm/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const itemData = `{
+	"type": "Feature",
+	"stac_version": "1.1.0",
+	"id": "resnet-18",
+	"geometry": null,
+	"properties": {
+		"datetime": "2024-01-01T00:00:00Z",
+		"mlm:name": "ResNet-18 Sentinel-2",
+		"mlm:architecture": "ResNet",
+		"mlm:tasks": ["classification"],
+		"mlm:framework": "pytorch",
+		"mlm:framework_version": "2.1.2",
+		"mlm:total_parameters": 11700000,
+		"mlm:pretrained": true,
+		"mlm:accelerator": "cuda",
+		"mlm:input": [
+			{
+				"name": "13 band Sentinel-2 batch",
+				"bands": [
+					"B01",
+					"B02",
+					{"name": "NDVI", "format": "python", "expression": "(B08 - B04) / (B08 + B04)"}
+				],
+				"input": {
+					"shape": [-1, 3, 64, 64],
+					"dim_order": ["batch", "channel", "height", "width"],
+					"data_type": "float32"
+				},
+				"value_scaling": [
+					{"type": "z-score", "mean": 1354.4, "stddev": 245.7},
+					{"type": "min-max", "minimum": 0, "maximum": 10000},
+					{"type": "clip", "minimum": -1, "maximum": 1}
+				],
+				"resize_type": "interpolation-nearest",
+				"pre_processing_function": {
+					"format": "python",
+					"expression": "torchgeo.datamodules.eurosat:preprocess"
+				}
+			}
+		],
+		"mlm:output": [
+			{
+				"name": "classification",
+				"tasks": ["classification"],
+				"result": {
+					"shape": [-1, 2],
+					"dim_order": ["batch", "class"],
+					"data_type": "float32"
+				},
+				"classification:classes": [
+					{"value": 0, "name": "water"},
+					{"value": 1, "name": "land"}
+				]
+			}
+		],
+		"mlm:hyperparameters": {"learning_rate": 0.001}
+	},
+	"links": [],
+	"assets": {
+		"weights": {
+			"href": "https://example.com/model/resnet-18.pt",
+			"roles": ["mlm:model", "mlm:weights"],
+			"mlm:artifact_type": "torch.save"
+		},
+		"data": {
+			"href": "https://example.com/data.tif",
+			"bands": [{"name": "B01"}]
+		},
+		"eo-data": {
+			"href": "https://example.com/eo-data.tif",
+			"eo:bands": [{"name": "B02"}]
+		}
+	},
+	"stac_extensions": [
+		"https://stac-extensions.github.io/eo/v1.1.0/schema.json",
+		"https://stac-extensions.github.io/mlm/v1.4.0/schema.json"
+	]
+}`
+
+func float(value float64) *float64 {
+	return &value
+}
+
+func TestItemExtendedUnmarshal(t *testing.T) {
+	item := &stac.Item{}
+	require.NoError(t, json.Unmarshal([]byte(itemData), item))
+
+	require.Len(t, item.Extensions, 1)
+	model, ok := item.Extensions[0].(*mlm.Item)
+	require.True(t, ok)
+
+	assert.Equal(t, "ResNet-18 Sentinel-2", model.Name)
+	assert.Equal(t, []string{mlm.TaskClassification}, model.Tasks)
+	require.NotNil(t, model.TotalParameters)
+	assert.Equal(t, int64(11700000), *model.TotalParameters)
+	require.NotNil(t, model.Pretrained)
+	assert.True(t, *model.Pretrained)
+
+	require.Len(t, model.Input, 1)
+	input := model.Input[0]
+	assert.Equal(t, []*mlm.ModelBand{
+		{Name: "B01"},
+		{Name: "B02"},
+		{Name: "NDVI", Format: "python", Expression: "(B08 - B04) / (B08 + B04)"},
+	}, input.Bands)
+	assert.Equal(t, &mlm.Structure{
+		Shape:    []int{-1, 3, 64, 64},
+		DimOrder: []string{"batch", "channel", "height", "width"},
+		DataType: "float32",
+	}, input.Input)
+	assert.Equal(t, []*mlm.ValueScaling{
+		{Type: mlm.ScalingZScore, Mean: float(1354.4), Stddev: float(245.7)},
+		{Type: mlm.ScalingMinMax, Minimum: float(0), Maximum: float(10000)},
+		{Type: mlm.ScalingClip, Minimum: float(-1), Maximum: float(1)},
+	}, input.ValueScaling)
+	assert.Equal(t, mlm.ResizeInterpolationNearest, input.ResizeType)
+	assert.Equal(t, &mlm.Expression{Format: "python", Expression: "torchgeo.datamodules.eurosat:preprocess"}, input.PreProcessingFunction)
+
+	require.Len(t, model.Output, 1)
+	assert.Equal(t, []int{-1, 2}, model.Output[0].Result.Shape)
+	assert.Len(t, model.Output[0].Classes, 2)
+
+	assert.Equal(t, map[string]any{"datetime": "2024-01-01T00:00:00Z"}, item.Properties)
+
+	weights := item.Assets["weights"]
+	require.Len(t, weights.Extensions, 1)
+	assert.Equal(t, &mlm.Asset{ArtifactType: "torch.save"}, weights.Extensions[0])
+
+	encoded, err := json.Marshal(item)
+	require.NoError(t, err)
+	assert.JSONEq(t, itemData, string(encoded))
+}
+
+func TestItemExtendedMarshal(t *testing.T) {
+	item := &stac.Item{
+		Version:    "1.1.0",
+		Id:         "model",
+		Properties: map[string]any{"datetime": "2024-01-01T00:00:00Z"},
+		Extensions: []stac.Extension{
+			&mlm.Item{
+				Name:         "model",
+				Architecture: "U-Net",
+				Tasks:        []string{mlm.TaskSemanticSegmentation},
+				Input: []*mlm.Input{{
+					Name:  "rgb",
+					Bands: []*mlm.ModelBand{{Name: "red"}, {Name: "green"}, {Name: "blue"}},
+					Input: &mlm.Structure{
+						Shape:    []int{1, 3, 256, 256},
+						DimOrder: []string{"batch", "channel", "height", "width"},
+						DataType: "uint8",
+					},
+				}},
+				Output: []*mlm.Output{{
+					Name:  "mask",
+					Tasks: []string{mlm.TaskSemanticSegmentation},
+					Result: &mlm.Structure{
+						Shape:    []int{1, 256, 256},
+						DimOrder: []string{"batch", "height", "width"},
+						DataType: "uint8",
+					},
+				}},
+			},
+		},
+	}
+
+	data, err := json.Marshal(item)
+	require.NoError(t, err)
+
+	expected := `{
+		"type": "Feature",
+		"stac_version": "1.1.0",
+		"id": "model",
+		"geometry": null,
+		"properties": {
+			"datetime": "2024-01-01T00:00:00Z",
+			"mlm:name": "model",
+			"mlm:architecture": "U-Net",
+			"mlm:tasks": ["semantic-segmentation"],
+			"mlm:input": [
+				{
+					"name": "rgb",
+					"bands": ["red", "green", "blue"],
+					"input": {
+						"shape": [1, 3, 256, 256],
+						"dim_order": ["batch", "channel", "height", "width"],
+						"data_type": "uint8"
+					}
+				}
+			],
+			"mlm:output": [
+				{
+					"name": "mask",
+					"tasks": ["semantic-segmentation"],
+					"result": {
+						"shape": [1, 256, 256],
+						"dim_order": ["batch", "height", "width"],
+						"data_type": "uint8"
+					}
+				}
+			]
+		},
+		"links": [],
+		"assets": {},
+		"stac_extensions": [
+			"https://stac-extensions.github.io/mlm/v1.4.0/schema.json"
+		]
+	}`
+
+	assert.JSONEq(t, expected, string(data))
+	assert.Equal(t, map[string]any{"datetime": "2024-01-01T00:00:00Z"}, item.Properties)
+}
+
+func TestValidateBands(t *testing.T) {
+	item := &stac.Item{}
+	require.NoError(t, json.Unmarshal([]byte(itemData), item))
+
+	require.IsType(t, &eo.Asset{}, item.Assets["eo-data"].Extensions[0])
+	assert.NoError(t, mlm.ValidateBands(item))
+
+	item.Assets["eo-data"].Extensions = nil
+	assert.EqualError(t, mlm.ValidateBands(item), `bands not found on item resnet-18: input "13 band Sentinel-2 batch" uses band "B02"`)
+
+	item.Properties["bands"] = []any{map[string]any{"name": "B02"}}
+	assert.NoError(t, mlm.ValidateBands(item))
+
+	delete(item.Properties, "bands")
+	item.Properties["eo:bands"] = []any{map[string]any{"name": "B02", "common_name": "blue"}}
+	assert.NoError(t, mlm.ValidateBands(item))
+
+	item.Extensions = nil
+	assert.ErrorContains(t, mlm.ValidateBands(item), "does not use the mlm extension")
+}
@@ -0,0 +1,121 @@
+package processing
+
+import (
+	"regexp"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/internal/summaries"
+)
+
+const (
+	extensionUri     = "https://stac-extensions.github.io/processing/v1.2.0/schema.json"
+	extensionPattern = `https://stac-extensions.github.io/processing/v1\..*/schema.json`
+	prefix           = "processing"
+)
+
+func init() {
+	stac.RegisterItemExtension(
+		regexp.MustCompile(extensionPattern),
+		func() stac.Extension {
+			return &Item{}
+		},
+	)
+
+	stac.RegisterAssetExtension(
+		regexp.MustCompile(extensionPattern),
+		func() stac.Extension {
+			return &Asset{}
+		},
+	)
+
+	stac.RegisterCollectionExtension(
+		regexp.MustCompile(extensionPattern),
+		func() stac.Extension {
+			return &Collection{}
+		},
+	)
+}
+
+type Item struct {
+	Expression *Expression       `json:"processing:expression,omitempty"`
+	Lineage    string            `json:"processing:lineage,omitempty"`
+	Level      string            `json:"processing:level,omitempty"`
+	Facility   string            `json:"processing:facility,omitempty"`
+	Software   map[string]string `json:"processing:software,omitempty"`
+	Datetime   string            `json:"processing:datetime,omitempty"`
+	Version    string            `json:"processing:version,omitempty"`
+}
+
+type Asset struct {
+	Expression *Expression       `json:"processing:expression,omitempty"`
+	Lineage    string            `json:"processing:lineage,omitempty"`
+	Level      string            `json:"processing:level,omitempty"`
+	Facility   string            `json:"processing:facility,omitempty"`
+	Software   map[string]string `json:"processing:software,omitempty"`
+	Datetime   string            `json:"processing:datetime,omitempty"`
+	Version    string            `json:"processing:version,omitempty"`
+}
+
+// Expression describes how the data was computed (e.g. a band math formula).
+type Expression struct {
+	Format     string `json:"format"`
+	Expression any    `json:"expression"`
+}
+
+// Collection holds summaries of the processing fields of a collection's items.
+// These are encoded in the collection summaries.  Summaries in a different form
+// (e.g. a JSON Schema instead of a list of levels) are only available in the
+// collection's Summaries map.
+type Collection struct {
+	Level    []string       `json:"processing:level,omitempty"`
+	Facility []string       `json:"processing:facility,omitempty"`
+	Version  []string       `json:"processing:version,omitempty"`
+	Datetime *DatetimeRange `json:"processing:datetime,omitempty"`
+}
+
+type DatetimeRange struct {
+	Minimum string `json:"minimum"`
+	Maximum string `json:"maximum"`
+}
+
+var (
+	_ stac.Extension = (*Item)(nil)
+	_ stac.Extension = (*Asset)(nil)
+	_ stac.Extension = (*Collection)(nil)
+)
+
+func (*Item) URI() string {
+	return extensionUri
+}
+
+func (e *Item) Encode(itemMap map[string]any) error {
+	return stac.EncodeExtendedItemProperties(e, itemMap)
+}
+
+func (e *Item) Decode(itemMap map[string]any) error {
+	return stac.DecodeExtendedItemProperties(e, itemMap)
+}
+
+func (*Asset) URI() string {
+	return extensionUri
+}
+
+func (e *Asset) Encode(assetMap map[string]any) error {
+	return stac.EncodeExtendedMap(e, assetMap)
+}
+
+func (e *Asset) Decode(assetMap map[string]any) error {
+	return stac.DecodeExtendedMap(e, assetMap, prefix)
+}
+
+func (*Collection) URI() string {
+	return extensionUri
+}
+
+func (e *Collection) Encode(collectionMap map[string]any) error {
+	return summaries.Encode(e, collectionMap)
+}
+
+func (e *Collection) Decode(collectionMap map[string]any) error {
+	return summaries.Decode(e, collectionMap, prefix)
+}
@@ -0,0 +1,162 @@
+package processing_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/extensions/processing/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemExtendedMarshal(t *testing.T) {
+	item := &stac.Item{
+		Version: "1.0.0",
+		Id:      "item-id",
+		Geometry: map[string]any{
+			"type":        "Point",
+			"coordinates": []any{float64(0), float64(0)},
+		},
+		Properties: map[string]any{
+			"test": "value",
+		},
+		Links: []*stac.Link{
+			{Href: "https://example.com/stac/item-id", Rel: "self"},
+		},
+		Assets: map[string]*stac.Asset{
+			"ndvi": {
+				Title: "NDVI",
+				Href:  "https://example.com/stac/item-id/ndvi.tif",
+				Type:  "image/tif",
+				Extensions: []stac.Extension{
+					&processing.Asset{
+						Expression: &processing.Expression{
+							Format:     "rio-calc",
+							Expression: "(B08-B04)/(B08+B04)",
+						},
+					},
+				},
+			},
+		},
+		Extensions: []stac.Extension{
+			&processing.Item{
+				Level:    "L2A",
+				Facility: "Copernicus S2 Processing and Archiving Facility",
+				Lineage:  "Generation of a Level-2A product from Level-1C",
+				Software: map[string]string{"Sentinel-2 IPF": "2.09"},
+				Datetime: "2021-01-02T00:00:00Z",
+			},
+		},
+	}
+
+	data, err := json.Marshal(item)
+	require.NoError(t, err)
+
+	expected := `{
+		"type": "Feature",
+		"stac_version": "1.0.0",
+		"id": "item-id",
+		"geometry": {
+			"type": "Point",
+			"coordinates": [0, 0]
+		},
+		"properties": {
+			"test": "value",
+			"processing:level": "L2A",
+			"processing:facility": "Copernicus S2 Processing and Archiving Facility",
+			"processing:lineage": "Generation of a Level-2A product from Level-1C",
+			"processing:software": {"Sentinel-2 IPF": "2.09"},
+			"processing:datetime": "2021-01-02T00:00:00Z"
+		},
+		"links": [
+			{
+				"rel": "self",
+				"href": "https://example.com/stac/item-id"
+			}
+		],
+		"assets": {
+			"ndvi": {
+				"title": "NDVI",
+				"href": "https://example.com/stac/item-id/ndvi.tif",
+				"type": "image/tif",
+				"processing:expression": {
+					"format": "rio-calc",
+					"expression": "(B08-B04)/(B08+B04)"
+				}
+			}
+		},
+		"stac_extensions": [
+			"https://stac-extensions.github.io/processing/v1.2.0/schema.json"
+		]
+	}`
+
+	assert.JSONEq(t, expected, string(data))
+
+	decoded := &stac.Item{}
+	require.NoError(t, json.Unmarshal(data, decoded))
+	assert.Equal(t, item, decoded)
+}
+
+func TestCollectionSummaries(t *testing.T) {
+	data := []byte(`{
+		"type": "Collection",
+		"stac_version": "1.0.0",
+		"id": "collection-id",
+		"description": "Test Collection",
+		"license": "various",
+		"extent": {
+			"spatial": {
+				"bbox": [[-180, -90, 180, 90]]
+			}
+		},
+		"summaries": {
+			"processing:level": ["L1C", "L2A"],
+			"processing:datetime": {"minimum": "2021-01-01T00:00:00Z", "maximum": "2021-06-01T00:00:00Z"}
+		},
+		"links": [],
+		"stac_extensions": [
+			"https://stac-extensions.github.io/processing/v1.1.0/schema.json"
+		]
+	}`)
+
+	collection := &stac.Collection{}
+	require.NoError(t, json.Unmarshal(data, collection))
+	require.Len(t, collection.Extensions, 1)
+	assert.Equal(t, &processing.Collection{
+		Level:    []string{"L1C", "L2A"},
+		Datetime: &processing.DatetimeRange{Minimum: "2021-01-01T00:00:00Z", Maximum: "2021-06-01T00:00:00Z"},
+	}, collection.Extensions[0])
+}
+
+func TestCollectionSummariesOtherForms(t *testing.T) {
+	data := []byte(`{
+		"type": "Collection",
+		"stac_version": "1.0.0",
+		"id": "collection-id",
+		"description": "Test Collection",
+		"license": "various",
+		"extent": {
+			"spatial": {
+				"bbox": [[-180, -90, 180, 90]]
+			}
+		},
+		"summaries": {
+			"processing:level": {"type": "string", "pattern": "^L[0-9][A-Z]?$"},
+			"processing:facility": ["Copernicus S2 IPF"],
+			"processing:datetime": ["2021-01-01T00:00:00Z", "2021-06-01T00:00:00Z"]
+		},
+		"links": [],
+		"stac_extensions": [
+			"https://stac-extensions.github.io/processing/v1.1.0/schema.json"
+		]
+	}`)
+
+	collection := &stac.Collection{}
+	require.NoError(t, json.Unmarshal(data, collection))
+	require.Len(t, collection.Extensions, 1)
+	assert.Equal(t, &processing.Collection{
+		Facility: []string{"Copernicus S2 IPF"},
+	}, collection.Extensions[0])
+	assert.Equal(t, map[string]any{"type": "string", "pattern": "^L[0-9][A-Z]?$"}, collection.Summaries["processing:level"])
+}
@@ -0,0 +1,132 @@
+package proj
+
+import (
+	"regexp"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/internal/affine"
+)
+
+const (
+	extensionUri     = "https://stac-extensions.github.io/projection/v1.1.0/schema.json"
+	extensionPattern = `https://stac-extensions.github.io/projection/v1\..*/schema.json`
+	prefix           = "proj"
+)
+
+func init() {
+	stac.RegisterItemExtension(
+		regexp.MustCompile(extensionPattern),
+		func() stac.Extension {
+			return &Item{}
+		},
+	)
+
+	stac.RegisterAssetExtension(
+		regexp.MustCompile(extensionPattern),
+		func() stac.Extension {
+			return &Asset{}
+		},
+	)
+}
+
+type Item struct {
+	EPSG      *int           `json:"proj:epsg,omitempty"`
+	WKT2      string         `json:"proj:wkt2,omitempty"`
+	PROJJSON  map[string]any `json:"proj:projjson,omitempty"`
+	Geometry  any            `json:"proj:geometry,omitempty"`
+	Bbox      []float64      `json:"proj:bbox,omitempty"`
+	Centroid  *Centroid      `json:"proj:centroid,omitempty"`
+	Shape     []int          `json:"proj:shape,omitempty"`
+	Transform []float64      `json:"proj:transform,omitempty"`
+}
+
+type Asset struct {
+	EPSG      *int           `json:"proj:epsg,omitempty"`
+	WKT2      string         `json:"proj:wkt2,omitempty"`
+	PROJJSON  map[string]any `json:"proj:projjson,omitempty"`
+	Geometry  any            `json:"proj:geometry,omitempty"`
+	Bbox      []float64      `json:"proj:bbox,omitempty"`
+	Centroid  *Centroid      `json:"proj:centroid,omitempty"`
+	Shape     []int          `json:"proj:shape,omitempty"`
+	Transform []float64      `json:"proj:transform,omitempty"`
+}
+
+type Centroid struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+var (
+	_ stac.Extension = (*Item)(nil)
+	_ stac.Extension = (*Asset)(nil)
+)
+
+func (*Item) URI() string {
+	return extensionUri
+}
+
+func (e *Item) Encode(itemMap map[string]any) error {
+	return stac.EncodeExtendedItemProperties(e, itemMap)
+}
+
+func (e *Item) Decode(itemMap map[string]any) error {
+	return stac.DecodeExtendedItemProperties(e, itemMap)
+}
+
+func (*Asset) URI() string {
+	return extensionUri
+}
+
+func (e *Asset) Encode(assetMap map[string]any) error {
+	return stac.EncodeExtendedMap(e, assetMap)
+}
+
+func (e *Asset) Decode(assetMap map[string]any) error {
+	return stac.DecodeExtendedMap(e, assetMap, prefix)
+}
+
+// PixelToNative returns the coordinates in the native CRS for pixel coordinates
+// (column and row) given the 6 or 9 values of a proj:transform.
+func PixelToNative(transform []float64, col float64, row float64) (float64, float64, error) {
+	t, err := affine.New(transform)
+	if err != nil {
+		return 0, 0, err
+	}
+	x, y := t.Apply(col, row)
+	return x, y, nil
+}
+
+// NativeToPixel returns the pixel coordinates (column and row) for coordinates
+// in the native CRS given the 6 or 9 values of a proj:transform.
+func NativeToPixel(transform []float64, x float64, y float64) (float64, float64, error) {
+	t, err := affine.New(transform)
+	if err != nil {
+		return 0, 0, err
+	}
+	inverse, err := t.Invert()
+	if err != nil {
+		return 0, 0, err
+	}
+	col, row := inverse.Apply(x, y)
+	return col, row, nil
+}
+
+// NativeBbox returns a proj:bbox value [minx, miny, maxx, maxy] for a raster
+// with the provided proj:shape and proj:transform.
+func NativeBbox(shape []int, transform []float64) ([]float64, error) {
+	t, err := affine.New(transform)
+	if err != nil {
+		return nil, err
+	}
+	return affine.Bounds(shape, t)
+}
+
+// NativeGeometry returns a proj:geometry value (a GeoJSON polygon) for the
+// footprint of a raster with the provided proj:shape and proj:transform.
+func NativeGeometry(shape []int, transform []float64) (map[string]any, error) {
+	t, err := affine.New(transform)
+	if err != nil {
+		return nil, err
+	}
+	return affine.Footprint(shape, t)
+}
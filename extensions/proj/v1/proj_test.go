@@ -0,0 +1,178 @@
+package proj_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/extensions/proj/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemExtendedMarshal(t *testing.T) {
+	epsg := 32659
+	item := &stac.Item{
+		Version: "1.0.0",
+		Id:      "item-id",
+		Geometry: map[string]any{
+			"type":        "Point",
+			"coordinates": []float64{0, 0},
+		},
+		Properties: map[string]any{
+			"test": "value",
+		},
+		Links: []*stac.Link{
+			{Href: "https://example.com/stac/item-id", Rel: "self"},
+		},
+		Assets: map[string]*stac.Asset{
+			"image": {
+				Title: "Image",
+				Href:  "https://example.com/stac/item-id/image.tif",
+				Type:  "image/tif",
+				Extensions: []stac.Extension{
+					&proj.Asset{
+						Shape:     []int{100, 50},
+						Transform: []float64{30, 0, 224985, 0, -30, 6790215},
+					},
+				},
+			},
+		},
+		Extensions: []stac.Extension{
+			&proj.Item{
+				EPSG:     &epsg,
+				Bbox:     []float64{224985, 6538485, 474315, 6790215},
+				Centroid: &proj.Centroid{Lat: 60.1, Lon: 170.3},
+				Shape:    []int{8391, 8311},
+			},
+		},
+	}
+
+	data, err := json.Marshal(item)
+	require.NoError(t, err)
+
+	expected := `{
+		"type": "Feature",
+		"stac_version": "1.0.0",
+		"id": "item-id",
+		"geometry": {
+			"type": "Point",
+			"coordinates": [0, 0]
+		},
+		"properties": {
+			"test": "value",
+			"proj:epsg": 32659,
+			"proj:bbox": [224985, 6538485, 474315, 6790215],
+			"proj:centroid": {"lat": 60.1, "lon": 170.3},
+			"proj:shape": [8391, 8311]
+		},
+		"links": [
+			{
+				"rel": "self",
+				"href": "https://example.com/stac/item-id"
+			}
+		],
+		"assets": {
+			"image": {
+				"title": "Image",
+				"href": "https://example.com/stac/item-id/image.tif",
+				"type": "image/tif",
+				"proj:shape": [100, 50],
+				"proj:transform": [30, 0, 224985, 0, -30, 6790215]
+			}
+		},
+		"stac_extensions": [
+			"https://stac-extensions.github.io/projection/v1.1.0/schema.json"
+		]
+	}`
+
+	assert.JSONEq(t, expected, string(data))
+}
+
+func TestItemExtendedUnmarshal(t *testing.T) {
+	data := []byte(`{
+		"type": "Feature",
+		"stac_version": "1.0.0",
+		"id": "item-id",
+		"geometry": {
+			"type": "Point",
+			"coordinates": [0, 0]
+		},
+		"properties": {
+			"test": "value",
+			"proj:epsg": 32659,
+			"proj:projjson": {"type": "ProjectedCRS", "name": "WGS 84 / UTM zone 59N"},
+			"proj:transform": [30, 0, 224985, 0, -30, 6790215, 0, 0, 1]
+		},
+		"links": [
+			{
+				"rel": "self",
+				"href": "https://example.com/stac/item-id"
+			}
+		],
+		"assets": {
+			"image": {
+				"title": "Image",
+				"href": "https://example.com/stac/item-id/image.tif",
+				"type": "image/tif"
+			}
+		},
+		"stac_extensions": [
+			"https://stac-extensions.github.io/projection/v1.0.0/schema.json"
+		]
+	}`)
+
+	item := &stac.Item{}
+	require.NoError(t, json.Unmarshal(data, item))
+
+	epsg := 32659
+	expected := &stac.Item{
+		Version: "1.0.0",
+		Id:      "item-id",
+		Geometry: map[string]any{
+			"type":        "Point",
+			"coordinates": []any{float64(0), float64(0)},
+		},
+		Properties: map[string]any{
+			"test": "value",
+		},
+		Links: []*stac.Link{
+			{Href: "https://example.com/stac/item-id", Rel: "self"},
+		},
+		Assets: map[string]*stac.Asset{
+			"image": {
+				Title: "Image",
+				Href:  "https://example.com/stac/item-id/image.tif",
+				Type:  "image/tif",
+			},
+		},
+		Extensions: []stac.Extension{
+			&proj.Item{
+				EPSG:      &epsg,
+				PROJJSON:  map[string]any{"type": "ProjectedCRS", "name": "WGS 84 / UTM zone 59N"},
+				Transform: []float64{30, 0, 224985, 0, -30, 6790215, 0, 0, 1},
+			},
+		},
+	}
+
+	assert.Equal(t, expected, item)
+}
+
+func TestTransformHelpers(t *testing.T) {
+	transform := []float64{30, 0, 224985, 0, -30, 6790215}
+
+	x, y, err := proj.PixelToNative(transform, 8311, 8391)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{474315, 6538485}, []float64{x, y})
+
+	col, row, err := proj.NativeToPixel(transform, 224985, 6790215)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0, 0}, []float64{col, row})
+
+	bbox, err := proj.NativeBbox([]int{8391, 8311}, transform)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{224985, 6538485, 474315, 6790215}, bbox)
+
+	_, err = proj.NativeGeometry([]int{8391}, transform)
+	assert.ErrorContains(t, err, "2 values")
+}
@@ -0,0 +1,242 @@
+package proj_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/extensions/proj/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjItemJSON(t *testing.T) {
+	cases := []struct {
+		name string
+		item *stac.Item
+		data string
+		err  string
+	}{
+		{
+			name: "extended item",
+			item: &stac.Item{
+				Version: "1.1.0",
+				Id:      "item-id",
+				Geometry: map[string]any{
+					"type":        "Point",
+					"coordinates": []any{1.1, 2.2},
+				},
+				Properties: map[string]any{
+					"test": "value",
+				},
+				Links: []*stac.Link{
+					{Href: "https://example.com/stac/item-id", Rel: "self"},
+				},
+				Assets: map[string]*stac.Asset{
+					"image": {
+						Title: "Image",
+						Href:  "https://example.com/stac/item-id/image.tif",
+						Type:  "image/tif",
+					},
+				},
+				Extensions: []stac.Extension{
+					&proj.Item{
+						Code: "EPSG:32659",
+						Geometry: map[string]any{
+							"type": "Polygon",
+							"coordinates": []any{[]any{
+								[]any{float64(224985), float64(6790215)},
+								[]any{float64(224985), float64(6538485)},
+								[]any{float64(474315), float64(6538485)},
+								[]any{float64(474315), float64(6790215)},
+								[]any{float64(224985), float64(6790215)},
+							}},
+						},
+						Bbox:      []float64{224985, 6538485, 474315, 6790215},
+						Centroid:  &proj.Centroid{Lat: 60.1, Lon: 170.3},
+						Shape:     []int{8391, 8311},
+						Transform: []float64{30, 0, 224985, 0, -30, 6790215},
+					},
+				},
+			},
+			data: `{
+				"type": "Feature",
+				"stac_version": "1.1.0",
+				"id": "item-id",
+				"geometry": {
+					"type": "Point",
+					"coordinates": [1.1, 2.2]
+				},
+				"properties": {
+					"test": "value",
+					"proj:code": "EPSG:32659",
+					"proj:geometry": {
+						"type": "Polygon",
+						"coordinates": [[
+							[224985, 6790215],
+							[224985, 6538485],
+							[474315, 6538485],
+							[474315, 6790215],
+							[224985, 6790215]
+						]]
+					},
+					"proj:bbox": [224985, 6538485, 474315, 6790215],
+					"proj:centroid": {"lat": 60.1, "lon": 170.3},
+					"proj:shape": [8391, 8311],
+					"proj:transform": [30, 0, 224985, 0, -30, 6790215]
+				},
+				"links": [
+					{
+						"rel": "self",
+						"href": "https://example.com/stac/item-id"
+					}
+				],
+				"assets": {
+					"image": {
+						"title": "Image",
+						"href": "https://example.com/stac/item-id/image.tif",
+						"type": "image/tif"
+					}
+				},
+				"stac_extensions": [
+					"https://stac-extensions.github.io/projection/v2.0.0/schema.json"
+				]
+			}`,
+		},
+		{
+			name: "extended asset and bands",
+			item: &stac.Item{
+				Version: "1.1.0",
+				Id:      "item-id",
+				Geometry: map[string]any{
+					"type":        "Point",
+					"coordinates": []any{1.1, 2.2},
+				},
+				Properties: map[string]any{
+					"test": "value",
+				},
+				Links: []*stac.Link{
+					{Href: "https://example.com/stac/item-id", Rel: "self"},
+				},
+				Assets: map[string]*stac.Asset{
+					"image": {
+						Title: "Image",
+						Href:  "https://example.com/stac/item-id/image.tif",
+						Type:  "image/tif",
+						Bands: []*stac.Band{
+							{
+								Name: "pan",
+								Extensions: []stac.Extension{
+									&proj.Band{
+										Shape:     []int{200, 100},
+										Transform: []float64{15, 0, 1000, 0, -15, 5000},
+									},
+								},
+							},
+						},
+						Extensions: []stac.Extension{
+							&proj.Asset{
+								Code:      "EPSG:3857",
+								WKT2:      "PROJCS[...]",
+								Shape:     []int{100, 50},
+								Transform: []float64{30, 0, 1000, 0, -30, 5000},
+							},
+						},
+					},
+				},
+			},
+			data: `{
+				"type": "Feature",
+				"stac_version": "1.1.0",
+				"id": "item-id",
+				"geometry": {
+					"type": "Point",
+					"coordinates": [1.1, 2.2]
+				},
+				"properties": {
+					"test": "value"
+				},
+				"links": [
+					{
+						"rel": "self",
+						"href": "https://example.com/stac/item-id"
+					}
+				],
+				"assets": {
+					"image": {
+						"title": "Image",
+						"href": "https://example.com/stac/item-id/image.tif",
+						"type": "image/tif",
+						"proj:code": "EPSG:3857",
+						"proj:wkt2": "PROJCS[...]",
+						"proj:shape": [100, 50],
+						"proj:transform": [30, 0, 1000, 0, -30, 5000],
+						"bands": [
+							{
+								"name": "pan",
+								"proj:shape": [200, 100],
+								"proj:transform": [15, 0, 1000, 0, -15, 5000]
+							}
+						]
+					}
+				},
+				"stac_extensions": [
+					"https://stac-extensions.github.io/projection/v2.0.0/schema.json"
+				]
+			}`,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%d-%s", i, c.name), func(t *testing.T) {
+			if c.item != nil {
+				data, err := json.Marshal(c.item)
+				if c.err != "" {
+					assert.ErrorContains(t, err, c.err)
+					return
+				}
+				require.NoError(t, err)
+				assert.JSONEq(t, c.data, string(data))
+			}
+
+			if c.data != "" {
+				item := &stac.Item{}
+				err := json.Unmarshal([]byte(c.data), item)
+				if c.err != "" {
+					assert.ErrorContains(t, err, c.err)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, c.item, item)
+			}
+		})
+	}
+}
+
+func TestTransformHelpers(t *testing.T) {
+	transform := []float64{30, 0, 224985, 0, -30, 6790215, 0, 0, 1}
+
+	x, y, err := proj.PixelToNative(transform, 10, 20)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{225285, 6789615}, []float64{x, y})
+
+	col, row, err := proj.NativeToPixel(transform, x, y)
+	require.NoError(t, err)
+	assert.InDelta(t, 10, col, 1e-9)
+	assert.InDelta(t, 20, row, 1e-9)
+
+	bbox, err := proj.NativeBbox([]int{8391, 8311}, transform)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{224985, 6538485, 474315, 6790215}, bbox)
+
+	geometry, err := proj.NativeGeometry([]int{8391, 8311}, transform)
+	require.NoError(t, err)
+	assert.Equal(t, "Polygon", geometry["type"])
+
+	_, _, err = proj.PixelToNative([]float64{1, 2}, 0, 0)
+	assert.ErrorContains(t, err, "6 or 9 values")
+
+	_, _, err = proj.NativeToPixel([]float64{0, 0, 0, 0, 0, 0}, 0, 0)
+	assert.ErrorContains(t, err, "not invertible")
+}
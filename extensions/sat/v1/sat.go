@@ -0,0 +1,123 @@
+package sat
+
+import (
+	"regexp"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/internal/summaries"
+)
+
+const (
+	extensionUri     = "https://stac-extensions.github.io/sat/v1.0.0/schema.json"
+	extensionPattern = `https://stac-extensions.github.io/sat/v1\..*/schema.json`
+	prefix           = "sat"
+)
+
+func init() {
+	stac.RegisterItemExtension(
+		regexp.MustCompile(extensionPattern),
+		func() stac.Extension {
+			return &Item{}
+		},
+	)
+
+	stac.RegisterAssetExtension(
+		regexp.MustCompile(extensionPattern),
+		func() stac.Extension {
+			return &Asset{}
+		},
+	)
+
+	stac.RegisterCollectionExtension(
+		regexp.MustCompile(extensionPattern),
+		func() stac.Extension {
+			return &Collection{}
+		},
+	)
+}
+
+const (
+	OrbitStateAscending     = "ascending"
+	OrbitStateDescending    = "descending"
+	OrbitStateGeostationary = "geostationary"
+)
+
+type Item struct {
+	PlatformInternationalDesignator string `json:"sat:platform_international_designator,omitempty"`
+	OrbitState                      string `json:"sat:orbit_state,omitempty"`
+	AbsoluteOrbit                   *int   `json:"sat:absolute_orbit,omitempty"`
+	RelativeOrbit                   *int   `json:"sat:relative_orbit,omitempty"`
+	AnxDatetime                     string `json:"sat:anx_datetime,omitempty"`
+}
+
+type Asset struct {
+	PlatformInternationalDesignator string `json:"sat:platform_international_designator,omitempty"`
+	OrbitState                      string `json:"sat:orbit_state,omitempty"`
+	AbsoluteOrbit                   *int   `json:"sat:absolute_orbit,omitempty"`
+	RelativeOrbit                   *int   `json:"sat:relative_orbit,omitempty"`
+	AnxDatetime                     string `json:"sat:anx_datetime,omitempty"`
+}
+
+// Collection holds summaries of the satellite fields of a collection's items.
+// These are encoded in the collection summaries.  Summaries in a different form
+// (e.g. a list of orbits instead of a range) are only available in the
+// collection's Summaries map.
+type Collection struct {
+	PlatformInternationalDesignator []string       `json:"sat:platform_international_designator,omitempty"`
+	OrbitState                      []string       `json:"sat:orbit_state,omitempty"`
+	AbsoluteOrbit                   *OrbitRange    `json:"sat:absolute_orbit,omitempty"`
+	RelativeOrbit                   *OrbitRange    `json:"sat:relative_orbit,omitempty"`
+	AnxDatetime                     *DatetimeRange `json:"sat:anx_datetime,omitempty"`
+}
+
+type OrbitRange struct {
+	Minimum int `json:"minimum"`
+	Maximum int `json:"maximum"`
+}
+
+type DatetimeRange struct {
+	Minimum string `json:"minimum"`
+	Maximum string `json:"maximum"`
+}
+
+var (
+	_ stac.Extension = (*Item)(nil)
+	_ stac.Extension = (*Asset)(nil)
+	_ stac.Extension = (*Collection)(nil)
+)
+
+func (*Item) URI() string {
+	return extensionUri
+}
+
+func (e *Item) Encode(itemMap map[string]any) error {
+	return stac.EncodeExtendedItemProperties(e, itemMap)
+}
+
+func (e *Item) Decode(itemMap map[string]any) error {
+	return stac.DecodeExtendedItemProperties(e, itemMap)
+}
+
+func (*Asset) URI() string {
+	return extensionUri
+}
+
+func (e *Asset) Encode(assetMap map[string]any) error {
+	return stac.EncodeExtendedMap(e, assetMap)
+}
+
+func (e *Asset) Decode(assetMap map[string]any) error {
+	return stac.DecodeExtendedMap(e, assetMap, prefix)
+}
+
+func (*Collection) URI() string {
+	return extensionUri
+}
+
+func (e *Collection) Encode(collectionMap map[string]any) error {
+	return summaries.Encode(e, collectionMap)
+}
+
+func (e *Collection) Decode(collectionMap map[string]any) error {
+	return summaries.Decode(e, collectionMap, prefix)
+}
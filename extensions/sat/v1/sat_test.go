@@ -0,0 +1,286 @@
+package sat_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/extensions/sat/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemExtendedMarshal(t *testing.T) {
+	absoluteOrbit := 36075
+	relativeOrbit := 44
+	item := &stac.Item{
+		Version: "1.0.0",
+		Id:      "item-id",
+		Geometry: map[string]any{
+			"type":        "Point",
+			"coordinates": []float64{0, 0},
+		},
+		Properties: map[string]any{
+			"test": "value",
+		},
+		Links: []*stac.Link{
+			{Href: "https://example.com/stac/item-id", Rel: "self"},
+		},
+		Assets: map[string]*stac.Asset{
+			"image": {
+				Title: "Image",
+				Href:  "https://example.com/stac/item-id/image.tif",
+				Type:  "image/tif",
+				Extensions: []stac.Extension{
+					&sat.Asset{
+						OrbitState: sat.OrbitStateDescending,
+					},
+				},
+			},
+		},
+		Extensions: []stac.Extension{
+			&sat.Item{
+				PlatformInternationalDesignator: "2014-016A",
+				OrbitState:                      sat.OrbitStateAscending,
+				AbsoluteOrbit:                   &absoluteOrbit,
+				RelativeOrbit:                   &relativeOrbit,
+				AnxDatetime:                     "2021-01-01T00:00:00Z",
+			},
+		},
+	}
+
+	data, err := json.Marshal(item)
+	require.NoError(t, err)
+
+	expected := `{
+		"type": "Feature",
+		"stac_version": "1.0.0",
+		"id": "item-id",
+		"geometry": {
+			"type": "Point",
+			"coordinates": [0, 0]
+		},
+		"properties": {
+			"test": "value",
+			"sat:platform_international_designator": "2014-016A",
+			"sat:orbit_state": "ascending",
+			"sat:absolute_orbit": 36075,
+			"sat:relative_orbit": 44,
+			"sat:anx_datetime": "2021-01-01T00:00:00Z"
+		},
+		"links": [
+			{
+				"rel": "self",
+				"href": "https://example.com/stac/item-id"
+			}
+		],
+		"assets": {
+			"image": {
+				"title": "Image",
+				"href": "https://example.com/stac/item-id/image.tif",
+				"type": "image/tif",
+				"sat:orbit_state": "descending"
+			}
+		},
+		"stac_extensions": [
+			"https://stac-extensions.github.io/sat/v1.0.0/schema.json"
+		]
+	}`
+
+	assert.JSONEq(t, expected, string(data))
+}
+
+func TestItemExtendedUnmarshal(t *testing.T) {
+	data := []byte(`{
+		"type": "Feature",
+		"stac_version": "1.0.0",
+		"id": "item-id",
+		"geometry": {
+			"type": "Point",
+			"coordinates": [0, 0]
+		},
+		"properties": {
+			"test": "value",
+			"sat:orbit_state": "descending",
+			"sat:relative_orbit": 44
+		},
+		"links": [
+			{
+				"rel": "self",
+				"href": "https://example.com/stac/item-id"
+			}
+		],
+		"assets": {
+			"image": {
+				"title": "Image",
+				"href": "https://example.com/stac/item-id/image.tif",
+				"type": "image/tif"
+			}
+		},
+		"stac_extensions": [
+			"https://stac-extensions.github.io/sat/v1.0.0/schema.json"
+		]
+	}`)
+
+	item := &stac.Item{}
+	require.NoError(t, json.Unmarshal(data, item))
+
+	relativeOrbit := 44
+	expected := &stac.Item{
+		Version: "1.0.0",
+		Id:      "item-id",
+		Geometry: map[string]any{
+			"type":        "Point",
+			"coordinates": []any{float64(0), float64(0)},
+		},
+		Properties: map[string]any{
+			"test": "value",
+		},
+		Links: []*stac.Link{
+			{Href: "https://example.com/stac/item-id", Rel: "self"},
+		},
+		Assets: map[string]*stac.Asset{
+			"image": {
+				Title: "Image",
+				Href:  "https://example.com/stac/item-id/image.tif",
+				Type:  "image/tif",
+			},
+		},
+		Extensions: []stac.Extension{
+			&sat.Item{
+				OrbitState:    sat.OrbitStateDescending,
+				RelativeOrbit: &relativeOrbit,
+			},
+		},
+	}
+
+	assert.Equal(t, expected, item)
+}
+
+func TestCollectionSummaries(t *testing.T) {
+	collection := &stac.Collection{
+		Version:     "1.0.0",
+		Id:          "collection-id",
+		Description: "Test Collection",
+		License:     "various",
+		Extent: &stac.Extent{
+			Spatial: &stac.SpatialExtent{
+				Bbox: [][]float64{{-180, -90, 180, 90}},
+			},
+		},
+		Summaries: map[string]any{
+			"platform": []any{"sentinel-1a"},
+		},
+		Links: []*stac.Link{},
+		Extensions: []stac.Extension{
+			&sat.Collection{
+				OrbitState:    []string{sat.OrbitStateAscending, sat.OrbitStateDescending},
+				RelativeOrbit: &sat.OrbitRange{Minimum: 1, Maximum: 175},
+				AnxDatetime:   &sat.DatetimeRange{Minimum: "2021-01-01T00:00:00Z", Maximum: "2021-12-31T00:00:00Z"},
+			},
+		},
+	}
+
+	data, err := json.Marshal(collection)
+	require.NoError(t, err)
+
+	expected := `{
+		"type": "Collection",
+		"stac_version": "1.0.0",
+		"id": "collection-id",
+		"description": "Test Collection",
+		"license": "various",
+		"extent": {
+			"spatial": {
+				"bbox": [[-180, -90, 180, 90]]
+			}
+		},
+		"summaries": {
+			"platform": ["sentinel-1a"],
+			"sat:orbit_state": ["ascending", "descending"],
+			"sat:relative_orbit": {"minimum": 1, "maximum": 175},
+			"sat:anx_datetime": {"minimum": "2021-01-01T00:00:00Z", "maximum": "2021-12-31T00:00:00Z"}
+		},
+		"links": [],
+		"stac_extensions": [
+			"https://stac-extensions.github.io/sat/v1.0.0/schema.json"
+		]
+	}`
+	assert.JSONEq(t, expected, string(data))
+	assert.Equal(t, map[string]any{"platform": []any{"sentinel-1a"}}, collection.Summaries)
+
+	decoded := &stac.Collection{}
+	require.NoError(t, json.Unmarshal(data, decoded))
+	require.Len(t, decoded.Extensions, 1)
+	assert.Equal(t, collection.Extensions[0], decoded.Extensions[0])
+
+	// encoding the decoded collection gives the same result
+	roundTrip, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.JSONEq(t, expected, string(roundTrip))
+}
+
+func TestCollectionSummariesOtherForms(t *testing.T) {
+	data := []byte(`{
+		"type": "Collection",
+		"stac_version": "1.0.0",
+		"id": "collection-id",
+		"description": "Test Collection",
+		"license": "various",
+		"extent": {
+			"spatial": {
+				"bbox": [[-180, -90, 180, 90]]
+			}
+		},
+		"summaries": {
+			"sat:absolute_orbit": [12345, 12346],
+			"sat:orbit_state": {"type": "string", "enum": ["ascending", "descending"]},
+			"sat:relative_orbit": {"minimum": 1, "maximum": 175}
+		},
+		"links": [],
+		"stac_extensions": [
+			"https://stac-extensions.github.io/sat/v1.0.0/schema.json"
+		]
+	}`)
+
+	collection := &stac.Collection{}
+	require.NoError(t, json.Unmarshal(data, collection))
+	require.Len(t, collection.Extensions, 1)
+	assert.Equal(t, &sat.Collection{
+		RelativeOrbit: &sat.OrbitRange{Minimum: 1, Maximum: 175},
+	}, collection.Extensions[0])
+
+	assert.Equal(t, []any{float64(12345), float64(12346)}, collection.Summaries["sat:absolute_orbit"])
+	assert.Equal(t, map[string]any{"type": "string", "enum": []any{"ascending", "descending"}}, collection.Summaries["sat:orbit_state"])
+
+	roundTrip, err := json.Marshal(collection)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(roundTrip))
+}
+
+func TestCollectionSummariesNoMatchingForms(t *testing.T) {
+	data := []byte(`{
+		"type": "Collection",
+		"stac_version": "1.0.0",
+		"id": "collection-id",
+		"description": "Test Collection",
+		"license": "various",
+		"extent": {
+			"spatial": {
+				"bbox": [[-180, -90, 180, 90]]
+			}
+		},
+		"summaries": {
+			"sat:relative_orbit": [1, 2, 3]
+		},
+		"links": [],
+		"stac_extensions": [
+			"https://stac-extensions.github.io/sat/v1.0.0/schema.json"
+		]
+	}`)
+
+	collection := &stac.Collection{}
+	require.NoError(t, json.Unmarshal(data, collection))
+	assert.Empty(t, collection.Extensions)
+	assert.Equal(t, []any{float64(1), float64(2), float64(3)}, collection.Summaries["sat:relative_orbit"])
+}
@@ -0,0 +1,260 @@
+package scientific
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/planetlabs/go-stac"
+)
+
+const (
+	extensionUri     = "https://stac-extensions.github.io/scientific/v1.0.0/schema.json"
+	extensionPattern = `https://stac-extensions.github.io/scientific/v1\..*/schema.json`
+	prefix           = "sci"
+)
+
+// RelCiteAs is the relation type for a link to the preferred resource to cite.
+const RelCiteAs = "cite-as"
+
+func init() {
+	r := regexp.MustCompile(extensionPattern)
+
+	stac.RegisterItemExtension(r, func() stac.Extension { return &Item{} })
+	stac.RegisterCollectionExtension(r, func() stac.Extension { return &Collection{} })
+}
+
+type Item struct {
+	DOI          string         `json:"sci:doi,omitempty"`
+	Citation     string         `json:"sci:citation,omitempty"`
+	Publications []*Publication `json:"sci:publications,omitempty"`
+}
+
+type Collection struct {
+	DOI          string         `json:"sci:doi,omitempty"`
+	Citation     string         `json:"sci:citation,omitempty"`
+	Publications []*Publication `json:"sci:publications,omitempty"`
+}
+
+type Publication struct {
+	DOI      string `json:"doi,omitempty"`
+	Citation string `json:"citation,omitempty"`
+}
+
+var (
+	_ stac.Extension = (*Item)(nil)
+	_ stac.Extension = (*Collection)(nil)
+)
+
+func (*Item) URI() string {
+	return extensionUri
+}
+
+func (e *Item) Encode(itemMap map[string]any) error {
+	return stac.EncodeExtendedItemProperties(e, itemMap)
+}
+
+func (e *Item) Decode(itemMap map[string]any) error {
+	return stac.DecodeExtendedItemProperties(e, itemMap)
+}
+
+func (*Collection) URI() string {
+	return extensionUri
+}
+
+func (e *Collection) Encode(collectionMap map[string]any) error {
+	return stac.EncodeExtendedMap(e, collectionMap)
+}
+
+func (e *Collection) Decode(collectionMap map[string]any) error {
+	return stac.DecodeExtendedMap(e, collectionMap, prefix)
+}
+
+const doiResolver = "https://doi.org/"
+
+// DOIURL returns the URL that resolves a DOI.
+func DOIURL(doi string) string {
+	return doiResolver + doi
+}
+
+// CiteAsLink returns a cite-as link for a DOI.
+func CiteAsLink(doi string) *stac.Link {
+	return &stac.Link{Rel: RelCiteAs, Href: DOIURL(doi)}
+}
+
+// Citation holds the information used to cite a collection.
+type Citation struct {
+	Key       string
+	Title     string
+	Authors   []string
+	Publisher string
+	DOI       string
+	URL       string
+	Keywords  []string
+	Note      string
+}
+
+// NewCitation creates a citation from the fields of a collection.  The authors
+// are the providers with the producer role (or the licensor role if there are
+// no producers) and the publisher is the first provider with the host role.
+// The note is the sci:citation value.  The URL is the href of the cite-as link,
+// the DOI URL, or the href of the self link.
+func NewCitation(collection *stac.Collection) *Citation {
+	c := &Citation{
+		Key:      collection.Id,
+		Title:    collection.Title,
+		Keywords: collection.Keywords,
+	}
+	if c.Title == "" {
+		c.Title = collection.Id
+	}
+
+	for _, extension := range collection.Extensions {
+		if e, ok := extension.(*Collection); ok {
+			c.DOI = e.DOI
+			c.Note = e.Citation
+			break
+		}
+	}
+
+	selfHref := ""
+	for _, link := range collection.Links {
+		switch link.Rel {
+		case RelCiteAs:
+			if c.URL == "" {
+				c.URL = link.Href
+			}
+		case "self":
+			selfHref = link.Href
+		}
+	}
+	if c.DOI == "" && strings.HasPrefix(c.URL, doiResolver) {
+		c.DOI = strings.TrimPrefix(c.URL, doiResolver)
+	}
+	if c.URL == "" && c.DOI != "" {
+		c.URL = DOIURL(c.DOI)
+	}
+	if c.URL == "" {
+		c.URL = selfHref
+	}
+
+	c.Authors = providerNames(collection.Providers, "producer")
+	if len(c.Authors) == 0 {
+		c.Authors = providerNames(collection.Providers, "licensor")
+	}
+	if hosts := providerNames(collection.Providers, "host"); len(hosts) > 0 {
+		c.Publisher = hosts[0]
+	}
+	return c
+}
+
+func providerNames(providers []*stac.Provider, role string) []string {
+	names := []string{}
+	for _, provider := range providers {
+		for _, r := range provider.Roles {
+			if r == role {
+				names = append(names, provider.Name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+var bibtexKeyPattern = regexp.MustCompile(`[^A-Za-z0-9_:.-]+`)
+
+var bibtexReplacer = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`$`, `\$`,
+	`#`, `\#`,
+	`_`, `\_`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+)
+
+// BibTeX renders the citation as a BibTeX @misc entry.
+func (c *Citation) BibTeX() string {
+	key := bibtexKeyPattern.ReplaceAllString(c.Key, "_")
+	if key == "" {
+		key = "dataset"
+	}
+
+	authors := make([]string, len(c.Authors))
+	for i, author := range c.Authors {
+		// braces keep organization names from being parsed as personal names
+		authors[i] = "{" + bibtexReplacer.Replace(author) + "}"
+	}
+
+	fields := [][2]string{
+		{"title", wrap(bibtexReplacer.Replace(c.Title))},
+		{"author", strings.Join(authors, " and ")},
+		{"publisher", bibtexReplacer.Replace(c.Publisher)},
+		{"doi", c.DOI},
+		{"url", c.URL},
+		{"keywords", bibtexReplacer.Replace(strings.Join(c.Keywords, ", "))},
+		{"note", bibtexReplacer.Replace(c.Note)},
+	}
+
+	builder := &strings.Builder{}
+	fmt.Fprintf(builder, "@misc{%s", key)
+	for _, field := range fields {
+		if field[1] == "" {
+			continue
+		}
+		fmt.Fprintf(builder, ",\n  %s = {%s}", field[0], field[1])
+	}
+	builder.WriteString("\n}\n")
+	return builder.String()
+}
+
+// wrap adds braces to preserve the capitalization of a non-empty title.
+func wrap(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "{" + value + "}"
+}
+
+// CSL returns the citation as a CSL-JSON item of type dataset.
+func (c *Citation) CSL() map[string]any {
+	item := map[string]any{
+		"id":   c.Key,
+		"type": "dataset",
+	}
+	if c.Title != "" {
+		item["title"] = c.Title
+	}
+	if len(c.Authors) > 0 {
+		authors := make([]map[string]any, len(c.Authors))
+		for i, author := range c.Authors {
+			authors[i] = map[string]any{"literal": author}
+		}
+		item["author"] = authors
+	}
+	if c.Publisher != "" {
+		item["publisher"] = c.Publisher
+	}
+	if c.DOI != "" {
+		item["DOI"] = c.DOI
+	}
+	if c.URL != "" {
+		item["URL"] = c.URL
+	}
+	if len(c.Keywords) > 0 {
+		item["keyword"] = strings.Join(c.Keywords, ", ")
+	}
+	if c.Note != "" {
+		item["note"] = c.Note
+	}
+	return item
+}
+
+// CSLJSON renders the citation as a CSL-JSON array with a single item.
+func (c *Citation) CSLJSON() ([]byte, error) {
+	return json.MarshalIndent([]map[string]any{c.CSL()}, "", "  ")
+}
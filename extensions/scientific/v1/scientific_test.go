@@ -0,0 +1,195 @@
+package scientific_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/extensions/scientific/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemExtendedMarshal(t *testing.T) {
+	item := &stac.Item{
+		Version: "1.0.0",
+		Id:      "item-id",
+		Geometry: map[string]any{
+			"type":        "Point",
+			"coordinates": []float64{0, 0},
+		},
+		Properties: map[string]any{
+			"datetime": "2024-01-01T00:00:00Z",
+		},
+		Links: []*stac.Link{
+			scientific.CiteAsLink("10.5061/dryad.s2v81"),
+		},
+		Extensions: []stac.Extension{
+			&scientific.Item{
+				DOI:      "10.5061/dryad.s2v81",
+				Citation: "Doe, J. (2024) Example data.",
+				Publications: []*scientific.Publication{
+					{DOI: "10.1234/example", Citation: "Doe, J. (2024) Example paper."},
+				},
+			},
+		},
+	}
+
+	data, err := json.Marshal(item)
+	require.NoError(t, err)
+
+	expected := `{
+		"type": "Feature",
+		"stac_version": "1.0.0",
+		"id": "item-id",
+		"geometry": {
+			"type": "Point",
+			"coordinates": [0, 0]
+		},
+		"properties": {
+			"datetime": "2024-01-01T00:00:00Z",
+			"sci:doi": "10.5061/dryad.s2v81",
+			"sci:citation": "Doe, J. (2024) Example data.",
+			"sci:publications": [
+				{"doi": "10.1234/example", "citation": "Doe, J. (2024) Example paper."}
+			]
+		},
+		"links": [
+			{
+				"href": "https://doi.org/10.5061/dryad.s2v81",
+				"rel": "cite-as"
+			}
+		],
+		"assets": {},
+		"stac_extensions": [
+			"https://stac-extensions.github.io/scientific/v1.0.0/schema.json"
+		]
+	}`
+
+	assert.JSONEq(t, expected, string(data))
+
+	decoded := &stac.Item{}
+	require.NoError(t, json.Unmarshal(data, decoded))
+	require.Len(t, decoded.Extensions, 1)
+	assert.Equal(t, item.Extensions[0], decoded.Extensions[0])
+	assert.Equal(t, map[string]any{"datetime": "2024-01-01T00:00:00Z"}, decoded.Properties)
+}
+
+const collectionData = `{
+	"type": "Collection",
+	"stac_version": "1.0.0",
+	"id": "land_cover",
+	"title": "Land Cover 100% {Global}",
+	"description": "Global land cover",
+	"keywords": ["land cover", "global"],
+	"license": "CC-BY-4.0",
+	"providers": [
+		{"name": "Example Lab & Co", "roles": ["producer", "licensor"]},
+		{"name": "Data University", "roles": ["producer"]},
+		{"name": "Example Host", "roles": ["host"]}
+	],
+	"extent": {
+		"spatial": {"bbox": [[-180, -90, 180, 90]]},
+		"temporal": {"interval": [["2024-01-01T00:00:00Z", null]]}
+	},
+	"sci:doi": "10.5061/dryad.s2v81",
+	"sci:citation": "Example Lab (2024) Land cover, version 1.",
+	"links": [
+		{"href": "https://example.com/stac/land_cover.json", "rel": "self"}
+	],
+	"stac_extensions": [
+		"https://stac-extensions.github.io/scientific/v1.0.0/schema.json"
+	]
+}`
+
+func TestCollectionExtendedUnmarshal(t *testing.T) {
+	collection := &stac.Collection{}
+	require.NoError(t, json.Unmarshal([]byte(collectionData), collection))
+
+	require.Len(t, collection.Extensions, 1)
+	assert.Equal(t, &scientific.Collection{
+		DOI:      "10.5061/dryad.s2v81",
+		Citation: "Example Lab (2024) Land cover, version 1.",
+	}, collection.Extensions[0])
+
+	encoded, err := json.Marshal(collection)
+	require.NoError(t, err)
+	assert.JSONEq(t, collectionData, string(encoded))
+}
+
+func TestCitation(t *testing.T) {
+	collection := &stac.Collection{}
+	require.NoError(t, json.Unmarshal([]byte(collectionData), collection))
+
+	citation := scientific.NewCitation(collection)
+	assert.Equal(t, &scientific.Citation{
+		Key:       "land_cover",
+		Title:     "Land Cover 100% {Global}",
+		Authors:   []string{"Example Lab & Co", "Data University"},
+		Publisher: "Example Host",
+		DOI:       "10.5061/dryad.s2v81",
+		URL:       "https://doi.org/10.5061/dryad.s2v81",
+		Keywords:  []string{"land cover", "global"},
+		Note:      "Example Lab (2024) Land cover, version 1.",
+	}, citation)
+
+	expected := `@misc{land_cover,
+  title = {{Land Cover 100\% \{Global\}}},
+  author = {{Example Lab \& Co} and {Data University}},
+  publisher = {Example Host},
+  doi = {10.5061/dryad.s2v81},
+  url = {https://doi.org/10.5061/dryad.s2v81},
+  keywords = {land cover, global},
+  note = {Example Lab (2024) Land cover, version 1.}
+}
+`
+	assert.Equal(t, expected, citation.BibTeX())
+
+	data, err := citation.CSLJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{
+			"id": "land_cover",
+			"type": "dataset",
+			"title": "Land Cover 100% {Global}",
+			"author": [
+				{"literal": "Example Lab & Co"},
+				{"literal": "Data University"}
+			],
+			"publisher": "Example Host",
+			"DOI": "10.5061/dryad.s2v81",
+			"URL": "https://doi.org/10.5061/dryad.s2v81",
+			"keyword": "land cover, global",
+			"note": "Example Lab (2024) Land cover, version 1."
+		}
+	]`, string(data))
+}
+
+func TestCitationFromLinks(t *testing.T) {
+	collection := &stac.Collection{
+		Id: "my collection",
+		Links: []*stac.Link{
+			{Rel: "self", Href: "https://example.com/collection.json"},
+			scientific.CiteAsLink("10.1234/abc"),
+		},
+		Providers: []*stac.Provider{
+			{Name: "Licensor", Roles: []string{"licensor"}},
+		},
+	}
+
+	citation := scientific.NewCitation(collection)
+	assert.Equal(t, "10.1234/abc", citation.DOI)
+	assert.Equal(t, "https://doi.org/10.1234/abc", citation.URL)
+	assert.Equal(t, []string{"Licensor"}, citation.Authors)
+
+	assert.Equal(t, `@misc{my_collection,
+  title = {{my collection}},
+  author = {{Licensor}},
+  doi = {10.1234/abc},
+  url = {https://doi.org/10.1234/abc}
+}
+`, citation.BibTeX())
+
+	collection.Links = collection.Links[:1]
+	assert.Equal(t, "https://example.com/collection.json", scientific.NewCitation(collection).URL)
+}
@@ -0,0 +1,235 @@
+package timestamps
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/crawler"
+)
+
+const (
+	extensionUri     = "https://stac-extensions.github.io/timestamps/v1.1.0/schema.json"
+	extensionPattern = `https://stac-extensions.github.io/timestamps/v1\..*/schema.json`
+	publishedKey     = "published"
+	expiresKey       = "expires"
+	unpublishedKey   = "unpublished"
+)
+
+func init() {
+	r := regexp.MustCompile(extensionPattern)
+
+	stac.RegisterItemExtension(r, func() stac.Extension { return &Item{} })
+	stac.RegisterCollectionExtension(r, func() stac.Extension { return &Collection{} })
+	stac.RegisterCatalogExtension(r, func() stac.Extension { return &Catalog{} })
+	stac.RegisterAssetExtension(r, func() stac.Extension { return &Asset{} })
+	stac.RegisterLinkExtension(r, func() stac.Extension { return &Link{} })
+}
+
+type Item struct {
+	Published   string `json:"published,omitempty"`
+	Expires     string `json:"expires,omitempty"`
+	Unpublished string `json:"unpublished,omitempty"`
+}
+
+type Collection struct {
+	Published   string `json:"published,omitempty"`
+	Expires     string `json:"expires,omitempty"`
+	Unpublished string `json:"unpublished,omitempty"`
+}
+
+type Catalog struct {
+	Published   string `json:"published,omitempty"`
+	Expires     string `json:"expires,omitempty"`
+	Unpublished string `json:"unpublished,omitempty"`
+}
+
+type Asset struct {
+	Published   string `json:"published,omitempty"`
+	Expires     string `json:"expires,omitempty"`
+	Unpublished string `json:"unpublished,omitempty"`
+}
+
+type Link struct {
+	Published   string `json:"published,omitempty"`
+	Expires     string `json:"expires,omitempty"`
+	Unpublished string `json:"unpublished,omitempty"`
+}
+
+var (
+	_ stac.Extension = (*Item)(nil)
+	_ stac.Extension = (*Collection)(nil)
+	_ stac.Extension = (*Catalog)(nil)
+	_ stac.Extension = (*Asset)(nil)
+	_ stac.Extension = (*Link)(nil)
+)
+
+func (*Item) URI() string {
+	return extensionUri
+}
+
+func (e *Item) Encode(itemMap map[string]any) error {
+	return stac.EncodeExtendedItemProperties(e, itemMap)
+}
+
+func (e *Item) Decode(itemMap map[string]any) error {
+	return stac.DecodeExtendedItemProperties(e, itemMap)
+}
+
+func (*Collection) URI() string {
+	return extensionUri
+}
+
+func (e *Collection) Encode(collectionMap map[string]any) error {
+	return stac.EncodeExtendedMap(e, collectionMap)
+}
+
+func (e *Collection) Decode(collectionMap map[string]any) error {
+	return decodeMap(e, collectionMap)
+}
+
+func (*Catalog) URI() string {
+	return extensionUri
+}
+
+func (e *Catalog) Encode(catalogMap map[string]any) error {
+	return stac.EncodeExtendedMap(e, catalogMap)
+}
+
+func (e *Catalog) Decode(catalogMap map[string]any) error {
+	return decodeMap(e, catalogMap)
+}
+
+func (*Asset) URI() string {
+	return extensionUri
+}
+
+func (e *Asset) Encode(assetMap map[string]any) error {
+	return stac.EncodeExtendedMap(e, assetMap)
+}
+
+func (e *Asset) Decode(assetMap map[string]any) error {
+	return decodeMap(e, assetMap)
+}
+
+func (*Link) URI() string {
+	return extensionUri
+}
+
+func (e *Link) Encode(linkMap map[string]any) error {
+	return stac.EncodeExtendedMap(e, linkMap)
+}
+
+func (e *Link) Decode(linkMap map[string]any) error {
+	return decodeMap(e, linkMap)
+}
+
+// decodeMap decodes the timestamp fields from a map.  The fields have no
+// prefix, so the extension only applies if one of them is present.
+func decodeMap(e stac.Extension, data map[string]any) error {
+	_, published := data[publishedKey]
+	_, expires := data[expiresKey]
+	_, unpublished := data[unpublishedKey]
+	if !published && !expires && !unpublished {
+		return stac.ErrExtensionDoesNotApply
+	}
+	return stac.DecodeExtendedMap(e, data, "")
+}
+
+// Expired returns true if the resource has an expires timestamp that is
+// before the provided time.
+func Expired(resource crawler.Resource, now time.Time) (bool, error) {
+	return before(resource, expiresKey, now)
+}
+
+// Unpublished returns true if the resource has an unpublished timestamp that
+// is not after the provided time.
+func Unpublished(resource crawler.Resource, now time.Time) (bool, error) {
+	return before(resource, unpublishedKey, now)
+}
+
+// before returns true if the timestamp with the provided key is before or
+// equal to the provided time.  Item timestamps are read from the properties
+// and catalog or collection timestamps are read from the top level.
+func before(resource crawler.Resource, key string, now time.Time) (bool, error) {
+	data := map[string]any(resource)
+	if resource.Type() == crawler.Item {
+		properties, ok := resource["properties"].(map[string]any)
+		if !ok {
+			return false, nil
+		}
+		data = properties
+	}
+
+	value, ok := data[key]
+	if !ok || value == nil {
+		return false, nil
+	}
+	str, ok := value.(string)
+	if !ok {
+		return false, fmt.Errorf("expected %s to be a string, got %T", key, value)
+	}
+	timestamp, err := time.Parse(time.RFC3339, str)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", key, err)
+	}
+	return !timestamp.After(now), nil
+}
+
+// Current returns true if the resource has not expired or been unpublished
+// at the provided time.
+func Current(resource crawler.Resource, now time.Time) (bool, error) {
+	expired, err := Expired(resource, now)
+	if err != nil || expired {
+		return false, err
+	}
+	unpublished, err := Unpublished(resource, now)
+	if err != nil || unpublished {
+		return false, err
+	}
+	return true, nil
+}
+
+// Filter is a crawler item filter that excludes expired and unpublished
+// items.  Resources with expires or unpublished values that cannot be parsed
+// are treated as not current (instead of stopping the crawl).
+type Filter struct {
+	// Now is the time used to check the timestamps (default time.Now()).
+	Now time.Time
+}
+
+var _ crawler.ItemFilter = (*Filter)(nil)
+
+func (f *Filter) now() time.Time {
+	if f.Now.IsZero() {
+		return time.Now()
+	}
+	return f.Now
+}
+
+func (f *Filter) current(resource crawler.Resource) bool {
+	current, err := Current(resource, f.now())
+	return err == nil && current
+}
+
+// Match returns true if the item has not expired or been unpublished.  An
+// item with an invalid timestamp does not match.
+func (f *Filter) Match(resource crawler.Resource) (bool, error) {
+	return f.current(resource), nil
+}
+
+// Visitor wraps a crawler visitor so that expired and unpublished resources
+// are not visited.  The children of expired or unpublished catalogs and
+// collections are not crawled.
+func (f *Filter) Visitor(visitor crawler.Visitor) crawler.Visitor {
+	return func(resource crawler.Resource, info *crawler.ResourceInfo) error {
+		if f.current(resource) {
+			return visitor(resource, info)
+		}
+		if resource.Type() == crawler.Item {
+			return nil
+		}
+		return crawler.ErrStopRecursion
+	}
+}
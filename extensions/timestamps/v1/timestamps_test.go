@@ -0,0 +1,269 @@
+package timestamps_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/crawler"
+	"github.com/planetlabs/go-stac/extensions/timestamps/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemExtendedMarshal(t *testing.T) {
+	item := &stac.Item{
+		Version: "1.0.0",
+		Id:      "item-id",
+		Geometry: map[string]any{
+			"type":        "Point",
+			"coordinates": []float64{0, 0},
+		},
+		Properties: map[string]any{
+			"datetime": "2024-01-01T00:00:00Z",
+		},
+		Links: []*stac.Link{
+			{
+				Href: "https://example.com/stac/item-id",
+				Rel:  "self",
+				Extensions: []stac.Extension{
+					&timestamps.Link{Published: "2024-01-02T00:00:00Z"},
+				},
+			},
+		},
+		Assets: map[string]*stac.Asset{
+			"image": {
+				Href: "https://example.com/stac/item-id/image.tif",
+				Extensions: []stac.Extension{
+					&timestamps.Asset{Expires: "2025-01-01T00:00:00Z"},
+				},
+			},
+		},
+		Extensions: []stac.Extension{
+			&timestamps.Item{
+				Published: "2024-01-02T00:00:00Z",
+				Expires:   "2025-01-01T00:00:00Z",
+			},
+		},
+	}
+
+	data, err := json.Marshal(item)
+	require.NoError(t, err)
+
+	expected := `{
+		"type": "Feature",
+		"stac_version": "1.0.0",
+		"id": "item-id",
+		"geometry": {
+			"type": "Point",
+			"coordinates": [0, 0]
+		},
+		"properties": {
+			"datetime": "2024-01-01T00:00:00Z",
+			"published": "2024-01-02T00:00:00Z",
+			"expires": "2025-01-01T00:00:00Z"
+		},
+		"links": [
+			{
+				"href": "https://example.com/stac/item-id",
+				"rel": "self",
+				"published": "2024-01-02T00:00:00Z"
+			}
+		],
+		"assets": {
+			"image": {
+				"href": "https://example.com/stac/item-id/image.tif",
+				"expires": "2025-01-01T00:00:00Z"
+			}
+		},
+		"stac_extensions": [
+			"https://stac-extensions.github.io/timestamps/v1.1.0/schema.json"
+		]
+	}`
+
+	assert.JSONEq(t, expected, string(data))
+}
+
+func TestItemExtendedUnmarshal(t *testing.T) {
+	data := []byte(`{
+		"type": "Feature",
+		"stac_version": "1.0.0",
+		"id": "item-id",
+		"geometry": null,
+		"properties": {
+			"datetime": "2024-01-01T00:00:00Z",
+			"unpublished": "2024-06-01T00:00:00Z"
+		},
+		"links": [
+			{
+				"href": "https://example.com/stac/item-id",
+				"rel": "self"
+			}
+		],
+		"assets": {
+			"image": {
+				"href": "https://example.com/stac/item-id/image.tif",
+				"published": "2024-01-02T00:00:00Z"
+			}
+		},
+		"stac_extensions": [
+			"https://stac-extensions.github.io/timestamps/v1.0.0/schema.json"
+		]
+	}`)
+
+	item := &stac.Item{}
+	require.NoError(t, json.Unmarshal(data, item))
+
+	require.Len(t, item.Extensions, 1)
+	assert.Equal(t, &timestamps.Item{Unpublished: "2024-06-01T00:00:00Z"}, item.Extensions[0])
+	assert.Equal(t, map[string]any{"datetime": "2024-01-01T00:00:00Z"}, item.Properties)
+
+	assert.Empty(t, item.Links[0].Extensions)
+	require.Len(t, item.Assets["image"].Extensions, 1)
+	assert.Equal(t, &timestamps.Asset{Published: "2024-01-02T00:00:00Z"}, item.Assets["image"].Extensions[0])
+}
+
+func TestCollectionExtendedUnmarshal(t *testing.T) {
+	data := []byte(`{
+		"type": "Collection",
+		"stac_version": "1.0.0",
+		"id": "collection-id",
+		"description": "Test collection",
+		"license": "CC-BY-4.0",
+		"extent": {
+			"spatial": {"bbox": [[-180, -90, 180, 90]]},
+			"temporal": {"interval": [["2024-01-01T00:00:00Z", null]]}
+		},
+		"published": "2024-01-02T00:00:00Z",
+		"links": [],
+		"stac_extensions": [
+			"https://stac-extensions.github.io/timestamps/v1.1.0/schema.json"
+		]
+	}`)
+
+	collection := &stac.Collection{}
+	require.NoError(t, json.Unmarshal(data, collection))
+
+	require.Len(t, collection.Extensions, 1)
+	assert.Equal(t, &timestamps.Collection{Published: "2024-01-02T00:00:00Z"}, collection.Extensions[0])
+
+	encoded, err := json.Marshal(collection)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(encoded))
+}
+
+func TestCurrent(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		resource crawler.Resource
+		current  bool
+		err      string
+	}{
+		{
+			name: "item without timestamps",
+			resource: crawler.Resource{
+				"type":       "Feature",
+				"properties": map[string]any{},
+			},
+			current: true,
+		},
+		{
+			name: "item expires later",
+			resource: crawler.Resource{
+				"type":       "Feature",
+				"properties": map[string]any{"expires": "2024-07-01T00:00:00Z"},
+			},
+			current: true,
+		},
+		{
+			name: "expired item",
+			resource: crawler.Resource{
+				"type":       "Feature",
+				"properties": map[string]any{"expires": "2024-05-01T00:00:00Z"},
+			},
+			current: false,
+		},
+		{
+			name: "unpublished item",
+			resource: crawler.Resource{
+				"type":       "Feature",
+				"properties": map[string]any{"unpublished": "2024-06-01T00:00:00Z"},
+			},
+			current: false,
+		},
+		{
+			name: "expired collection",
+			resource: crawler.Resource{
+				"type":    "Collection",
+				"expires": "2024-05-01T00:00:00+02:00",
+			},
+			current: false,
+		},
+		{
+			name: "invalid timestamp",
+			resource: crawler.Resource{
+				"type":    "Catalog",
+				"expires": "yesterday",
+			},
+			err: "failed to parse expires",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			current, err := timestamps.Current(c.resource, now)
+			if c.err != "" {
+				assert.ErrorContains(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.current, current)
+		})
+	}
+}
+
+func TestFilterVisitor(t *testing.T) {
+	filter := &timestamps.Filter{Now: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}
+
+	visited := []string{}
+	visitor := filter.Visitor(func(resource crawler.Resource, info *crawler.ResourceInfo) error {
+		visited = append(visited, resource["id"].(string))
+		return nil
+	})
+
+	expired := "2024-01-01T00:00:00Z"
+	info := &crawler.ResourceInfo{}
+
+	assert.NoError(t, visitor(crawler.Resource{"type": "Catalog", "id": "catalog"}, info))
+	assert.ErrorIs(t, visitor(crawler.Resource{"type": "Collection", "id": "old", "expires": expired}, info), crawler.ErrStopRecursion)
+	assert.NoError(t, visitor(crawler.Resource{"type": "Feature", "id": "item", "properties": map[string]any{}}, info))
+	assert.NoError(t, visitor(crawler.Resource{"type": "Feature", "id": "old-item", "properties": map[string]any{"expires": expired}}, info))
+
+	assert.Equal(t, []string{"catalog", "item"}, visited)
+
+	match, err := filter.Match(crawler.Resource{"type": "Feature", "id": "old-item", "properties": map[string]any{"expires": expired}})
+	require.NoError(t, err)
+	assert.False(t, match)
+}
+
+func TestFilterInvalidTimestamp(t *testing.T) {
+	filter := &timestamps.Filter{Now: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}
+
+	visited := []string{}
+	visitor := filter.Visitor(func(resource crawler.Resource, info *crawler.ResourceInfo) error {
+		visited = append(visited, resource["id"].(string))
+		return nil
+	})
+
+	info := &crawler.ResourceInfo{}
+	assert.ErrorIs(t, visitor(crawler.Resource{"type": "Catalog", "id": "bad", "unpublished": "someday"}, info), crawler.ErrStopRecursion)
+	assert.NoError(t, visitor(crawler.Resource{"type": "Feature", "id": "bad-item", "properties": map[string]any{"expires": "yesterday"}}, info))
+	assert.Empty(t, visited)
+
+	match, err := filter.Match(crawler.Resource{"type": "Feature", "id": "bad-item", "properties": map[string]any{"expires": "yesterday"}})
+	require.NoError(t, err)
+	assert.False(t, match)
+}
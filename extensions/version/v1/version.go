@@ -0,0 +1,388 @@
+package version
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/crawler"
+)
+
+const (
+	extensionUri     = "https://stac-extensions.github.io/version/v1.2.0/schema.json"
+	extensionPattern = `https://stac-extensions.github.io/version/v1\..*/schema.json`
+	versionKey       = "version"
+	deprecatedKey    = "deprecated"
+	experimentalKey  = "experimental"
+)
+
+// Link relation types for versioned resources.
+const (
+	RelLatestVersion      = "latest-version"
+	RelPredecessorVersion = "predecessor-version"
+	RelSuccessorVersion   = "successor-version"
+)
+
+func init() {
+	r := regexp.MustCompile(extensionPattern)
+
+	stac.RegisterItemExtension(r, func() stac.Extension { return &Item{} })
+	stac.RegisterCollectionExtension(r, func() stac.Extension { return &Collection{} })
+	stac.RegisterCatalogExtension(r, func() stac.Extension { return &Catalog{} })
+	stac.RegisterAssetExtension(r, func() stac.Extension { return &Asset{} })
+	stac.RegisterLinkExtension(r, func() stac.Extension { return &Link{} })
+}
+
+type Item struct {
+	Version      string `json:"version,omitempty"`
+	Deprecated   bool   `json:"deprecated,omitempty"`
+	Experimental bool   `json:"experimental,omitempty"`
+}
+
+type Collection struct {
+	Version      string `json:"version,omitempty"`
+	Deprecated   bool   `json:"deprecated,omitempty"`
+	Experimental bool   `json:"experimental,omitempty"`
+}
+
+type Catalog struct {
+	Version      string `json:"version,omitempty"`
+	Deprecated   bool   `json:"deprecated,omitempty"`
+	Experimental bool   `json:"experimental,omitempty"`
+}
+
+type Asset struct {
+	Version      string `json:"version,omitempty"`
+	Deprecated   bool   `json:"deprecated,omitempty"`
+	Experimental bool   `json:"experimental,omitempty"`
+}
+
+type Link struct {
+	Version      string `json:"version,omitempty"`
+	Deprecated   bool   `json:"deprecated,omitempty"`
+	Experimental bool   `json:"experimental,omitempty"`
+}
+
+var (
+	_ stac.Extension = (*Item)(nil)
+	_ stac.Extension = (*Collection)(nil)
+	_ stac.Extension = (*Catalog)(nil)
+	_ stac.Extension = (*Asset)(nil)
+	_ stac.Extension = (*Link)(nil)
+)
+
+func (*Item) URI() string {
+	return extensionUri
+}
+
+func (e *Item) Encode(itemMap map[string]any) error {
+	return stac.EncodeExtendedItemProperties(e, itemMap)
+}
+
+func (e *Item) Decode(itemMap map[string]any) error {
+	return stac.DecodeExtendedItemProperties(e, itemMap)
+}
+
+func (*Collection) URI() string {
+	return extensionUri
+}
+
+func (e *Collection) Encode(collectionMap map[string]any) error {
+	return stac.EncodeExtendedMap(e, collectionMap)
+}
+
+func (e *Collection) Decode(collectionMap map[string]any) error {
+	return decodeMap(e, collectionMap)
+}
+
+func (*Catalog) URI() string {
+	return extensionUri
+}
+
+func (e *Catalog) Encode(catalogMap map[string]any) error {
+	return stac.EncodeExtendedMap(e, catalogMap)
+}
+
+func (e *Catalog) Decode(catalogMap map[string]any) error {
+	return decodeMap(e, catalogMap)
+}
+
+func (*Asset) URI() string {
+	return extensionUri
+}
+
+func (e *Asset) Encode(assetMap map[string]any) error {
+	return stac.EncodeExtendedMap(e, assetMap)
+}
+
+func (e *Asset) Decode(assetMap map[string]any) error {
+	return decodeMap(e, assetMap)
+}
+
+func (*Link) URI() string {
+	return extensionUri
+}
+
+func (e *Link) Encode(linkMap map[string]any) error {
+	return stac.EncodeExtendedMap(e, linkMap)
+}
+
+func (e *Link) Decode(linkMap map[string]any) error {
+	return decodeMap(e, linkMap)
+}
+
+// decodeMap decodes the version fields from a map.  The fields have no prefix,
+// so the extension only applies if one of them is present.
+func decodeMap(e stac.Extension, data map[string]any) error {
+	_, version := data[versionKey]
+	_, deprecated := data[deprecatedKey]
+	_, experimental := data[experimentalKey]
+	if !version && !deprecated && !experimental {
+		return stac.ErrExtensionDoesNotApply
+	}
+	return stac.DecodeExtendedMap(e, data, "")
+}
+
+// Options for creating a new version of a resource.
+type Options struct {
+	// Id for the new version (default is the id of the existing resource).
+	Id string
+
+	// PredecessorHref is the location of the existing resource (default is the
+	// href of its self link).
+	PredecessorHref string
+
+	// Deprecate marks the existing resource as deprecated.
+	Deprecate bool
+}
+
+type config struct {
+	id              string
+	version         string
+	href            string
+	predecessorHref string
+	deprecate       bool
+}
+
+func newConfig(version string, href string, links []*stac.Link, options []*Options) (*config, error) {
+	if version == "" {
+		return nil, errors.New("missing version for the new resource")
+	}
+	if href == "" {
+		return nil, errors.New("missing href for the new resource")
+	}
+	c := &config{version: version, href: href}
+	for _, link := range links {
+		if link.Rel == "self" {
+			c.predecessorHref = link.Href
+		}
+	}
+	for _, opt := range options {
+		if opt.Id != "" {
+			c.id = opt.Id
+		}
+		if opt.PredecessorHref != "" {
+			c.predecessorHref = opt.PredecessorHref
+		}
+		if opt.Deprecate {
+			c.deprecate = true
+		}
+	}
+	if c.predecessorHref == "" {
+		return nil, errors.New("missing href for the existing resource, provide a self link or a predecessor href")
+	}
+	return c, nil
+}
+
+// NextItem creates a new version of an item.  The item is cloned and the clone
+// is given the provided version and a predecessor-version link to the
+// existing item.  The existing item is given a successor-version link to the
+// clone, which will be located at the provided href.  Both items are given a
+// latest-version link to the clone.
+func NextItem(item *stac.Item, version string, href string, options ...*Options) (*stac.Item, error) {
+	c, err := newConfig(version, href, item.Links, options)
+	if err != nil {
+		return nil, err
+	}
+
+	next := &stac.Item{}
+	if err := clone(item, next); err != nil {
+		return nil, fmt.Errorf("failed to clone item %s: %w", item.Id, err)
+	}
+	if c.id != "" {
+		next.Id = c.id
+	}
+
+	linkType := "application/geo+json"
+	next.Links = c.nextLinks(next.Links, linkType)
+	item.Links = c.predecessorLinks(item.Links, linkType)
+
+	nextExtension := itemExtension(next)
+	nextExtension.Version = c.version
+	nextExtension.Deprecated = false
+	if c.deprecate {
+		itemExtension(item).Deprecated = true
+	}
+	return next, nil
+}
+
+// NextCollection creates a new version of a collection.  The collection is
+// cloned and the clone is given the provided version and a predecessor-version
+// link to the existing collection.  The existing collection is given a
+// successor-version link to the clone, which will be located at the provided
+// href.  Both collections are given a latest-version link to the clone.
+func NextCollection(collection *stac.Collection, version string, href string, options ...*Options) (*stac.Collection, error) {
+	c, err := newConfig(version, href, collection.Links, options)
+	if err != nil {
+		return nil, err
+	}
+
+	next := &stac.Collection{}
+	if err := clone(collection, next); err != nil {
+		return nil, fmt.Errorf("failed to clone collection %s: %w", collection.Id, err)
+	}
+	if c.id != "" {
+		next.Id = c.id
+	}
+
+	linkType := "application/json"
+	next.Links = c.nextLinks(next.Links, linkType)
+	collection.Links = c.predecessorLinks(collection.Links, linkType)
+
+	nextExtension := collectionExtension(next)
+	nextExtension.Version = c.version
+	nextExtension.Deprecated = false
+	if c.deprecate {
+		collectionExtension(collection).Deprecated = true
+	}
+	return next, nil
+}
+
+// clone copies a resource by encoding it as JSON and decoding the result.
+func clone(resource any, target any) error {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+// nextLinks updates the links of the new version.  The self link (if any) is
+// updated and links to other versions are replaced.
+func (c *config) nextLinks(links []*stac.Link, linkType string) []*stac.Link {
+	updated := []*stac.Link{}
+	for _, link := range links {
+		switch link.Rel {
+		case RelPredecessorVersion, RelSuccessorVersion, RelLatestVersion:
+			continue
+		case "self":
+			link.Href = c.href
+		}
+		updated = append(updated, link)
+	}
+	return append(updated,
+		&stac.Link{Rel: RelPredecessorVersion, Href: c.predecessorHref, Type: linkType},
+		&stac.Link{Rel: RelLatestVersion, Href: c.href, Type: linkType},
+	)
+}
+
+// predecessorLinks updates the links of the existing version to point to the
+// new version.
+func (c *config) predecessorLinks(links []*stac.Link, linkType string) []*stac.Link {
+	updated := []*stac.Link{}
+	for _, link := range links {
+		if link.Rel == RelSuccessorVersion || link.Rel == RelLatestVersion {
+			continue
+		}
+		updated = append(updated, link)
+	}
+	return append(updated,
+		&stac.Link{Rel: RelSuccessorVersion, Href: c.href, Type: linkType},
+		&stac.Link{Rel: RelLatestVersion, Href: c.href, Type: linkType},
+	)
+}
+
+// itemExtension returns the version extension of an item, adding one if
+// needed.
+func itemExtension(item *stac.Item) *Item {
+	for _, extension := range item.Extensions {
+		if e, ok := extension.(*Item); ok {
+			return e
+		}
+	}
+	e := &Item{}
+	item.Extensions = append(item.Extensions, e)
+	return e
+}
+
+// collectionExtension returns the version extension of a collection, adding
+// one if needed.
+func collectionExtension(collection *stac.Collection) *Collection {
+	for _, extension := range collection.Extensions {
+		if e, ok := extension.(*Collection); ok {
+			return e
+		}
+	}
+	e := &Collection{}
+	collection.Extensions = append(collection.Extensions, e)
+	return e
+}
+
+// Deprecated returns true if the resource is marked as deprecated.  Item
+// fields are read from the properties and catalog or collection fields are
+// read from the top level.
+func Deprecated(resource crawler.Resource) bool {
+	return flag(resource, deprecatedKey)
+}
+
+// Experimental returns true if the resource is marked as experimental.
+func Experimental(resource crawler.Resource) bool {
+	return flag(resource, experimentalKey)
+}
+
+func flag(resource crawler.Resource, key string) bool {
+	data := map[string]any(resource)
+	if resource.Type() == crawler.Item {
+		properties, ok := resource["properties"].(map[string]any)
+		if !ok {
+			return false
+		}
+		data = properties
+	}
+	value, _ := data[key].(bool)
+	return value
+}
+
+// Filter is a crawler item filter that excludes deprecated items.
+type Filter struct {
+	// Experimental also excludes experimental resources.
+	Experimental bool
+}
+
+var _ crawler.ItemFilter = (*Filter)(nil)
+
+func (f *Filter) skip(resource crawler.Resource) bool {
+	return Deprecated(resource) || (f.Experimental && Experimental(resource))
+}
+
+// Match returns true if the item is not deprecated.
+func (f *Filter) Match(resource crawler.Resource) (bool, error) {
+	return !f.skip(resource), nil
+}
+
+// Visitor wraps a crawler visitor so that deprecated resources are not
+// visited.  The children of deprecated catalogs and collections are not
+// crawled.
+func (f *Filter) Visitor(visitor crawler.Visitor) crawler.Visitor {
+	return func(resource crawler.Resource, info *crawler.ResourceInfo) error {
+		if !f.skip(resource) {
+			return visitor(resource, info)
+		}
+		if resource.Type() == crawler.Item {
+			return nil
+		}
+		return crawler.ErrStopRecursion
+	}
+}
@@ -0,0 +1,201 @@
+package version_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/crawler"
+	"github.com/planetlabs/go-stac/extensions/version/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemExtendedUnmarshal(t *testing.T) {
+	data := []byte(`{
+		"type": "Feature",
+		"stac_version": "1.0.0",
+		"id": "item-id",
+		"geometry": null,
+		"properties": {
+			"datetime": "2024-01-01T00:00:00Z",
+			"version": "1",
+			"deprecated": true
+		},
+		"links": [
+			{
+				"href": "https://example.com/stac/item-id",
+				"rel": "self"
+			},
+			{
+				"href": "https://example.com/stac/item-id-v2",
+				"rel": "latest-version",
+				"version": "2"
+			}
+		],
+		"assets": {},
+		"stac_extensions": [
+			"https://stac-extensions.github.io/version/v1.2.0/schema.json"
+		]
+	}`)
+
+	item := &stac.Item{}
+	require.NoError(t, json.Unmarshal(data, item))
+
+	require.Len(t, item.Extensions, 1)
+	assert.Equal(t, &version.Item{Version: "1", Deprecated: true}, item.Extensions[0])
+	assert.Equal(t, map[string]any{"datetime": "2024-01-01T00:00:00Z"}, item.Properties)
+
+	assert.Empty(t, item.Links[0].Extensions)
+	require.Len(t, item.Links[1].Extensions, 1)
+	assert.Equal(t, &version.Link{Version: "2"}, item.Links[1].Extensions[0])
+
+	encoded, err := json.Marshal(item)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(encoded))
+}
+
+func TestCollectionExtendedUnmarshal(t *testing.T) {
+	data := []byte(`{
+		"type": "Collection",
+		"stac_version": "1.0.0",
+		"id": "collection-id",
+		"description": "Test collection",
+		"license": "CC-BY-4.0",
+		"extent": {
+			"spatial": {"bbox": [[-180, -90, 180, 90]]},
+			"temporal": {"interval": [["2024-01-01T00:00:00Z", null]]}
+		},
+		"version": "1.0.0",
+		"experimental": true,
+		"links": [],
+		"stac_extensions": [
+			"https://stac-extensions.github.io/version/v1.2.0/schema.json"
+		]
+	}`)
+
+	collection := &stac.Collection{}
+	require.NoError(t, json.Unmarshal(data, collection))
+
+	require.Len(t, collection.Extensions, 1)
+	assert.Equal(t, &version.Collection{Version: "1.0.0", Experimental: true}, collection.Extensions[0])
+
+	encoded, err := json.Marshal(collection)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(encoded))
+}
+
+func TestNextItem(t *testing.T) {
+	item := &stac.Item{
+		Version:    "1.0.0",
+		Id:         "item-v1",
+		Properties: map[string]any{"datetime": "2024-01-01T00:00:00Z"},
+		Links: []*stac.Link{
+			{Href: "https://example.com/stac/item-v1.json", Rel: "self", Type: "application/geo+json"},
+			{Href: "https://example.com/stac/collection.json", Rel: "parent"},
+		},
+		Assets: map[string]*stac.Asset{
+			"image": {Href: "https://example.com/image-v1.tif"},
+		},
+		Extensions: []stac.Extension{
+			&version.Item{Version: "1"},
+		},
+	}
+
+	next, err := version.NextItem(item, "2", "https://example.com/stac/item-v2.json", &version.Options{
+		Id:        "item-v2",
+		Deprecate: true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "item-v2", next.Id)
+	assert.Equal(t, []stac.Extension{&version.Item{Version: "2"}}, next.Extensions)
+	assert.Equal(t, []*stac.Link{
+		{Href: "https://example.com/stac/item-v2.json", Rel: "self", Type: "application/geo+json"},
+		{Href: "https://example.com/stac/collection.json", Rel: "parent"},
+		{Href: "https://example.com/stac/item-v1.json", Rel: version.RelPredecessorVersion, Type: "application/geo+json"},
+		{Href: "https://example.com/stac/item-v2.json", Rel: version.RelLatestVersion, Type: "application/geo+json"},
+	}, next.Links)
+
+	assert.Equal(t, "item-v1", item.Id)
+	assert.Equal(t, []stac.Extension{&version.Item{Version: "1", Deprecated: true}}, item.Extensions)
+	assert.Equal(t, []*stac.Link{
+		{Href: "https://example.com/stac/item-v1.json", Rel: "self", Type: "application/geo+json"},
+		{Href: "https://example.com/stac/collection.json", Rel: "parent"},
+		{Href: "https://example.com/stac/item-v2.json", Rel: version.RelSuccessorVersion, Type: "application/geo+json"},
+		{Href: "https://example.com/stac/item-v2.json", Rel: version.RelLatestVersion, Type: "application/geo+json"},
+	}, item.Links)
+
+	next.Assets["image"].Href = "https://example.com/image-v2.tif"
+	assert.Equal(t, "https://example.com/image-v1.tif", item.Assets["image"].Href)
+}
+
+func TestNextItemMissingHref(t *testing.T) {
+	item := &stac.Item{Version: "1.0.0", Id: "item-id"}
+
+	_, err := version.NextItem(item, "2", "")
+	assert.ErrorContains(t, err, "missing href for the new resource")
+
+	_, err = version.NextItem(item, "2", "item-v2.json")
+	assert.ErrorContains(t, err, "missing href for the existing resource")
+
+	next, err := version.NextItem(item, "2", "item-v2.json", &version.Options{PredecessorHref: "item-v1.json"})
+	require.NoError(t, err)
+	assert.Equal(t, "item-v1.json", next.Links[0].Href)
+	assert.Empty(t, item.Extensions)
+}
+
+func TestNextCollection(t *testing.T) {
+	collection := &stac.Collection{
+		Version:     "1.0.0",
+		Id:          "collection",
+		Description: "Test collection",
+		License:     "CC-BY-4.0",
+		Links: []*stac.Link{
+			{Href: "https://example.com/stac/v1/collection.json", Rel: "self"},
+			{Href: "https://example.com/stac/v1/collection.json", Rel: version.RelLatestVersion},
+		},
+	}
+
+	next, err := version.NextCollection(collection, "2.0.0", "https://example.com/stac/v2/collection.json")
+	require.NoError(t, err)
+
+	assert.Equal(t, "collection", next.Id)
+	assert.Equal(t, []stac.Extension{&version.Collection{Version: "2.0.0"}}, next.Extensions)
+	assert.Equal(t, []*stac.Link{
+		{Href: "https://example.com/stac/v2/collection.json", Rel: "self"},
+		{Href: "https://example.com/stac/v1/collection.json", Rel: version.RelPredecessorVersion, Type: "application/json"},
+		{Href: "https://example.com/stac/v2/collection.json", Rel: version.RelLatestVersion, Type: "application/json"},
+	}, next.Links)
+
+	assert.Empty(t, collection.Extensions)
+	assert.Equal(t, []*stac.Link{
+		{Href: "https://example.com/stac/v1/collection.json", Rel: "self"},
+		{Href: "https://example.com/stac/v2/collection.json", Rel: version.RelSuccessorVersion, Type: "application/json"},
+		{Href: "https://example.com/stac/v2/collection.json", Rel: version.RelLatestVersion, Type: "application/json"},
+	}, collection.Links)
+}
+
+func TestFilterVisitor(t *testing.T) {
+	filter := &version.Filter{Experimental: true}
+
+	visited := []string{}
+	visitor := filter.Visitor(func(resource crawler.Resource, info *crawler.ResourceInfo) error {
+		visited = append(visited, resource["id"].(string))
+		return nil
+	})
+
+	info := &crawler.ResourceInfo{}
+
+	assert.NoError(t, visitor(crawler.Resource{"type": "Catalog", "id": "catalog"}, info))
+	assert.ErrorIs(t, visitor(crawler.Resource{"type": "Collection", "id": "old", "deprecated": true}, info), crawler.ErrStopRecursion)
+	assert.ErrorIs(t, visitor(crawler.Resource{"type": "Collection", "id": "new", "experimental": true}, info), crawler.ErrStopRecursion)
+	assert.NoError(t, visitor(crawler.Resource{"type": "Feature", "id": "item", "properties": map[string]any{"deprecated": false}}, info))
+	assert.NoError(t, visitor(crawler.Resource{"type": "Feature", "id": "old-item", "properties": map[string]any{"deprecated": true}}, info))
+
+	assert.Equal(t, []string{"catalog", "item"}, visited)
+
+	match, err := (&version.Filter{}).Match(crawler.Resource{"type": "Feature", "properties": map[string]any{"experimental": true}})
+	require.NoError(t, err)
+	assert.True(t, match)
+}
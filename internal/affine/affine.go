@@ -0,0 +1,95 @@
+// Package affine converts between pixel and native coordinates with the affine
+// transforms used by the projection extension (proj:transform).
+package affine
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Transform holds the first two rows of an affine transform [a, b, c, d, e, f]
+// where x = a*col + b*row + c and y = d*col + e*row + f.
+type Transform [6]float64
+
+// New creates a transform from the 6 or 9 values of a proj:transform.
+func New(values []float64) (Transform, error) {
+	t := Transform{}
+	switch len(values) {
+	case 6:
+	case 9:
+		if values[6] != 0 || values[7] != 0 || values[8] != 1 {
+			return t, fmt.Errorf("expected the last row of the transform to be [0, 0, 1], got %v", values[6:])
+		}
+	default:
+		return t, fmt.Errorf("expected a transform with 6 or 9 values, got %d", len(values))
+	}
+	copy(t[:], values[:6])
+	return t, nil
+}
+
+// Apply returns the native coordinates for pixel coordinates.
+func (t Transform) Apply(col float64, row float64) (float64, float64) {
+	return t[0]*col + t[1]*row + t[2], t[3]*col + t[4]*row + t[5]
+}
+
+// Invert returns the transform from native to pixel coordinates.
+func (t Transform) Invert() (Transform, error) {
+	det := t[0]*t[4] - t[1]*t[3]
+	if det == 0 || math.IsNaN(det) {
+		return Transform{}, errors.New("transform is not invertible")
+	}
+	a := t[4] / det
+	b := -t[1] / det
+	d := -t[3] / det
+	e := t[0] / det
+	return Transform{a, b, -a*t[2] - b*t[5], d, e, -d*t[2] - e*t[5]}, nil
+}
+
+// Corners returns the native coordinates of the corners of a raster with the
+// provided shape ([rows, columns] as in proj:shape).  The corners are ordered
+// counter-clockwise for a north-up transform, starting at the upper left.
+func Corners(shape []int, t Transform) ([][]float64, error) {
+	if len(shape) != 2 {
+		return nil, fmt.Errorf("expected a shape with 2 values, got %d", len(shape))
+	}
+	rows, cols := float64(shape[0]), float64(shape[1])
+	pixels := [][2]float64{{0, 0}, {0, rows}, {cols, rows}, {cols, 0}}
+	corners := make([][]float64, len(pixels))
+	for i, pixel := range pixels {
+		x, y := t.Apply(pixel[0], pixel[1])
+		corners[i] = []float64{x, y}
+	}
+	return corners, nil
+}
+
+// Bounds returns the native bounding box [minx, miny, maxx, maxy] of a raster
+// with the provided shape.
+func Bounds(shape []int, t Transform) ([]float64, error) {
+	corners, err := Corners(shape, t)
+	if err != nil {
+		return nil, err
+	}
+	bbox := []float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for _, corner := range corners {
+		bbox[0] = math.Min(bbox[0], corner[0])
+		bbox[1] = math.Min(bbox[1], corner[1])
+		bbox[2] = math.Max(bbox[2], corner[0])
+		bbox[3] = math.Max(bbox[3], corner[1])
+	}
+	return bbox, nil
+}
+
+// Footprint returns a GeoJSON polygon with the native coordinates of the
+// corners of a raster with the provided shape.
+func Footprint(shape []int, t Transform) (map[string]any, error) {
+	corners, err := Corners(shape, t)
+	if err != nil {
+		return nil, err
+	}
+	ring := []any{}
+	for _, corner := range append(corners, corners[0]) {
+		ring = append(ring, []any{corner[0], corner[1]})
+	}
+	return map[string]any{"type": "Polygon", "coordinates": []any{ring}}, nil
+}
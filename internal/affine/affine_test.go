@@ -0,0 +1,67 @@
+package affine_test
+
+import (
+	"testing"
+
+	"github.com/planetlabs/go-stac/internal/affine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	transform, err := affine.New([]float64{30, 0, 224985, 0, -30, 6790215, 0, 0, 1})
+	require.NoError(t, err)
+	assert.Equal(t, affine.Transform{30, 0, 224985, 0, -30, 6790215}, transform)
+
+	_, err = affine.New([]float64{30, 0, 224985, 0, -30, 6790215, 1, 0, 1})
+	assert.ErrorContains(t, err, "last row")
+
+	_, err = affine.New([]float64{30, 0, 224985})
+	assert.ErrorContains(t, err, "6 or 9 values")
+}
+
+func TestApplyInvert(t *testing.T) {
+	transforms := []affine.Transform{
+		{30, 0, 224985, 0, -30, 6790215},
+		{10, 2, 1000, -3, -10, 5000},
+	}
+	for _, transform := range transforms {
+		x, y := transform.Apply(100, 200)
+		inverse, err := transform.Invert()
+		require.NoError(t, err)
+		col, row := inverse.Apply(x, y)
+		assert.InDelta(t, 100, col, 1e-9)
+		assert.InDelta(t, 200, row, 1e-9)
+	}
+
+	x, y := transforms[0].Apply(1, 2)
+	assert.Equal(t, []float64{225015, 6790155}, []float64{x, y})
+
+	_, err := affine.Transform{1, 2, 0, 2, 4, 0}.Invert()
+	assert.ErrorContains(t, err, "not invertible")
+}
+
+func TestBounds(t *testing.T) {
+	transform := affine.Transform{30, 0, 224985, 0, -30, 6790215}
+	bbox, err := affine.Bounds([]int{8391, 8311}, transform)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{224985, 6538485, 474315, 6790215}, bbox)
+
+	_, err = affine.Bounds([]int{1}, transform)
+	assert.ErrorContains(t, err, "2 values")
+}
+
+func TestFootprint(t *testing.T) {
+	footprint, err := affine.Footprint([]int{2, 3}, affine.Transform{10, 0, 100, 0, -10, 200})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"type": "Polygon",
+		"coordinates": []any{[]any{
+			[]any{100.0, 200.0},
+			[]any{100.0, 180.0},
+			[]any{130.0, 180.0},
+			[]any{130.0, 200.0},
+			[]any{100.0, 200.0},
+		}},
+	}, footprint)
+}
@@ -0,0 +1,171 @@
+// Package fetch loads JSON over HTTP with retries for transient failures.
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/tschaub/retry"
+)
+
+// DefaultClient retries requests that fail with connection errors or server errors.
+var DefaultClient = newDefaultClient()
+
+func newDefaultClient() *http.Client {
+	client := retryablehttp.NewClient()
+	client.Logger = nil
+	return client.StandardClient()
+}
+
+const retries = 5
+
+// Request describes an HTTP request.
+type Request struct {
+	Method   string
+	Location string
+	Header   http.Header
+	Body     []byte
+}
+
+// StatusError is returned when a response has an unexpected status code.
+type StatusError struct {
+	Location   string
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected response for %s: %d", e.Location, e.StatusCode)
+}
+
+// JSON sends a request and decodes the JSON response body into the provided value.
+//
+// Requests are made again if the connection is reset while reading the response
+// body.  Any response other than 200 OK results in a *StatusError.
+func JSON(ctx context.Context, client *http.Client, request *Request, value any) error {
+	_, err := send(ctx, client, request, value, true)
+	return err
+}
+
+// Do sends a request and decodes any JSON response body into the provided value
+// (which may be nil).  Unlike JSON, any 2xx response is successful and an empty
+// response body is not an error.  Do makes a single attempt and leaves any
+// retries to the provided client, so requests that are not idempotent should
+// be sent with a client that does not retry (unlike DefaultClient).  The status
+// code of the response is returned.
+func Do(ctx context.Context, client *http.Client, request *Request, value any) (int, error) {
+	return trySend(ctx, client, request, value, false)
+}
+
+func send(ctx context.Context, client *http.Client, request *Request, value any, strict bool) (int, error) {
+	status := 0
+	err := retry.Limit(ctx, retries, func(ctx context.Context, attempt int) error {
+		var err error
+		status, err = trySend(ctx, client, request, value, strict)
+		if err == nil {
+			return nil
+		}
+
+		// these come when parsing the response body
+		if !errors.Is(err, syscall.ECONNRESET) {
+			return retry.Stop(err)
+		}
+
+		if waitErr := wait(ctx, attempt); waitErr != nil {
+			return retry.Stop(waitErr)
+		}
+		return err
+	})
+	return status, err
+}
+
+// wait pauses before another attempt with exponential backoff and up to a second
+// of jitter.  The context error is returned if the context is done first.
+func wait(ctx context.Context, attempt int) error {
+	jitter := time.Duration(rand.Float64() * float64(time.Second))
+	timer := time.NewTimer(time.Second*time.Duration(math.Pow(2, float64(attempt))) + jitter)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func trySend(ctx context.Context, client *http.Client, request *Request, value any, strict bool) (int, error) {
+	method := request.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	var body io.Reader
+	if request.Body != nil {
+		body = bytes.NewReader(request.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, request.Location, body)
+	if err != nil {
+		return 0, err
+	}
+	for key, values := range request.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	if request.Body != nil && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	success := resp.StatusCode == http.StatusOK
+	if !strict {
+		success = resp.StatusCode >= 200 && resp.StatusCode < 300
+	}
+	if !success {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		return resp.StatusCode, &StatusError{
+			Location:   request.Location,
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       data,
+		}
+	}
+
+	if strict {
+		jsonErr := json.NewDecoder(resp.Body).Decode(value)
+		if jsonErr != nil {
+			return resp.StatusCode, fmt.Errorf("failed to parse %s: %w", request.Location, jsonErr)
+		}
+		return resp.StatusCode, nil
+	}
+
+	data, readErr := io.ReadAll(resp.Body)
+	if readErr != nil {
+		return resp.StatusCode, fmt.Errorf("failed to read %s: %w", request.Location, readErr)
+	}
+	if value == nil || len(bytes.TrimSpace(data)) == 0 {
+		return resp.StatusCode, nil
+	}
+	if jsonErr := json.Unmarshal(data, value); jsonErr != nil {
+		return resp.StatusCode, fmt.Errorf("failed to parse %s: %w", request.Location, jsonErr)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,160 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/tschaub/retry"
+)
+
+// PartialSuffix is appended to the path of a file while it is being downloaded.
+const PartialSuffix = ".part"
+
+// File downloads a resource to a file.
+//
+// The response body is written to a partial file next to the destination and
+// renamed when the download is complete.  If a partial file exists from an
+// earlier attempt, the download is resumed with a range request.  Interrupted
+// downloads are retried (resuming where they left off).  Any response other than
+// 200 OK or 206 Partial Content results in a *StatusError.
+func File(ctx context.Context, client *http.Client, request *Request, path string) error {
+	partPath := path + PartialSuffix
+	err := retry.Limit(ctx, retries, func(ctx context.Context, attempt int) error {
+		err := tryFile(ctx, client, request, partPath)
+		if err == nil {
+			return nil
+		}
+
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) || ctx.Err() != nil {
+			return retry.Stop(err)
+		}
+
+		if waitErr := wait(ctx, attempt); waitErr != nil {
+			return retry.Stop(waitErr)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.Rename(partPath, path); err != nil {
+		return fmt.Errorf("failed to rename %s: %w", partPath, err)
+	}
+	return nil
+}
+
+func tryFile(ctx context.Context, client *http.Client, request *Request, partPath string) error {
+	offset := int64(0)
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, request.Location, nil)
+	if err != nil {
+		return retry.Stop(err)
+	}
+	for key, values := range request.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	flag := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusPartialContent:
+		start, _, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			// start over on the next attempt
+			_ = os.Remove(partPath)
+			return fmt.Errorf("unexpected content range for %s: %q", request.Location, resp.Header.Get("Content-Range"))
+		}
+		flag = os.O_WRONLY | os.O_APPEND
+	case http.StatusRequestedRangeNotSatisfiable:
+		_, size, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if ok && size == offset {
+			// the partial file is already complete
+			return nil
+		}
+		_ = os.Remove(partPath)
+		return fmt.Errorf("unable to resume download of %s", request.Location)
+	default:
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		return &StatusError{
+			Location:   request.Location,
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       data,
+		}
+	}
+
+	file, err := os.OpenFile(partPath, flag, 0644)
+	if err != nil {
+		return retry.Stop(fmt.Errorf("failed to open %s: %w", partPath, err))
+	}
+
+	_, copyErr := io.Copy(file, resp.Body)
+	closeErr := file.Close()
+	if copyErr != nil {
+		return fmt.Errorf("failed to download %s: %w", request.Location, copyErr)
+	}
+	if closeErr != nil {
+		return retry.Stop(fmt.Errorf("failed to write %s: %w", partPath, closeErr))
+	}
+	return nil
+}
+
+// parseContentRange parses a header like "bytes 100-199/200" or "bytes */200"
+// and returns the start offset and the total size (-1 if unknown).
+func parseContentRange(value string) (int64, int64, bool) {
+	value, ok := strings.CutPrefix(value, "bytes ")
+	if !ok {
+		return 0, 0, false
+	}
+	rangePart, sizePart, ok := strings.Cut(value, "/")
+	if !ok {
+		return 0, 0, false
+	}
+
+	size := int64(-1)
+	if sizePart != "*" {
+		parsed, err := strconv.ParseInt(sizePart, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		size = parsed
+	}
+
+	if rangePart == "*" {
+		return 0, size, true
+	}
+	startPart, _, ok := strings.Cut(rangePart, "-")
+	if !ok {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(startPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, size, true
+}
@@ -0,0 +1,113 @@
+package fetch_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/planetlabs/go-stac/internal/fetch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const content = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+func newFileServer(t *testing.T, ranges *[]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		*ranges = append(*ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "data.txt", time.Time{}, strings.NewReader(content))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFile(t *testing.T) {
+	ranges := []string{}
+	server := newFileServer(t, &ranges)
+	path := filepath.Join(t.TempDir(), "data.txt")
+
+	err := fetch.File(context.Background(), http.DefaultClient, &fetch.Request{Location: server.URL + "/data.txt"}, path)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+	assert.NoFileExists(t, path+fetch.PartialSuffix)
+	assert.Equal(t, []string{""}, ranges)
+}
+
+func TestFileResume(t *testing.T) {
+	ranges := []string{}
+	server := newFileServer(t, &ranges)
+	path := filepath.Join(t.TempDir(), "data.txt")
+	require.NoError(t, os.WriteFile(path+fetch.PartialSuffix, []byte(content[:10]), 0644))
+
+	err := fetch.File(context.Background(), http.DefaultClient, &fetch.Request{Location: server.URL + "/data.txt"}, path)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+	assert.Equal(t, []string{"bytes=10-"}, ranges)
+}
+
+func TestFileResumeComplete(t *testing.T) {
+	ranges := []string{}
+	server := newFileServer(t, &ranges)
+	path := filepath.Join(t.TempDir(), "data.txt")
+	require.NoError(t, os.WriteFile(path+fetch.PartialSuffix, []byte(content), 0644))
+
+	err := fetch.File(context.Background(), http.DefaultClient, &fetch.Request{Location: server.URL + "/data.txt"}, path)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+}
+
+func TestFileNotFound(t *testing.T) {
+	ranges := []string{}
+	server := newFileServer(t, &ranges)
+	path := filepath.Join(t.TempDir(), "missing.txt")
+
+	err := fetch.File(context.Background(), http.DefaultClient, &fetch.Request{Location: server.URL + "/missing.txt"}, path)
+	statusErr := &fetch.StatusError{}
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+	assert.NoFileExists(t, path)
+}
+
+// newTruncatingServer responds with less of the body than the content length
+// header promises.
+func newTruncatingServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		_, _ = w.Write([]byte(content[:10]))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFileCanceledWhileWaiting(t *testing.T) {
+	server := newTruncatingServer(t)
+	path := filepath.Join(t.TempDir(), "data.txt")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := fetch.File(ctx, http.DefaultClient, &fetch.Request{Location: server.URL + "/data.txt"}, path)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.NoFileExists(t, path)
+}
@@ -0,0 +1,72 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"syscall"
+
+	"github.com/tschaub/retry"
+)
+
+// Read sends a request and calls the provided function with the response body.
+//
+// If the connection is reset while reading the response body, the request is
+// made again and the function is called with the new response body (so it must
+// not retain state from an earlier call).  Any response other than 200 OK
+// results in a *StatusError.
+func Read(ctx context.Context, client *http.Client, request *Request, read func(io.Reader) error) error {
+	return retry.Limit(ctx, retries, func(ctx context.Context, attempt int) error {
+		err := tryRead(ctx, client, request, read)
+		if err == nil {
+			return nil
+		}
+
+		if !errors.Is(err, syscall.ECONNRESET) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return retry.Stop(err)
+		}
+
+		if waitErr := wait(ctx, attempt); waitErr != nil {
+			return retry.Stop(waitErr)
+		}
+		return err
+	})
+}
+
+func tryRead(ctx context.Context, client *http.Client, request *Request, read func(io.Reader) error) error {
+	method := request.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequestWithContext(ctx, method, request.Location, nil)
+	if err != nil {
+		return err
+	}
+	for key, values := range request.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		return &StatusError{
+			Location:   request.Location,
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       data,
+		}
+	}
+
+	return read(resp.Body)
+}
@@ -0,0 +1,57 @@
+package fetch_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/planetlabs/go-stac/internal/fetch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead(t *testing.T) {
+	ranges := []string{}
+	server := newFileServer(t, &ranges)
+
+	data := []byte{}
+	err := fetch.Read(context.Background(), http.DefaultClient, &fetch.Request{Location: server.URL + "/data.txt"}, func(r io.Reader) error {
+		var err error
+		data, err = io.ReadAll(r)
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+}
+
+func TestReadNotFound(t *testing.T) {
+	ranges := []string{}
+	server := newFileServer(t, &ranges)
+
+	called := false
+	err := fetch.Read(context.Background(), http.DefaultClient, &fetch.Request{Location: server.URL + "/missing.txt"}, func(r io.Reader) error {
+		called = true
+		return nil
+	})
+	statusErr := &fetch.StatusError{}
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+	assert.False(t, called)
+}
+
+func TestReadCanceledWhileWaiting(t *testing.T) {
+	server := newTruncatingServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := fetch.Read(ctx, http.DefaultClient, &fetch.Request{Location: server.URL + "/data.txt"}, func(r io.Reader) error {
+		_, err := io.ReadAll(r)
+		return err
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,114 @@
+// Package multihash computes and verifies hex-encoded multihash checksums (as
+// used by the file:checksum field of the file extension).
+package multihash
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha3"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+)
+
+// hashes maps multihash function codes to hash constructors.
+var hashes = map[uint64]func() hash.Hash{
+	0x11: sha1.New,
+	0x12: sha256.New,
+	0x13: sha512.New,
+	0x14: func() hash.Hash { return sha3.New512() },
+	0x15: func() hash.Hash { return sha3.New384() },
+	0x16: func() hash.Hash { return sha3.New256() },
+	0x17: func() hash.Hash { return sha3.New224() },
+	0x20: sha512.New384,
+	0xd5: md5.New,
+}
+
+// Sum reads all data from the reader and returns the hex-encoded multihash
+// checksum for the hash function with the provided code and the number of bytes
+// read.
+func Sum(r io.Reader, code uint64) (string, int64, error) {
+	newHash, ok := hashes[code]
+	if !ok {
+		return "", 0, fmt.Errorf("unsupported hash function 0x%x", code)
+	}
+	h := newHash()
+	size, err := io.Copy(h, r)
+	if err != nil {
+		return "", 0, err
+	}
+	digest := h.Sum(nil)
+	data := binary.AppendUvarint(nil, code)
+	data = binary.AppendUvarint(data, uint64(len(digest)))
+	data = append(data, digest...)
+	return hex.EncodeToString(data), size, nil
+}
+
+// Parse decodes a hex-encoded multihash and returns a new hash for the function
+// and the expected digest.
+func Parse(checksum string) (hash.Hash, []byte, error) {
+	data, err := hex.DecodeString(checksum)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid checksum %q: %w", checksum, err)
+	}
+
+	code, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, nil, fmt.Errorf("invalid checksum %q: bad function code", checksum)
+	}
+	data = data[n:]
+
+	length, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, nil, fmt.Errorf("invalid checksum %q: bad digest length", checksum)
+	}
+	digest := data[n:]
+	if uint64(len(digest)) != length {
+		return nil, nil, fmt.Errorf("invalid checksum %q: expected %d byte digest, got %d", checksum, length, len(digest))
+	}
+
+	newHash, ok := hashes[code]
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported hash function 0x%x in checksum %q", code, checksum)
+	}
+	h := newHash()
+	if h.Size() != len(digest) {
+		return nil, nil, fmt.Errorf("invalid checksum %q: expected %d byte digest for function 0x%x", checksum, h.Size(), code)
+	}
+	return h, digest, nil
+}
+
+// Verify reads all data from the reader and checks that it matches the checksum.
+func Verify(r io.Reader, checksum string) error {
+	h, digest, err := Parse(checksum)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(h, r); err != nil {
+		return err
+	}
+	if sum := h.Sum(nil); !bytes.Equal(sum, digest) {
+		return fmt.Errorf("checksum mismatch: expected %x, got %x", digest, sum)
+	}
+	return nil
+}
+
+// VerifyFile checks that the file contents match the checksum.
+func VerifyFile(path string, checksum string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	if err := Verify(file, checksum); err != nil {
+		return fmt.Errorf("failed to verify %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package multihash_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/planetlabs/go-stac/internal/multihash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	cases := []struct {
+		name     string
+		checksum string
+		err      string
+	}{
+		{
+			name:     "sha2-256",
+			checksum: "1220b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9",
+		},
+		{
+			name:     "sha2-512",
+			checksum: "1340309ecc489c12d6eb4cc40f50c902f2b4d0ed77ee511a7c7a9bcd3ca86d4cd86f989dd35bc5ff499670da34255b45b0cfd830e81f605dcf7dc5542e93ae9cd76f",
+		},
+		{
+			name:     "md5",
+			checksum: "d501105eb63bbbe01eeed093cb22bb8f5acdc3",
+		},
+		{
+			name:     "mismatch",
+			checksum: "1220c94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9",
+			err:      "checksum mismatch",
+		},
+		{
+			name:     "not hex",
+			checksum: "12zz",
+			err:      "invalid checksum",
+		},
+		{
+			name:     "wrong length",
+			checksum: "1221b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9",
+			err:      "expected 33 byte digest",
+		},
+		{
+			name:     "unsupported function",
+			checksum: "1e20b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9",
+			err:      "unsupported hash function",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := multihash.Verify(strings.NewReader("hello world"), c.checksum)
+			if c.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, c.err)
+			}
+		})
+	}
+}
+
+func TestVerifyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.txt")
+	require.NoError(t, os.WriteFile(path, []byte("hello world"), 0644))
+
+	assert.NoError(t, multihash.VerifyFile(path, "1220b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"))
+	assert.ErrorContains(t, multihash.VerifyFile(path, "d501105eb63bbbe01eeed093cb22bb8f5acdc4"), "failed to verify")
+}
+
+func TestSum(t *testing.T) {
+	checksum, size, err := multihash.Sum(strings.NewReader("hello world"), 0x12)
+	require.NoError(t, err)
+	assert.Equal(t, "1220b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9", checksum)
+	assert.Equal(t, int64(11), size)
+
+	checksum, _, err = multihash.Sum(strings.NewReader("hello world"), 0xd5)
+	require.NoError(t, err)
+	assert.Equal(t, "d501105eb63bbbe01eeed093cb22bb8f5acdc3", checksum)
+	require.NoError(t, multihash.Verify(strings.NewReader("hello world"), checksum))
+
+	_, _, err = multihash.Sum(strings.NewReader("hello world"), 0x1e)
+	assert.ErrorContains(t, err, "unsupported hash function")
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"path"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -149,3 +150,25 @@ func (base *Locator) Resolve(s string) (*Locator, error) {
 	}
 	return loc, nil
 }
+
+// Rel returns a relative reference from the resource at one slash-separated
+// path to the resource at another (both relative to the same directory).  The
+// result starts with "./" or "../".
+func Rel(from string, to string) string {
+	fromParts := strings.Split(path.Dir(from), "/")
+	if fromParts[0] == "." {
+		fromParts = nil
+	}
+	toParts := strings.Split(to, "/")
+
+	common := 0
+	for common < len(fromParts) && common < len(toParts)-1 && fromParts[common] == toParts[common] {
+		common += 1
+	}
+
+	rel := strings.Repeat("../", len(fromParts)-common) + strings.Join(toParts[common:], "/")
+	if !strings.HasPrefix(rel, "../") {
+		rel = "./" + rel
+	}
+	return rel
+}
@@ -333,3 +333,25 @@ func TestMarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestRel(t *testing.T) {
+	cases := []struct {
+		from string
+		to   string
+		rel  string
+	}{
+		{from: "catalog.json", to: "catalog.json", rel: "./catalog.json"},
+		{from: "catalog.json", to: "a/collection.json", rel: "./a/collection.json"},
+		{from: "a/collection.json", to: "catalog.json", rel: "../catalog.json"},
+		{from: "a/b/item.json", to: "a/collection.json", rel: "../collection.json"},
+		{from: "a/b/item.json", to: "c/d/item.json", rel: "../../c/d/item.json"},
+		{from: "a/collection.json", to: "a/b/item.json", rel: "./b/item.json"},
+		{from: "a/a.json", to: "a", rel: "../a"},
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%s to %s", c.from, c.to), func(t *testing.T) {
+			assert.Equal(t, c.rel, normurl.Rel(c.from, c.to))
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// Package registry provides a concurrency safe list of values identified by id.
+package registry
+
+import (
+	"slices"
+	"sync"
+)
+
+// Registry holds values in the order they were registered.
+type Registry[T any] struct {
+	mutex  sync.RWMutex
+	id     func(T) string
+	values []T
+}
+
+// New creates a registry that identifies values with the provided function.
+func New[T any](id func(T) string) *Registry[T] {
+	return &Registry[T]{id: id}
+}
+
+// Register adds a value.  A previously registered value with the same id will be
+// replaced.
+func (r *Registry[T]) Register(value T) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	id := r.id(value)
+	for i, existing := range r.values {
+		if r.id(existing) == id {
+			r.values[i] = value
+			return
+		}
+	}
+	r.values = append(r.values, value)
+}
+
+// Unregister removes the value with the provided id.
+func (r *Registry[T]) Unregister(id string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.values = slices.DeleteFunc(r.values, func(value T) bool {
+		return r.id(value) == id
+	})
+}
+
+// Values returns a copy of the registered values.
+func (r *Registry[T]) Values() []T {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return slices.Clone(r.values)
+}
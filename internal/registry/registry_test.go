@@ -0,0 +1,37 @@
+package registry_test
+
+import (
+	"testing"
+
+	"github.com/planetlabs/go-stac/internal/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+type value struct {
+	id    string
+	count int
+}
+
+func TestRegistry(t *testing.T) {
+	r := registry.New(func(v *value) string { return v.id })
+
+	a := &value{id: "a"}
+	b := &value{id: "b"}
+	r.Register(a)
+	r.Register(b)
+	assert.Equal(t, []*value{a, b}, r.Values())
+
+	replacement := &value{id: "a", count: 1}
+	r.Register(replacement)
+	assert.Equal(t, []*value{replacement, b}, r.Values())
+
+	r.Unregister("a")
+	assert.Equal(t, []*value{b}, r.Values())
+
+	r.Unregister("missing")
+	assert.Equal(t, []*value{b}, r.Values())
+
+	values := r.Values()
+	values[0] = nil
+	assert.Equal(t, []*value{b}, r.Values())
+}
@@ -0,0 +1,95 @@
+// Package summaries encodes and decodes collection extensions that are
+// represented by the fields of the collection summaries.
+package summaries
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/planetlabs/go-stac"
+)
+
+const summariesKey = "summaries"
+
+// Encode adds the fields of an extension to the collection summaries.  The
+// extension fields take precedence over values with the same name in the
+// collection's Summaries map.
+func Encode(extension stac.Extension, collectionMap map[string]any) error {
+	summaries, err := collectionSummaries(collectionMap)
+	if err != nil {
+		return err
+	}
+	extended := map[string]any{}
+	if err := stac.EncodeExtendedMap(extension, extended); err != nil {
+		return err
+	}
+	maps.Copy(summaries, extended)
+	collectionMap[summariesKey] = summaries
+	return nil
+}
+
+// Decode sets the fields of an extension from the prefixed collection summaries.
+//
+// A summary may be a list of values, a range, or a JSON Schema object.  Summaries
+// that are not in the form used by the extension field are skipped (they remain
+// in the collection's Summaries map).  If no summaries can be decoded,
+// stac.ErrExtensionDoesNotApply is returned.
+func Decode(extension stac.Extension, collectionMap map[string]any, prefix string) error {
+	summaries, err := collectionSummaries(collectionMap)
+	if err != nil {
+		return err
+	}
+
+	decoded := false
+	for _, key := range slices.Sorted(maps.Keys(summaries)) {
+		if !strings.HasPrefix(key, prefix+":") {
+			continue
+		}
+		field := map[string]any{key: summaries[key]}
+
+		// decode into a scratch value first so a mismatch leaves the extension unchanged
+		scratch := reflect.New(reflect.TypeOf(extension).Elem()).Interface()
+		if decodeField(field, scratch) != nil {
+			continue
+		}
+		if err := decodeField(field, extension); err != nil {
+			return err
+		}
+		decoded = true
+	}
+	if !decoded {
+		return stac.ErrExtensionDoesNotApply
+	}
+	return nil
+}
+
+func decodeField(field map[string]any, result any) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		TagName:     "json",
+		Result:      result,
+		ErrorUnused: true,
+	})
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(field)
+}
+
+// collectionSummaries returns a copy of the collection summaries.
+func collectionSummaries(collectionMap map[string]any) (map[string]any, error) {
+	summaries := map[string]any{}
+	value, ok := collectionMap[summariesKey]
+	if !ok || value == nil {
+		return summaries, nil
+	}
+	existing, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected a summaries object, got %T", value)
+	}
+	maps.Copy(summaries, existing)
+	return summaries, nil
+}
@@ -0,0 +1,234 @@
+// Package linter checks STAC resources against the STAC best practices.
+package linter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/planetlabs/go-stac/crawler"
+)
+
+// Context provides a rule with the resource being checked.
+type Context struct {
+	// Resource is the resource being checked.  Fix functions modify this resource.
+	Resource crawler.Resource
+
+	// Location is the file path or URL of the resource.
+	Location string
+
+	// Entry is the file path or URL of the resource where the crawl started.
+	Entry string
+}
+
+// Rule checks a resource against a best practice.
+type Rule struct {
+	// Id uniquely identifies the rule.
+	Id string
+
+	// Description is a short summary of the best practice.
+	Description string
+
+	// Check returns a message for each problem found.
+	Check func(*Context) []string
+
+	// Fix is an optional function that modifies the resource to address problems
+	// reported by Check.  The function returns false if none of the problems could be
+	// fixed.  Problems still reported by Check after the fix are not considered fixed.
+	Fix func(*Context) bool
+}
+
+// Problem is reported when a resource does not follow a best practice.
+type Problem struct {
+	// Rule is the rule that reported the problem.
+	Rule *Rule
+
+	// Message describes the problem.
+	Message string
+
+	// Fixed is true if the problem was fixed in the report resource.
+	Fixed bool
+}
+
+// Report includes the problems found with a single resource.
+type Report struct {
+	// Location is the file path or URL of the resource.
+	Location string
+
+	// Entry is the file path or URL of the resource where the crawl started.
+	Entry string
+
+	// Resource is the linted resource.  If fixing is enabled, this is a copy of the
+	// original resource with fixes applied.
+	Resource crawler.Resource
+
+	// Problems found with the resource.
+	Problems []*Problem
+}
+
+// Config can be loaded from a file to configure the linter.
+type Config struct {
+	// Disable is a list of rule identifiers that will not be checked.
+	Disable []string `json:"disable,omitempty"`
+}
+
+// LoadConfig reads a JSON config file.
+func LoadConfig(configPath string) (*Config, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", configPath, err)
+	}
+	config := &Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", configPath, err)
+	}
+	return config, nil
+}
+
+// Linter checks STAC resources against best practices.
+type Linter struct {
+	concurrency int
+	noRecursion bool
+	fix         bool
+	rules       []*Rule
+}
+
+// Options for the Linter.
+type Options struct {
+	// Limit to the number of resources to fetch and lint concurrently.
+	Concurrency int
+
+	// Set to true to lint a single resource and avoid linting all linked resources.
+	NoRecursion bool
+
+	// Set to true to apply fixes for problems that can be fixed mechanically.
+	Fix bool
+
+	// Rules to check.  By default, all of the Rules are checked.
+	Rules []*Rule
+
+	// Optional config with rules to disable.
+	Config *Config
+}
+
+// New creates a new Linter.
+func New(options ...*Options) *Linter {
+	l := &Linter{
+		concurrency: runtime.GOMAXPROCS(0),
+		rules:       Rules(),
+	}
+	disabled := []string{}
+	for _, opt := range options {
+		if opt.Concurrency != 0 {
+			l.concurrency = opt.Concurrency
+		}
+		if opt.NoRecursion {
+			l.noRecursion = opt.NoRecursion
+		}
+		if opt.Fix {
+			l.fix = opt.Fix
+		}
+		if opt.Rules != nil {
+			l.rules = opt.Rules
+		}
+		if opt.Config != nil {
+			disabled = append(disabled, opt.Config.Disable...)
+		}
+	}
+
+	l.rules = slices.DeleteFunc(slices.Clone(l.rules), func(rule *Rule) bool {
+		return slices.Contains(disabled, rule.Id)
+	})
+	return l
+}
+
+// ReportHandler is called with a report for each resource.  Calls are not
+// made concurrently.  Any returned error will stop linting.
+type ReportHandler func(*Report) error
+
+// Lint crawls STAC resources starting with the entry and calls the handler
+// with a report for each resource.
+//
+// The entry can be a path to a local file or a URL.  Context cancellation will
+// stop linting and the context error will be returned.
+func (l *Linter) Lint(ctx context.Context, entry string, handler ReportHandler) error {
+	mutex := &sync.Mutex{}
+	visitor := func(resource crawler.Resource, info *crawler.ResourceInfo) error {
+		report, err := l.LintResource(resource, info)
+		if err != nil {
+			return err
+		}
+
+		mutex.Lock()
+		err = handler(report)
+		mutex.Unlock()
+		if err != nil {
+			return err
+		}
+
+		if l.noRecursion {
+			return crawler.ErrStopRecursion
+		}
+		return nil
+	}
+
+	return crawler.Crawl(entry, visitor, &crawler.Options{
+		Queue: crawler.NewMemoryQueue(ctx, l.concurrency),
+	})
+}
+
+// LintResource checks a single resource.
+func (l *Linter) LintResource(resource crawler.Resource, info *crawler.ResourceInfo) (*Report, error) {
+	report := &Report{
+		Location: info.Location,
+		Entry:    info.Entry,
+		Resource: resource,
+		Problems: []*Problem{},
+	}
+
+	if l.fix {
+		clone, err := cloneResource(resource)
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy %s: %w", info.Location, err)
+		}
+		report.Resource = clone
+	}
+
+	ctx := &Context{
+		Resource: report.Resource,
+		Location: info.Location,
+		Entry:    info.Entry,
+	}
+	for _, rule := range l.rules {
+		messages := rule.Check(ctx)
+		if len(messages) == 0 {
+			continue
+		}
+		var remaining []string
+		fixed := l.fix && rule.Fix != nil && rule.Fix(ctx)
+		if fixed {
+			remaining = rule.Check(ctx)
+		}
+		for _, message := range messages {
+			problem := &Problem{Rule: rule, Message: message, Fixed: fixed && !slices.Contains(remaining, message)}
+			report.Problems = append(report.Problems, problem)
+		}
+	}
+	return report, nil
+}
+
+func cloneResource(resource crawler.Resource) (crawler.Resource, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	clone := crawler.Resource{}
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
@@ -0,0 +1,177 @@
+package linter_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/planetlabs/go-stac/crawler"
+	"github.com/planetlabs/go-stac/linter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lint(t *testing.T, entry string, options *linter.Options) map[string]*linter.Report {
+	reports := map[string]*linter.Report{}
+	l := linter.New(options)
+	err := l.Lint(context.Background(), entry, func(report *linter.Report) error {
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		rel, err := filepath.Rel(filepath.Join(wd, "testdata"), report.Location)
+		require.NoError(t, err)
+		reports[filepath.ToSlash(rel)] = report
+		return nil
+	})
+	require.NoError(t, err)
+	return reports
+}
+
+func problemRules(report *linter.Report) []string {
+	ids := []string{}
+	for _, problem := range report.Problems {
+		ids = append(ids, problem.Rule.Id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestLintGood(t *testing.T) {
+	reports := lint(t, "testdata/good/catalog.json", &linter.Options{})
+	require.Len(t, reports, 3)
+	for location, report := range reports {
+		assert.Empty(t, report.Problems, location)
+	}
+}
+
+func TestLint(t *testing.T) {
+	reports := lint(t, "testdata/catalog.json", &linter.Options{})
+	require.Len(t, reports, 3)
+
+	assert.Equal(t, []string{"link-media-type", "self-link"}, problemRules(reports["catalog.json"]))
+
+	assert.Equal(t, []string{
+		"collection-title",
+		"id-format",
+		"id-format",
+		"self-link",
+	}, problemRules(reports["Messy-Collection/collection.json"]))
+
+	assert.Equal(t, []string{
+		"absolute-root-link",
+		"asset-roles",
+		"file-layout",
+		"item-thumbnail",
+	}, problemRules(reports["Messy-Collection/item-1/item.json"]))
+}
+
+func TestLintDisable(t *testing.T) {
+	reports := lint(t, "testdata/catalog.json", &linter.Options{
+		NoRecursion: true,
+		Config:      &linter.Config{Disable: []string{"self-link"}},
+	})
+	require.Len(t, reports, 1)
+
+	assert.Equal(t, []string{"link-media-type"}, problemRules(reports["catalog.json"]))
+}
+
+func TestLintFix(t *testing.T) {
+	reports := lint(t, "testdata/catalog.json", &linter.Options{Fix: true})
+	require.Len(t, reports, 3)
+
+	catalog := reports["catalog.json"]
+	for _, problem := range catalog.Problems {
+		switch problem.Rule.Id {
+		case "link-media-type":
+			assert.True(t, problem.Fixed)
+		case "self-link":
+			assert.False(t, problem.Fixed, "self link cannot be fixed for a file")
+		}
+	}
+	child := catalog.Resource.Links().Rel("child")
+	require.NotNil(t, child)
+	assert.Equal(t, "application/json", child["type"])
+
+	item := reports["Messy-Collection/item-1/item.json"]
+	for _, problem := range item.Problems {
+		switch problem.Rule.Id {
+		case "absolute-root-link", "asset-roles":
+			assert.True(t, problem.Fixed, problem.Rule.Id)
+		default:
+			assert.False(t, problem.Fixed, problem.Rule.Id)
+		}
+	}
+	root := item.Resource.Links().Rel("root")
+	require.NotNil(t, root)
+	assert.Equal(t, "https://example.com/stac/catalog.json", root["href"])
+	assert.Equal(t, []string{"thumbnail"}, item.Resource.Assets()["thumbnail"].Roles())
+}
+
+func TestLintFixSelfLink(t *testing.T) {
+	l := linter.New(&linter.Options{Fix: true})
+	resource := crawler.Resource{
+		"type":         "Catalog",
+		"stac_version": "1.0.0",
+		"id":           "catalog",
+		"description":  "A catalog",
+		"links":        []any{},
+	}
+	report, err := l.LintResource(resource, &crawler.ResourceInfo{
+		Location: "https://example.com/stac/catalog.json",
+		Entry:    "https://example.com/stac/catalog.json",
+	})
+	require.NoError(t, err)
+	require.Len(t, report.Problems, 1)
+	assert.True(t, report.Problems[0].Fixed)
+
+	self := report.Resource.Links().Rel("self")
+	require.NotNil(t, self)
+	assert.Equal(t, "https://example.com/stac/catalog.json", self["href"])
+	assert.Empty(t, resource.Links(), "original resource is not modified")
+}
+
+func TestLintFixAssetRoles(t *testing.T) {
+	l := linter.New(&linter.Options{Fix: true, Rules: []*linter.Rule{ruleById(t, "asset-roles")}})
+	resource := crawler.Resource{
+		"type":         "Feature",
+		"stac_version": "1.0.0",
+		"id":           "item",
+		"links":        []any{},
+		"assets": map[string]any{
+			"thumbnail": map[string]any{"href": "./thumbnail.png"},
+			"visual":    map[string]any{"href": "./visual.tif"},
+		},
+	}
+	report, err := l.LintResource(resource, &crawler.ResourceInfo{Location: "item.json", Entry: "item.json"})
+	require.NoError(t, err)
+	require.Len(t, report.Problems, 2)
+
+	fixed := map[string]bool{}
+	for _, problem := range report.Problems {
+		fixed[problem.Message] = problem.Fixed
+	}
+	assert.Equal(t, map[string]bool{
+		`asset "thumbnail" has no roles`: true,
+		`asset "visual" has no roles`:    false,
+	}, fixed)
+}
+
+func ruleById(t *testing.T, id string) *linter.Rule {
+	for _, rule := range linter.Rules() {
+		if rule.Id == id {
+			return rule
+		}
+	}
+	require.Failf(t, "missing rule", "no rule with id %q", id)
+	return nil
+}
+
+func TestLoadConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"disable": ["file-layout", "item-thumbnail"]}`), 0644))
+
+	config, err := linter.LoadConfig(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"file-layout", "item-thumbnail"}, config.Disable)
+}
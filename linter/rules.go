@@ -0,0 +1,307 @@
+package linter
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/planetlabs/go-stac/crawler"
+	"github.com/planetlabs/go-stac/internal/registry"
+)
+
+var rules = registry.New(func(rule *Rule) string { return rule.Id })
+
+// RegisterRule adds a rule to the list returned by Rules.  A previously registered rule
+// with the same id will be replaced.
+func RegisterRule(rule *Rule) {
+	rules.Register(rule)
+}
+
+// UnregisterRule removes a rule added with RegisterRule.
+func UnregisterRule(id string) {
+	rules.Unregister(id)
+}
+
+// Rules returns the built-in rules and any rules added with RegisterRule.
+func Rules() []*Rule {
+	return rules.Values()
+}
+
+func init() {
+	RegisterRule(&Rule{
+		Id:          "self-link",
+		Description: "Resources should have a self link.",
+		Check:       checkSelfLink,
+		Fix:         fixSelfLink,
+	})
+	RegisterRule(&Rule{
+		Id:          "absolute-root-link",
+		Description: "Published resources (with an absolute self link) should have an absolute root link.",
+		Check:       checkAbsoluteRootLink,
+		Fix:         fixAbsoluteRootLink,
+	})
+	RegisterRule(&Rule{
+		Id:          "link-media-type",
+		Description: "Links to STAC resources should include a media type.",
+		Check:       checkLinkMediaType,
+		Fix:         fixLinkMediaType,
+	})
+	RegisterRule(&Rule{
+		Id:          "id-format",
+		Description: "Identifiers should be lowercase and should not contain spaces.",
+		Check:       checkIdFormat,
+	})
+	RegisterRule(&Rule{
+		Id:          "collection-title",
+		Description: "Collections should have a title.",
+		Check:       checkCollectionTitle,
+	})
+	RegisterRule(&Rule{
+		Id:          "asset-roles",
+		Description: "Assets should have roles.",
+		Check:       checkAssetRoles,
+		Fix:         fixAssetRoles,
+	})
+	RegisterRule(&Rule{
+		Id:          "item-thumbnail",
+		Description: "Items should have a thumbnail asset.",
+		Check:       checkItemThumbnail,
+	})
+	RegisterRule(&Rule{
+		Id:          "file-layout",
+		Description: "Static catalogs should follow the recommended file layout.",
+		Check:       checkFileLayout,
+	})
+}
+
+// rawLinks returns the links of a resource as a slice of maps that can be modified.
+func rawLinks(resource crawler.Resource) []map[string]any {
+	values, ok := resource["links"].([]any)
+	if !ok {
+		return nil
+	}
+	links := []map[string]any{}
+	for _, value := range values {
+		if link, ok := value.(map[string]any); ok {
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+func isURL(location string) bool {
+	u, err := url.Parse(location)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "http" || u.Scheme == "https"
+}
+
+func checkSelfLink(ctx *Context) []string {
+	if ctx.Resource.Links().Rel("self") != nil {
+		return nil
+	}
+	return []string{"missing self link"}
+}
+
+func fixSelfLink(ctx *Context) bool {
+	if !isURL(ctx.Location) {
+		return false
+	}
+	link := map[string]any{"rel": "self", "href": ctx.Location}
+	if ctx.Resource.Type() == crawler.Item {
+		link["type"] = "application/geo+json"
+	} else {
+		link["type"] = "application/json"
+	}
+	links, _ := ctx.Resource["links"].([]any)
+	ctx.Resource["links"] = append(links, link)
+	return true
+}
+
+func checkAbsoluteRootLink(ctx *Context) []string {
+	links := ctx.Resource.Links()
+	self := links.Rel("self")
+	root := links.Rel("root")
+	if self == nil || root == nil || !isURL(self["href"]) || isURL(root["href"]) {
+		return nil
+	}
+	return []string{fmt.Sprintf("root link %q is relative but the self link is absolute", root["href"])}
+}
+
+func fixAbsoluteRootLink(ctx *Context) bool {
+	self := ctx.Resource.Links().Rel("self")
+	if self == nil {
+		return false
+	}
+	base, err := url.Parse(self["href"])
+	if err != nil {
+		return false
+	}
+	for _, link := range rawLinks(ctx.Resource) {
+		if link["rel"] != "root" {
+			continue
+		}
+		href, _ := link["href"].(string)
+		ref, err := url.Parse(href)
+		if err != nil {
+			return false
+		}
+		link["href"] = base.ResolveReference(ref).String()
+	}
+	return true
+}
+
+var linkMediaTypes = map[string]string{
+	"self":       "",
+	"root":       "application/json",
+	"parent":     "application/json",
+	"child":      "application/json",
+	"collection": "application/json",
+	"item":       "application/geo+json",
+}
+
+func checkLinkMediaType(ctx *Context) []string {
+	messages := []string{}
+	for _, link := range ctx.Resource.Links() {
+		rel := link["rel"]
+		if _, ok := linkMediaTypes[rel]; !ok {
+			continue
+		}
+		if link["type"] == "" {
+			messages = append(messages, fmt.Sprintf("%s link %q has no media type", rel, link["href"]))
+		}
+	}
+	return messages
+}
+
+func fixLinkMediaType(ctx *Context) bool {
+	for _, link := range rawLinks(ctx.Resource) {
+		rel, _ := link["rel"].(string)
+		mediaType, ok := linkMediaTypes[rel]
+		if !ok {
+			continue
+		}
+		if t, _ := link["type"].(string); t != "" {
+			continue
+		}
+		if rel == "self" {
+			mediaType = "application/json"
+			if ctx.Resource.Type() == crawler.Item {
+				mediaType = "application/geo+json"
+			}
+		}
+		link["type"] = mediaType
+	}
+	return true
+}
+
+func checkIdFormat(ctx *Context) []string {
+	id, ok := ctx.Resource["id"].(string)
+	if !ok {
+		return nil
+	}
+	messages := []string{}
+	if strings.ToLower(id) != id {
+		messages = append(messages, fmt.Sprintf("id %q is not lowercase", id))
+	}
+	if strings.ContainsFunc(id, unicode.IsSpace) {
+		messages = append(messages, fmt.Sprintf("id %q contains spaces", id))
+	}
+	return messages
+}
+
+func checkCollectionTitle(ctx *Context) []string {
+	if ctx.Resource.Type() != crawler.Collection {
+		return nil
+	}
+	if title, _ := ctx.Resource["title"].(string); title != "" {
+		return nil
+	}
+	return []string{"collection has no title"}
+}
+
+// wellKnownRoles are used to fix assets without roles when the asset key matches
+var wellKnownRoles = []string{"thumbnail", "overview", "data", "metadata"}
+
+func checkAssetRoles(ctx *Context) []string {
+	messages := []string{}
+	assets := ctx.Resource.Assets()
+	for _, key := range slices.Sorted(maps.Keys(assets)) {
+		if len(assets[key].Roles()) == 0 {
+			messages = append(messages, fmt.Sprintf("asset %q has no roles", key))
+		}
+	}
+	return messages
+}
+
+func fixAssetRoles(ctx *Context) bool {
+	fixed := false
+	for key, asset := range ctx.Resource.Assets() {
+		if len(asset.Roles()) > 0 || !slices.Contains(wellKnownRoles, key) {
+			continue
+		}
+		asset["roles"] = []any{key}
+		fixed = true
+	}
+	return fixed
+}
+
+func checkItemThumbnail(ctx *Context) []string {
+	if ctx.Resource.Type() != crawler.Item {
+		return nil
+	}
+	for _, asset := range ctx.Resource.Assets() {
+		if slices.Contains(asset.Roles(), "thumbnail") {
+			return nil
+		}
+	}
+	return []string{"item has no thumbnail asset"}
+}
+
+func locationPath(location string) string {
+	if isURL(location) {
+		u, _ := url.Parse(location)
+		return u.Path
+	}
+	return filepath.ToSlash(location)
+}
+
+func checkFileLayout(ctx *Context) []string {
+	p := locationPath(ctx.Location)
+	if path.Ext(p) != ".json" {
+		// not a static catalog
+		return nil
+	}
+	name := path.Base(p)
+
+	switch ctx.Resource.Type() {
+	case crawler.Catalog:
+		if name != "catalog.json" {
+			return []string{fmt.Sprintf("catalog file is named %q instead of \"catalog.json\"", name)}
+		}
+	case crawler.Collection:
+		if name != "collection.json" {
+			return []string{fmt.Sprintf("collection file is named %q instead of \"collection.json\"", name)}
+		}
+	case crawler.Item:
+		id, ok := ctx.Resource["id"].(string)
+		if !ok {
+			return nil
+		}
+		messages := []string{}
+		if name != id+".json" {
+			messages = append(messages, fmt.Sprintf("item file is named %q instead of %q", name, id+".json"))
+		}
+		if dir := path.Base(path.Dir(p)); dir != id {
+			messages = append(messages, fmt.Sprintf("item is in directory %q instead of %q", dir, id))
+		}
+		return messages
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+// Package relink rewrites the links in crawled STAC resources.
+package relink
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/planetlabs/go-stac/crawler"
+	"github.com/planetlabs/go-stac/internal/normurl"
+)
+
+// Options for making links relative.
+type Options struct {
+	// URL is the published URL of the entry resource.  Absolute hrefs under the
+	// entry's directory at this URL are made relative and self links are set to
+	// the published URL of each resource.  If not provided, self links are only
+	// kept when crawling a URL.
+	URL string
+
+	// Parent is the location of the resource's parent.  If provided, the parent
+	// link is inserted or corrected.
+	Parent string
+
+	// Assets also makes asset hrefs relative.
+	Assets bool
+}
+
+// location is the URL or file path of a resource.
+type location struct {
+	file bool
+	url  *url.URL
+	path string
+}
+
+func parseLocation(s string) (*location, error) {
+	loc, err := normurl.New(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid location %q: %w", s, err)
+	}
+	if loc.IsFilepath() {
+		return &location{file: true, path: loc.String()}, nil
+	}
+	u, err := url.Parse(loc.String())
+	if err != nil {
+		return nil, err
+	}
+	return &location{url: u}, nil
+}
+
+// dir returns the directory of the location with a trailing separator.
+func (l *location) dir() string {
+	if l.file {
+		return filepath.Dir(l.path) + string(filepath.Separator)
+	}
+	u := *l.url
+	u.RawQuery = ""
+	u.Fragment = ""
+	u.Path = path.Dir(u.Path) + "/"
+	if u.Path == "//" {
+		u.Path = "/"
+	}
+	return u.String()
+}
+
+// under returns the slash-separated path of the href relative to the
+// directory (and false if the href is not under the directory).
+func under(dir string, href string, file bool) (string, bool) {
+	if file {
+		if !filepath.IsAbs(href) {
+			return "", false
+		}
+		rel, err := filepath.Rel(dir, href)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", false
+		}
+		return filepath.ToSlash(rel), true
+	}
+	if !strings.HasPrefix(href, dir) {
+		return "", false
+	}
+	return strings.TrimPrefix(href, dir), true
+}
+
+// relinker has the context for rewriting one resource.
+type relinker struct {
+	// dirs are the directories (file paths or URLs) under which hrefs are made relative
+	dirs []*location
+
+	// path is the slash-separated path of the resource relative to the entry directory
+	path string
+
+	// rootPath is the slash-separated path of the entry relative to its directory
+	rootPath string
+
+	// self is the published URL of the resource (if known)
+	self string
+}
+
+func (r *relinker) relative(href string) string {
+	u, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+
+	suffix := ""
+	if u.RawQuery != "" {
+		suffix += "?" + u.RawQuery
+	}
+	if u.Fragment != "" {
+		suffix += "#" + u.Fragment
+	}
+
+	for _, dir := range r.dirs {
+		target := href
+		if !dir.file {
+			if !u.IsAbs() {
+				continue
+			}
+			base := *u
+			base.RawQuery = ""
+			base.Fragment = ""
+			target = base.String()
+		} else if u.Scheme == "" && !filepath.IsAbs(href) {
+			continue
+		}
+		if rel, ok := under(dir.dir(), target, dir.file); ok {
+			return normurl.Rel(r.path, rel) + suffix
+		}
+	}
+	return href
+}
+
+// MakeRelative returns a copy of a crawled resource with links made relative.
+// The entry for the crawl is treated as the root.  Absolute hrefs that point to
+// resources under the directory of the entry are made relative, the root link
+// is set to the entry, and self links are inserted or corrected.
+func MakeRelative(resource crawler.Resource, info *crawler.ResourceInfo, options ...*Options) (crawler.Resource, error) {
+	opts := &Options{}
+	for _, opt := range options {
+		if opt.URL != "" {
+			opts.URL = opt.URL
+		}
+		if opt.Parent != "" {
+			opts.Parent = opt.Parent
+		}
+		if opt.Assets {
+			opts.Assets = true
+		}
+	}
+
+	entry, err := parseLocation(info.Entry)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := parseLocation(info.Location)
+	if err != nil {
+		return nil, err
+	}
+	if entry.file != loc.file {
+		return nil, fmt.Errorf("cannot relate %s to %s", info.Location, info.Entry)
+	}
+
+	r := &relinker{dirs: []*location{entry}}
+
+	var ok bool
+	if loc.file {
+		r.path, ok = under(entry.dir(), loc.path, true)
+		r.rootPath = filepath.Base(entry.path)
+	} else {
+		r.path, ok = under(entry.dir(), loc.dir()+path.Base(loc.url.Path), false)
+		r.rootPath = path.Base(entry.url.Path)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%s is not under the directory of %s", info.Location, info.Entry)
+	}
+
+	if opts.URL != "" {
+		published, err := url.Parse(opts.URL)
+		if err != nil || !published.IsAbs() {
+			return nil, fmt.Errorf("invalid URL: %s", opts.URL)
+		}
+		publishedLoc := &location{url: published}
+		r.dirs = append(r.dirs, publishedLoc)
+		r.self = publishedLoc.dir() + r.path
+	} else if !loc.file {
+		r.self = info.Location
+	}
+
+	var parentPath string
+	if opts.Parent != "" {
+		parent, err := parseLocation(opts.Parent)
+		if err != nil {
+			return nil, err
+		}
+		if parent.file {
+			parentPath, ok = under(entry.dir(), parent.path, true)
+		} else {
+			parentPath, ok = under(entry.dir(), parent.dir()+path.Base(parent.url.Path), false)
+		}
+		if !ok {
+			return nil, fmt.Errorf("parent %s is not under the directory of %s", opts.Parent, info.Entry)
+		}
+	}
+
+	relResource := crawler.Resource{}
+	for key, value := range resource {
+		relResource[key] = value
+	}
+
+	links := []any{}
+	hasRoot, hasSelf, hasParent := false, false, false
+	values, _ := resource["links"].([]any)
+	for _, value := range values {
+		original, ok := value.(map[string]any)
+		if !ok {
+			links = append(links, value)
+			continue
+		}
+		link := map[string]any{}
+		for k, v := range original {
+			link[k] = v
+		}
+
+		href, _ := link["href"].(string)
+		switch link["rel"] {
+		case "root":
+			if hasRoot {
+				continue
+			}
+			hasRoot = true
+			link["href"] = normurl.Rel(r.path, r.rootPath)
+		case "self":
+			if hasSelf || r.self == "" {
+				continue
+			}
+			hasSelf = true
+			link["href"] = r.self
+		case "parent":
+			if parentPath != "" {
+				if hasParent {
+					continue
+				}
+				hasParent = true
+				link["href"] = normurl.Rel(r.path, parentPath)
+			} else {
+				link["href"] = r.relative(href)
+			}
+		default:
+			link["href"] = r.relative(href)
+		}
+		links = append(links, link)
+	}
+
+	inserted := []any{}
+	if !hasRoot {
+		inserted = append(inserted, map[string]any{"rel": "root", "href": normurl.Rel(r.path, r.rootPath), "type": "application/json"})
+	}
+	if !hasSelf && r.self != "" {
+		mediaType := "application/json"
+		if resource.Type() == crawler.Item {
+			mediaType = "application/geo+json"
+		}
+		inserted = append(inserted, map[string]any{"rel": "self", "href": r.self, "type": mediaType})
+	}
+	if !hasParent && parentPath != "" {
+		inserted = append(inserted, map[string]any{"rel": "parent", "href": normurl.Rel(r.path, parentPath), "type": "application/json"})
+	}
+	relResource["links"] = append(inserted, links...)
+
+	if opts.Assets {
+		if assets, ok := resource["assets"].(map[string]any); ok {
+			relAssets := map[string]any{}
+			for key, value := range assets {
+				original, ok := value.(map[string]any)
+				if !ok {
+					relAssets[key] = value
+					continue
+				}
+				asset := map[string]any{}
+				for k, v := range original {
+					asset[k] = v
+				}
+				if href, ok := asset["href"].(string); ok {
+					asset["href"] = r.relative(href)
+				}
+				relAssets[key] = asset
+			}
+			relResource["assets"] = relAssets
+		}
+	}
+
+	return relResource, nil
+}
@@ -0,0 +1,161 @@
+package relink_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/planetlabs/go-stac/crawler"
+	"github.com/planetlabs/go-stac/relink"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseResource(t *testing.T, data string) crawler.Resource {
+	resource := crawler.Resource{}
+	require.NoError(t, json.Unmarshal([]byte(data), &resource))
+	return resource
+}
+
+func linkHrefs(resource crawler.Resource) map[string][]string {
+	hrefs := map[string][]string{}
+	for _, link := range resource.Links() {
+		hrefs[link["rel"]] = append(hrefs[link["rel"]], link["href"])
+	}
+	return hrefs
+}
+
+const item = `{
+	"type": "Feature",
+	"stac_version": "1.0.0",
+	"id": "item-1",
+	"links": [
+		{"rel": "root", "href": "https://old.example.com/stac/catalog.json"},
+		{"rel": "self", "href": "https://old.example.com/stac/collection/item-1/item-1.json"},
+		{"rel": "parent", "href": "https://old.example.com/stac/collection/collection.json"},
+		{"rel": "collection", "href": "https://old.example.com/stac/collection/collection.json"},
+		{"rel": "license", "href": "https://example.com/license.html"},
+		{"rel": "alternate", "href": "./item-1.html"}
+	],
+	"assets": {
+		"data": {"href": "https://old.example.com/stac/collection/item-1/data.tif"},
+		"thumbnail": {"href": "https://cdn.example.com/item-1.png"}
+	}
+}`
+
+func TestMakeRelative(t *testing.T) {
+	resource := parseResource(t, item)
+	info := &crawler.ResourceInfo{
+		Entry:    "https://new.example.com/stac/catalog.json",
+		Location: "https://new.example.com/stac/collection/item-1/item-1.json",
+	}
+
+	relResource, err := relink.MakeRelative(resource, info, &relink.Options{
+		URL:    "https://old.example.com/stac/catalog.json",
+		Assets: true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]string{
+		"root":       {"../../catalog.json"},
+		"self":       {"https://old.example.com/stac/collection/item-1/item-1.json"},
+		"parent":     {"../collection.json"},
+		"collection": {"../collection.json"},
+		"license":    {"https://example.com/license.html"},
+		"alternate":  {"./item-1.html"},
+	}, linkHrefs(relResource))
+
+	assets := relResource.Assets()
+	assert.Equal(t, "./data.tif", assets["data"]["href"])
+	assert.Equal(t, "https://cdn.example.com/item-1.png", assets["thumbnail"]["href"])
+
+	// the original resource is not modified
+	assert.Equal(t, "https://old.example.com/stac/catalog.json", resource.Links()[0]["href"])
+	assert.Equal(t, "https://old.example.com/stac/collection/item-1/data.tif", resource.Assets()["data"]["href"])
+}
+
+func TestMakeRelativeWithoutAssets(t *testing.T) {
+	info := &crawler.ResourceInfo{
+		Entry:    "https://old.example.com/stac/catalog.json",
+		Location: "https://old.example.com/stac/collection/item-1/item-1.json",
+	}
+
+	relResource, err := relink.MakeRelative(parseResource(t, item), info)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"https://old.example.com/stac/collection/item-1/item-1.json"}, linkHrefs(relResource)["self"])
+	assert.Equal(t, []string{"../collection.json"}, linkHrefs(relResource)["parent"])
+	assert.Equal(t, "https://old.example.com/stac/collection/item-1/data.tif", relResource.Assets()["data"]["href"])
+}
+
+func TestMakeRelativeInsertLinks(t *testing.T) {
+	dir := filepath.Join(string(filepath.Separator), "data", "stac")
+	resource := parseResource(t, `{
+		"type": "Collection",
+		"stac_version": "1.0.0",
+		"id": "collection",
+		"extent": {},
+		"links": [
+			{"rel": "self", "href": "/somewhere/else/collection.json"},
+			{"rel": "parent", "href": "../../wrong.json"},
+			{"rel": "item", "href": "/data/stac/sub/collection/item-1/item-1.json"}
+		]
+	}`)
+	info := &crawler.ResourceInfo{
+		Entry:    filepath.Join(dir, "catalog.json"),
+		Location: filepath.Join(dir, "sub", "collection", "collection.json"),
+	}
+
+	relResource, err := relink.MakeRelative(resource, info, &relink.Options{
+		Parent: filepath.Join(dir, "sub", "catalog.json"),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]string{
+		"root":   {"../../catalog.json"},
+		"parent": {"../catalog.json"},
+		"item":   {"./item-1/item-1.json"},
+	}, linkHrefs(relResource))
+
+	links := relResource.Links()
+	assert.Equal(t, "root", links[0]["rel"])
+	assert.Equal(t, "application/json", links[0]["type"])
+}
+
+func TestMakeRelativeSelfLink(t *testing.T) {
+	dir := filepath.Join(string(filepath.Separator), "data", "stac")
+	resource := parseResource(t, `{
+		"type": "Catalog",
+		"stac_version": "1.0.0",
+		"id": "root",
+		"links": []
+	}`)
+	info := &crawler.ResourceInfo{
+		Entry:    filepath.Join(dir, "catalog.json"),
+		Location: filepath.Join(dir, "catalog.json"),
+	}
+
+	relResource, err := relink.MakeRelative(resource, info, &relink.Options{URL: "https://example.com/stac/catalog.json"})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]string{
+		"root": {"./catalog.json"},
+		"self": {"https://example.com/stac/catalog.json"},
+	}, linkHrefs(relResource))
+}
+
+func TestMakeRelativeErrors(t *testing.T) {
+	resource := parseResource(t, item)
+
+	_, err := relink.MakeRelative(resource, &crawler.ResourceInfo{
+		Entry:    "https://example.com/stac/catalog.json",
+		Location: "https://example.com/other/item.json",
+	})
+	assert.ErrorContains(t, err, "is not under the directory")
+
+	_, err = relink.MakeRelative(resource, &crawler.ResourceInfo{
+		Entry:    "https://example.com/stac/catalog.json",
+		Location: "https://example.com/stac/item.json",
+	}, &relink.Options{URL: "not-absolute"})
+	assert.ErrorContains(t, err, "invalid URL")
+}
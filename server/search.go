@@ -0,0 +1,454 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/planetlabs/go-stac/cql2"
+)
+
+// searchParams are the parameters for an item search or a request for the items
+// in a collection.
+type searchParams struct {
+	collections []string
+	ids         []string
+	bbox        []float64
+	intersects  map[string]any
+	datetime    string
+	filter      cql2.Expression
+	limit       int
+	offset      int
+}
+
+// searchBody is the body of a POST search request.
+type searchBody struct {
+	Collections []string        `json:"collections"`
+	Ids         []string        `json:"ids"`
+	Bbox        []float64       `json:"bbox"`
+	Intersects  map[string]any  `json:"intersects"`
+	Datetime    string          `json:"datetime"`
+	Limit       int             `json:"limit"`
+	Token       json.RawMessage `json:"token"`
+	Filter      json.RawMessage `json:"filter"`
+	FilterLang  string          `json:"filter-lang"`
+}
+
+const (
+	filterLangText = "cql2-text"
+	filterLangJSON = "cql2-json"
+)
+
+func (s *Server) parseQuery(query url.Values) (*searchParams, error) {
+	params := &searchParams{
+		collections: splitList(query.Get("collections")),
+		ids:         splitList(query.Get("ids")),
+		datetime:    query.Get("datetime"),
+	}
+
+	if err := s.setPage(params, query.Get("limit"), query.Get("token")); err != nil {
+		return nil, err
+	}
+
+	if value := query.Get("bbox"); value != "" {
+		parts := strings.Split(value, ",")
+		params.bbox = make([]float64, len(parts))
+		for i, part := range parts {
+			number, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				return nil, badRequest("invalid bbox %q", value)
+			}
+			params.bbox[i] = number
+		}
+	}
+
+	if value := query.Get("intersects"); value != "" {
+		if err := json.Unmarshal([]byte(value), &params.intersects); err != nil {
+			return nil, badRequest("invalid intersects geometry: %s", err)
+		}
+	}
+
+	if value := query.Get("filter"); value != "" {
+		lang := query.Get("filter-lang")
+		if lang == "" {
+			lang = filterLangText
+		}
+		var expression cql2.Expression
+		var err error
+		switch lang {
+		case filterLangText:
+			expression, err = cql2.ParseText(value)
+		case filterLangJSON:
+			expression, err = cql2.ParseJSON([]byte(value))
+		default:
+			return nil, badRequest("unsupported filter-lang %q", lang)
+		}
+		if err != nil {
+			return nil, badRequest("invalid filter: %s", err)
+		}
+		params.filter = expression
+	}
+
+	return params, nil
+}
+
+func (s *Server) parseBody(r *http.Request) (*searchParams, error) {
+	body := &searchBody{}
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		return nil, badRequest("invalid search body: %s", err)
+	}
+
+	params := &searchParams{
+		collections: body.Collections,
+		ids:         body.Ids,
+		bbox:        body.Bbox,
+		intersects:  body.Intersects,
+		datetime:    body.Datetime,
+	}
+
+	limit := ""
+	if body.Limit != 0 {
+		limit = strconv.Itoa(body.Limit)
+	}
+	token := ""
+	if len(body.Token) > 0 {
+		if err := json.Unmarshal(body.Token, &token); err != nil {
+			token = string(body.Token)
+		}
+	}
+	if err := s.setPage(params, limit, token); err != nil {
+		return nil, err
+	}
+
+	if len(body.Filter) > 0 {
+		lang := body.FilterLang
+		if lang == "" {
+			lang = filterLangJSON
+		}
+		var expression cql2.Expression
+		var err error
+		switch lang {
+		case filterLangJSON:
+			expression, err = cql2.ParseJSON(body.Filter)
+		case filterLangText:
+			text := ""
+			if err := json.Unmarshal(body.Filter, &text); err != nil {
+				return nil, badRequest("expected a string filter for %s", filterLangText)
+			}
+			expression, err = cql2.ParseText(text)
+		default:
+			return nil, badRequest("unsupported filter-lang %q", lang)
+		}
+		if err != nil {
+			return nil, badRequest("invalid filter: %s", err)
+		}
+		params.filter = expression
+	}
+
+	return params, nil
+}
+
+// setPage sets the limit and offset from the limit and token parameters.
+func (s *Server) setPage(params *searchParams, limit string, token string) error {
+	params.limit = s.limit
+	if limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value < 1 {
+			return badRequest("invalid limit %q", limit)
+		}
+		params.limit = min(value, s.maxLimit)
+	}
+	if token != "" {
+		value, err := strconv.Atoi(token)
+		if err != nil || value < 0 {
+			return badRequest("invalid token %q", token)
+		}
+		params.offset = value
+	}
+	return nil
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	parts := strings.Split(value, ",")
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+	}
+	return parts
+}
+
+// filterExpression combines the spatial, temporal, and CQL2 parameters into a single filter.
+func (p *searchParams) filterExpression() (cql2.Expression, error) {
+	expressions := []cql2.Expression{}
+
+	if p.bbox != nil {
+		if len(p.bbox) != 4 && len(p.bbox) != 6 {
+			return nil, badRequest("expected bbox with 4 or 6 numbers")
+		}
+		expressions = append(expressions, &cql2.Operation{
+			Op:   "s_intersects",
+			Args: []cql2.Expression{&cql2.Property{Name: "geometry"}, &cql2.BBox{Values: p.bbox}},
+		})
+	}
+
+	if p.intersects != nil {
+		if p.bbox != nil {
+			return nil, badRequest("only one of bbox and intersects can be provided")
+		}
+		expressions = append(expressions, &cql2.Operation{
+			Op:   "s_intersects",
+			Args: []cql2.Expression{&cql2.Property{Name: "geometry"}, &cql2.Geometry{Value: p.intersects}},
+		})
+	}
+
+	if p.datetime != "" {
+		datetime, err := datetimeExpression(p.datetime)
+		if err != nil {
+			return nil, err
+		}
+		expressions = append(expressions, &cql2.Operation{
+			Op:   "t_intersects",
+			Args: []cql2.Expression{&cql2.Property{Name: "datetime"}, datetime},
+		})
+	}
+
+	if p.filter != nil {
+		expressions = append(expressions, p.filter)
+	}
+
+	switch len(expressions) {
+	case 0:
+		return nil, nil
+	case 1:
+		return expressions[0], nil
+	default:
+		return &cql2.Operation{Op: "and", Args: expressions}, nil
+	}
+}
+
+// datetimeExpression parses a single datetime or an interval with ".." or empty
+// open bounds.
+func datetimeExpression(value string) (cql2.Expression, error) {
+	parse := func(v string) (cql2.Expression, error) {
+		if v == "" || v == ".." {
+			return &cql2.Literal{Value: ".."}, nil
+		}
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return nil, badRequest("invalid datetime %q", value)
+		}
+		return &cql2.Timestamp{Value: t}, nil
+	}
+
+	startValue, endValue, isInterval := strings.Cut(value, "/")
+	if !isInterval {
+		if value == ".." {
+			return nil, badRequest("invalid datetime %q", value)
+		}
+		return parse(value)
+	}
+
+	start, err := parse(startValue)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parse(endValue)
+	if err != nil {
+		return nil, err
+	}
+	return &cql2.Interval{Start: start, End: end}, nil
+}
+
+// searchResult is a page of items.
+type searchResult struct {
+	items   []*indexedResource
+	matched int
+	next    int
+}
+
+func (s *Server) search(params *searchParams) (*searchResult, error) {
+	expression, err := params.filterExpression()
+	if err != nil {
+		return nil, err
+	}
+	var filter *cql2.Filter
+	if expression != nil {
+		filter = cql2.NewFilter(expression)
+	}
+
+	result := &searchResult{items: []*indexedResource{}}
+	for _, item := range s.items {
+		if len(params.collections) > 0 && !slices.Contains(params.collections, item.collection) {
+			continue
+		}
+		if len(params.ids) > 0 && !slices.Contains(params.ids, item.id) {
+			continue
+		}
+		if filter != nil {
+			match, err := filter.Match(item.resource)
+			if err != nil {
+				return nil, badRequest("failed to evaluate filter: %s", err)
+			}
+			if !match {
+				continue
+			}
+		}
+		result.matched += 1
+		if result.matched > params.offset && len(result.items) < params.limit {
+			result.items = append(result.items, item)
+		}
+	}
+
+	if end := params.offset + len(result.items); end < result.matched {
+		result.next = end
+	}
+	return result, nil
+}
+
+func (s *Server) writeItems(w http.ResponseWriter, base string, result *searchResult, links []map[string]any) {
+	features := make([]map[string]any, len(result.items))
+	for i, item := range result.items {
+		features[i] = s.renderItem(base, item)
+	}
+	writeJSON(w, http.StatusOK, mediaTypeGeoJSON, map[string]any{
+		"type":           "FeatureCollection",
+		"features":       features,
+		"links":          links,
+		"numberMatched":  result.matched,
+		"numberReturned": len(features),
+	})
+}
+
+// nextQuery returns the query string for the next page of results.
+func nextQuery(query url.Values, next int) string {
+	nextQuery := url.Values{}
+	for key, values := range query {
+		nextQuery[key] = values
+	}
+	nextQuery.Set("token", strconv.Itoa(next))
+	return nextQuery.Encode()
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	var params *searchParams
+	var err error
+	if r.Method == http.MethodPost {
+		params, err = s.parseBody(r)
+	} else {
+		params, err = s.parseQuery(r.URL.Query())
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := s.search(params)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	base := s.base(r)
+	self := base + "/search"
+	if r.URL.RawQuery != "" {
+		self += "?" + r.URL.RawQuery
+	}
+	links := []map[string]any{
+		link("self", self, mediaTypeGeoJSON),
+		link("root", base+"/", mediaTypeJSON),
+	}
+	if result.next > 0 {
+		if r.Method == http.MethodPost {
+			next := link("next", base+"/search", mediaTypeGeoJSON)
+			next["method"] = http.MethodPost
+			next["body"] = map[string]any{"token": strconv.Itoa(result.next)}
+			next["merge"] = true
+			links = append(links, next)
+		} else {
+			links = append(links, link("next", base+"/search?"+nextQuery(r.URL.Query(), result.next), mediaTypeGeoJSON))
+		}
+	}
+
+	s.writeItems(w, base, result, links)
+}
+
+func (s *Server) handleItems(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("collectionId")
+	if s.collection(id) == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no collection with id %q", id))
+		return
+	}
+
+	params, err := s.parseQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	params.collections = []string{id}
+
+	result, err := s.search(params)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	base := s.base(r)
+	collectionHref := base + "/collections/" + id
+	self := collectionHref + "/items"
+	if r.URL.RawQuery != "" {
+		self += "?" + r.URL.RawQuery
+	}
+	links := []map[string]any{
+		link("self", self, mediaTypeGeoJSON),
+		link("root", base+"/", mediaTypeJSON),
+		link("parent", collectionHref, mediaTypeJSON),
+		link("collection", collectionHref, mediaTypeJSON),
+	}
+	if result.next > 0 {
+		links = append(links, link("next", collectionHref+"/items?"+nextQuery(r.URL.Query(), result.next), mediaTypeGeoJSON))
+	}
+
+	s.writeItems(w, base, result, links)
+}
+
+// queryables returns a JSON schema for the item properties that can be used in filters.
+func queryables(items []*indexedResource) map[string]any {
+	properties := map[string]any{
+		"id":         map[string]any{"type": "string", "title": "Item ID"},
+		"collection": map[string]any{"type": "string", "title": "Collection ID"},
+		"geometry":   map[string]any{"$ref": "https://geojson.org/schema/Geometry.json"},
+		"datetime":   map[string]any{"type": "string", "format": "date-time"},
+	}
+	for _, item := range items {
+		itemProperties, _ := item.resource["properties"].(map[string]any)
+		for name, value := range itemProperties {
+			if _, exists := properties[name]; exists {
+				continue
+			}
+			var schemaType string
+			switch value.(type) {
+			case string:
+				schemaType = "string"
+			case float64:
+				schemaType = "number"
+			case bool:
+				schemaType = "boolean"
+			case []any:
+				schemaType = "array"
+			case map[string]any:
+				schemaType = "object"
+			default:
+				continue
+			}
+			properties[name] = map[string]any{"type": schemaType}
+		}
+	}
+	return properties
+}
@@ -0,0 +1,532 @@
+// Package server serves a static STAC catalog as a STAC API.
+//
+// The catalog is crawled once when the server is created and items are indexed
+// in memory.  The server implements the STAC API core, collections, features,
+// and item search conformance classes (with CQL2 filtering).  It is intended for
+// testing and demos rather than for serving large catalogs.
+package server
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/planetlabs/go-stac/crawler"
+	"github.com/planetlabs/go-stac/internal/normurl"
+)
+
+const (
+	mediaTypeJSON    = "application/json"
+	mediaTypeGeoJSON = "application/geo+json"
+	mediaTypeSchema  = "application/schema+json"
+	mediaTypeOpenAPI = "application/vnd.oai.openapi+json;version=3.0"
+
+	relQueryables = "http://www.opengis.net/def/rel/ogc/1.0/queryables"
+
+	apiVersion = "1.0.0"
+)
+
+// ConformsTo lists the conformance classes implemented by the server.
+var ConformsTo = []string{
+	"https://api.stacspec.org/v1.0.0/core",
+	"https://api.stacspec.org/v1.0.0/collections",
+	"https://api.stacspec.org/v1.0.0/ogcapi-features",
+	"https://api.stacspec.org/v1.0.0/item-search",
+	"https://api.stacspec.org/v1.0.0/item-search#filter",
+	"http://www.opengis.net/spec/ogcapi-features-1/1.0/conf/core",
+	"http://www.opengis.net/spec/ogcapi-features-1/1.0/conf/oas30",
+	"http://www.opengis.net/spec/ogcapi-features-1/1.0/conf/geojson",
+	"http://www.opengis.net/spec/ogcapi-features-3/1.0/conf/filter",
+	"http://www.opengis.net/spec/ogcapi-features-3/1.0/conf/features-filter",
+	"http://www.opengis.net/spec/cql2/1.0/conf/cql2-text",
+	"http://www.opengis.net/spec/cql2/1.0/conf/cql2-json",
+	"http://www.opengis.net/spec/cql2/1.0/conf/basic-cql2",
+	"http://www.opengis.net/spec/cql2/1.0/conf/advanced-comparison-operators",
+	"http://www.opengis.net/spec/cql2/1.0/conf/case-insensitive-comparison",
+	"http://www.opengis.net/spec/cql2/1.0/conf/basic-spatial-functions",
+	"http://www.opengis.net/spec/cql2/1.0/conf/temporal-functions",
+	"http://www.opengis.net/spec/cql2/1.0/conf/array-functions",
+	"http://www.opengis.net/spec/cql2/1.0/conf/property-property",
+	"http://www.opengis.net/spec/cql2/1.0/conf/arithmetic",
+}
+
+// Server is an http.Handler that serves a static catalog as a STAC API.
+type Server struct {
+	baseURL     string
+	limit       int
+	maxLimit    int
+	root        crawler.Resource
+	collections []*indexedResource
+	items       []*indexedResource
+	queryables  map[string]any
+	mux         *http.ServeMux
+}
+
+// indexedResource is a crawled resource and the location it was loaded from.
+type indexedResource struct {
+	id         string
+	collection string
+	location   *normurl.Locator
+	resource   crawler.Resource
+}
+
+// Options for the Server.
+type Options struct {
+	// Optional base URL for links in responses (e.g. "https://example.com/stac").  By
+	// default, the base URL is derived from the scheme and host of each request.  If
+	// the base URL includes a path, use http.StripPrefix when mounting the server.
+	BaseURL string
+
+	// Optional number of items per page if a request does not include a limit.  The
+	// default is 10.
+	Limit int
+
+	// Optional maximum number of items per page.  Requests for more items are limited
+	// to this number.  The default is 10000.
+	MaxLimit int
+}
+
+const (
+	defaultLimit    = 10
+	defaultMaxLimit = 10000
+)
+
+// New creates a new Server by crawling the catalog or collection at the entry
+// location.  Collections and items linked from the entry are indexed.  Items are
+// associated with a collection by their "collection" member or by a "collection"
+// or "parent" link to a crawled collection.
+func New(entry string, options ...*Options) (*Server, error) {
+	s := &Server{
+		limit:    defaultLimit,
+		maxLimit: defaultMaxLimit,
+	}
+	for _, opt := range options {
+		if opt.BaseURL != "" {
+			s.baseURL = strings.TrimSuffix(opt.BaseURL, "/")
+		}
+		if opt.Limit > 0 {
+			s.limit = opt.Limit
+		}
+		if opt.MaxLimit > 0 {
+			s.maxLimit = opt.MaxLimit
+		}
+	}
+
+	if err := s.index(entry); err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleLanding)
+	mux.HandleFunc("GET /conformance", s.handleConformance)
+	mux.HandleFunc("GET /api", s.handleServiceDesc)
+	mux.HandleFunc("GET /queryables", s.handleQueryables)
+	mux.HandleFunc("GET /collections", s.handleCollections)
+	mux.HandleFunc("GET /collections/{collectionId}", s.handleCollection)
+	mux.HandleFunc("GET /collections/{collectionId}/items", s.handleItems)
+	mux.HandleFunc("GET /collections/{collectionId}/items/{itemId}", s.handleItem)
+	mux.HandleFunc("GET /search", s.handleSearch)
+	mux.HandleFunc("POST /search", s.handleSearch)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no resource at %s", r.URL.Path))
+	})
+	s.mux = mux
+
+	return s, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) index(entry string) error {
+	mutex := &sync.Mutex{}
+	collections := map[string]*indexedResource{}
+	items := []*indexedResource{}
+
+	visitor := func(resource crawler.Resource, info *crawler.ResourceInfo) error {
+		loc, err := normurl.New(info.Location)
+		if err != nil {
+			return err
+		}
+		id, _ := resource["id"].(string)
+		indexed := &indexedResource{id: id, location: loc, resource: resource}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if info.Location == info.Entry {
+			s.root = resource
+		}
+		switch resource.Type() {
+		case crawler.Collection:
+			if _, exists := collections[id]; exists {
+				return fmt.Errorf("duplicate collection %q at %s", id, info.Location)
+			}
+			indexed.collection = id
+			collections[id] = indexed
+		case crawler.Item:
+			indexed.collection, _ = resource["collection"].(string)
+			items = append(items, indexed)
+		}
+		return nil
+	}
+
+	if err := crawler.Crawl(entry, visitor); err != nil {
+		return fmt.Errorf("failed to crawl %s: %w", entry, err)
+	}
+	if s.root == nil {
+		return fmt.Errorf("failed to load %s", entry)
+	}
+
+	collectionsByLocation := map[string]string{}
+	for id, collection := range collections {
+		collectionsByLocation[collection.location.String()] = id
+	}
+
+	seen := map[[2]string]bool{}
+	for _, item := range items {
+		if item.collection == "" {
+			item.collection = linkedCollection(item, collectionsByLocation)
+			if item.collection != "" {
+				// allow filtering on the collection of linked items
+				item.resource["collection"] = item.collection
+			}
+		}
+		key := [2]string{item.collection, item.id}
+		if seen[key] {
+			return fmt.Errorf("duplicate item %q in collection %q at %s", item.id, item.collection, item.location)
+		}
+		seen[key] = true
+	}
+
+	s.collections = slices.SortedFunc(func(yield func(*indexedResource) bool) {
+		for _, collection := range collections {
+			if !yield(collection) {
+				return
+			}
+		}
+	}, func(a, b *indexedResource) int {
+		return strings.Compare(a.id, b.id)
+	})
+
+	slices.SortFunc(items, func(a, b *indexedResource) int {
+		return cmp.Or(strings.Compare(a.collection, b.collection), strings.Compare(a.id, b.id))
+	})
+	s.items = items
+	s.queryables = queryables(items)
+
+	return nil
+}
+
+// linkedCollection returns the identifier of a crawled collection referenced by an
+// item's "collection" or "parent" link.
+func linkedCollection(item *indexedResource, collectionsByLocation map[string]string) string {
+	links := item.resource.Links()
+	for _, rel := range []string{"collection", "parent"} {
+		link := links.Rel(rel)
+		if link == nil {
+			continue
+		}
+		loc, err := item.location.Resolve(link["href"])
+		if err != nil {
+			continue
+		}
+		if id, ok := collectionsByLocation[loc.String()]; ok {
+			return id
+		}
+	}
+	return ""
+}
+
+func (s *Server) collection(id string) *indexedResource {
+	i, found := slices.BinarySearchFunc(s.collections, id, func(c *indexedResource, id string) int {
+		return strings.Compare(c.id, id)
+	})
+	if !found {
+		return nil
+	}
+	return s.collections[i]
+}
+
+// base returns the base URL for links in a response.
+func (s *Server) base(r *http.Request) string {
+	if s.baseURL != "" {
+		return s.baseURL
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+func link(rel string, href string, mediaType string) map[string]any {
+	return map[string]any{"rel": rel, "href": href, "type": mediaType}
+}
+
+// structuralRels are link relations that are replaced in served resources.
+var structuralRels = map[string]bool{
+	"self":       true,
+	"root":       true,
+	"parent":     true,
+	"collection": true,
+	"child":      true,
+	"item":       true,
+	"items":      true,
+}
+
+// render returns a copy of a resource with structural links replaced and other
+// relative links and asset hrefs resolved against the original location.
+func render(indexed *indexedResource, links ...map[string]any) map[string]any {
+	output := make(map[string]any, len(indexed.resource))
+	for key, value := range indexed.resource {
+		output[key] = value
+	}
+
+	for _, original := range indexed.resource.Links() {
+		if structuralRels[original["rel"]] {
+			continue
+		}
+		resolved := map[string]any{}
+		for key, value := range original {
+			resolved[key] = value
+		}
+		if loc, err := indexed.location.Resolve(original["href"]); err == nil {
+			resolved["href"] = loc.String()
+		}
+		links = append(links, resolved)
+	}
+	output["links"] = links
+
+	if assets, ok := indexed.resource["assets"].(map[string]any); ok {
+		resolvedAssets := make(map[string]any, len(assets))
+		for key, value := range assets {
+			asset, ok := value.(map[string]any)
+			if !ok {
+				resolvedAssets[key] = value
+				continue
+			}
+			resolvedAsset := make(map[string]any, len(asset))
+			for k, v := range asset {
+				resolvedAsset[k] = v
+			}
+			if href, ok := asset["href"].(string); ok {
+				if loc, err := indexed.location.Resolve(href); err == nil {
+					resolvedAsset["href"] = loc.String()
+				}
+			}
+			resolvedAssets[key] = resolvedAsset
+		}
+		output["assets"] = resolvedAssets
+	}
+
+	return output
+}
+
+func (s *Server) renderCollection(base string, collection *indexedResource) map[string]any {
+	href := base + "/collections/" + url.PathEscape(collection.id)
+	return render(collection,
+		link("self", href, mediaTypeJSON),
+		link("root", base+"/", mediaTypeJSON),
+		link("parent", base+"/", mediaTypeJSON),
+		link("items", href+"/items", mediaTypeGeoJSON),
+	)
+}
+
+func (s *Server) renderItem(base string, item *indexedResource) map[string]any {
+	if item.collection == "" {
+		output := render(item,
+			link("self", base+"/search?ids="+url.QueryEscape(item.id), mediaTypeGeoJSON),
+			link("root", base+"/", mediaTypeJSON),
+		)
+		return output
+	}
+
+	collectionHref := base + "/collections/" + url.PathEscape(item.collection)
+	output := render(item,
+		link("self", collectionHref+"/items/"+url.PathEscape(item.id), mediaTypeGeoJSON),
+		link("root", base+"/", mediaTypeJSON),
+		link("parent", collectionHref, mediaTypeJSON),
+		link("collection", collectionHref, mediaTypeJSON),
+	)
+	output["collection"] = item.collection
+	return output
+}
+
+func (s *Server) handleLanding(w http.ResponseWriter, r *http.Request) {
+	base := s.base(r)
+	links := []map[string]any{
+		link("self", base+"/", mediaTypeJSON),
+		link("root", base+"/", mediaTypeJSON),
+		link("conformance", base+"/conformance", mediaTypeJSON),
+		link("service-desc", base+"/api", mediaTypeOpenAPI),
+		link("data", base+"/collections", mediaTypeJSON),
+		link(relQueryables, base+"/queryables", mediaTypeSchema),
+		link("search", base+"/search", mediaTypeGeoJSON),
+	}
+	postSearch := link("search", base+"/search", mediaTypeGeoJSON)
+	postSearch["method"] = http.MethodPost
+	links = append(links, postSearch)
+	for _, collection := range s.collections {
+		child := link("child", base+"/collections/"+url.PathEscape(collection.id), mediaTypeJSON)
+		if title, ok := collection.resource["title"].(string); ok {
+			child["title"] = title
+		}
+		links = append(links, child)
+	}
+
+	landing := map[string]any{
+		"type":         "Catalog",
+		"stac_version": apiVersion,
+		"id":           s.root["id"],
+		"description":  s.root["description"],
+		"conformsTo":   ConformsTo,
+		"links":        links,
+	}
+	if title, ok := s.root["title"]; ok {
+		landing["title"] = title
+	}
+	writeJSON(w, http.StatusOK, mediaTypeJSON, landing)
+}
+
+func (s *Server) handleConformance(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, mediaTypeJSON, map[string]any{"conformsTo": ConformsTo})
+}
+
+func (s *Server) handleServiceDesc(w http.ResponseWriter, r *http.Request) {
+	title, _ := s.root["title"].(string)
+	if title == "" {
+		title, _ = s.root["id"].(string)
+	}
+	operation := func(summary string) map[string]any {
+		return map[string]any{
+			"summary":   summary,
+			"responses": map[string]any{"200": map[string]any{"description": summary}},
+		}
+	}
+	paths := map[string]any{
+		"/":                                      map[string]any{"get": operation("Landing page")},
+		"/conformance":                           map[string]any{"get": operation("Conformance classes")},
+		"/queryables":                            map[string]any{"get": operation("Queryables")},
+		"/collections":                           map[string]any{"get": operation("Collections")},
+		"/collections/{collectionId}":            map[string]any{"get": operation("Collection")},
+		"/collections/{collectionId}/items":      map[string]any{"get": operation("Items")},
+		"/collections/{collectionId}/items/{id}": map[string]any{"get": operation("Item")},
+		"/search": map[string]any{
+			"get":  operation("Item search"),
+			"post": operation("Item search"),
+		},
+	}
+	writeJSON(w, http.StatusOK, mediaTypeOpenAPI, map[string]any{
+		"openapi": "3.0.3",
+		"info":    map[string]any{"title": title, "version": apiVersion},
+		"servers": []any{map[string]any{"url": s.base(r)}},
+		"paths":   paths,
+	})
+}
+
+func (s *Server) handleQueryables(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, mediaTypeSchema, map[string]any{
+		"$schema":              "https://json-schema.org/draft/2019-09/schema",
+		"$id":                  s.base(r) + "/queryables",
+		"type":                 "object",
+		"title":                "Queryables",
+		"properties":           s.queryables,
+		"additionalProperties": true,
+	})
+}
+
+func (s *Server) handleCollections(w http.ResponseWriter, r *http.Request) {
+	base := s.base(r)
+	collections := make([]map[string]any, len(s.collections))
+	for i, collection := range s.collections {
+		collections[i] = s.renderCollection(base, collection)
+	}
+	writeJSON(w, http.StatusOK, mediaTypeJSON, map[string]any{
+		"collections": collections,
+		"links": []map[string]any{
+			link("self", base+"/collections", mediaTypeJSON),
+			link("root", base+"/", mediaTypeJSON),
+			link("parent", base+"/", mediaTypeJSON),
+		},
+	})
+}
+
+func (s *Server) handleCollection(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("collectionId")
+	collection := s.collection(id)
+	if collection == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no collection with id %q", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, mediaTypeJSON, s.renderCollection(s.base(r), collection))
+}
+
+func (s *Server) handleItem(w http.ResponseWriter, r *http.Request) {
+	collectionId := r.PathValue("collectionId")
+	if s.collection(collectionId) == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no collection with id %q", collectionId))
+		return
+	}
+	id := r.PathValue("itemId")
+	i, found := slices.BinarySearchFunc(s.items, [2]string{collectionId, id}, func(item *indexedResource, key [2]string) int {
+		return cmp.Or(strings.Compare(item.collection, key[0]), strings.Compare(item.id, key[1]))
+	})
+	if !found {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no item with id %q in collection %q", id, collectionId))
+		return
+	}
+	writeJSON(w, http.StatusOK, mediaTypeGeoJSON, s.renderItem(s.base(r), s.items[i]))
+}
+
+// requestError is an error caused by an invalid request.
+type requestError struct {
+	err error
+}
+
+func (e *requestError) Error() string {
+	return e.err.Error()
+}
+
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
+func badRequest(format string, args ...any) error {
+	return &requestError{err: fmt.Errorf(format, args...)}
+}
+
+func writeJSON(w http.ResponseWriter, status int, mediaType string, value any) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to encode response: %w", err))
+		return
+	}
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+// writeError writes an error response in the OGC API exception format.  Request
+// errors result in a 400 status regardless of the provided status.
+func writeError(w http.ResponseWriter, status int, err error) {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		status = http.StatusBadRequest
+	}
+	data, _ := json.Marshal(map[string]any{
+		"code":        strings.ReplaceAll(http.StatusText(status), " ", ""),
+		"description": err.Error(),
+	})
+	w.Header().Set("Content-Type", mediaTypeJSON)
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,409 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/planetlabs/go-stac/client"
+	"github.com/planetlabs/go-stac/crawler"
+	"github.com/planetlabs/go-stac/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, options ...*server.Options) *httptest.Server {
+	s, err := server.New("testdata/catalog.json", options...)
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func getJSON(t *testing.T, location string) (int, string, map[string]any) {
+	resp, err := http.Get(location)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	body := map[string]any{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return resp.StatusCode, resp.Header.Get("Content-Type"), body
+}
+
+func featureIds(body map[string]any) []string {
+	ids := []string{}
+	features, _ := body["features"].([]any)
+	for _, feature := range features {
+		ids = append(ids, feature.(map[string]any)["id"].(string))
+	}
+	return ids
+}
+
+func linkHref(body map[string]any, rel string) string {
+	links, _ := body["links"].([]any)
+	for _, l := range links {
+		link := l.(map[string]any)
+		if link["rel"] == rel {
+			return link["href"].(string)
+		}
+	}
+	return ""
+}
+
+func TestLanding(t *testing.T) {
+	ts := newTestServer(t)
+
+	status, contentType, body := getJSON(t, ts.URL+"/")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "application/json", contentType)
+	assert.Equal(t, "Catalog", body["type"])
+	assert.Equal(t, "test-catalog", body["id"])
+	assert.Equal(t, "Test Catalog", body["title"])
+	assert.Contains(t, body["conformsTo"], "https://api.stacspec.org/v1.0.0/item-search")
+	assert.Contains(t, body["conformsTo"], "https://api.stacspec.org/v1.0.0/item-search#filter")
+	assert.Contains(t, body["conformsTo"], "http://www.opengis.net/spec/cql2/1.0/conf/basic-spatial-functions")
+	assert.NotContains(t, body["conformsTo"], "http://www.opengis.net/spec/cql2/1.0/conf/basic-spatial-functions-plus")
+
+	assert.Equal(t, ts.URL+"/", linkHref(body, "self"))
+	assert.Equal(t, ts.URL+"/collections", linkHref(body, "data"))
+	assert.Equal(t, ts.URL+"/search", linkHref(body, "search"))
+	assert.Equal(t, ts.URL+"/conformance", linkHref(body, "conformance"))
+	assert.Equal(t, ts.URL+"/api", linkHref(body, "service-desc"))
+
+	status, _, conformance := getJSON(t, ts.URL+"/conformance")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, body["conformsTo"], conformance["conformsTo"])
+}
+
+func TestBaseURL(t *testing.T) {
+	ts := newTestServer(t, &server.Options{BaseURL: "https://example.com/stac/"})
+
+	_, _, body := getJSON(t, ts.URL+"/")
+	assert.Equal(t, "https://example.com/stac/", linkHref(body, "self"))
+	assert.Equal(t, "https://example.com/stac/collections", linkHref(body, "data"))
+}
+
+func TestCollections(t *testing.T) {
+	ts := newTestServer(t)
+
+	status, _, body := getJSON(t, ts.URL+"/collections")
+	require.Equal(t, http.StatusOK, status)
+
+	collections, _ := body["collections"].([]any)
+	require.Len(t, collections, 2)
+	first := collections[0].(map[string]any)
+	assert.Equal(t, "collection-a", first["id"])
+	assert.Equal(t, ts.URL+"/collections/collection-a", linkHref(first, "self"))
+	assert.Equal(t, ts.URL+"/collections/collection-a/items", linkHref(first, "items"))
+	assert.Empty(t, linkHref(first, "item"))
+
+	status, _, collection := getJSON(t, ts.URL+"/collections/collection-b")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "Collection B", collection["title"])
+
+	status, _, notFound := getJSON(t, ts.URL+"/collections/missing")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, "NotFound", notFound["code"])
+}
+
+func TestItems(t *testing.T) {
+	ts := newTestServer(t)
+
+	status, contentType, body := getJSON(t, ts.URL+"/collections/collection-a/items?limit=2")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "application/geo+json", contentType)
+	assert.Equal(t, "FeatureCollection", body["type"])
+	assert.Equal(t, []string{"a1", "a2"}, featureIds(body))
+	assert.Equal(t, float64(3), body["numberMatched"])
+
+	next := linkHref(body, "next")
+	require.NotEmpty(t, next)
+	_, _, nextBody := getJSON(t, next)
+	assert.Equal(t, []string{"a3"}, featureIds(nextBody))
+	assert.Empty(t, linkHref(nextBody, "next"))
+
+	// items linked to a collection without a "collection" member
+	_, _, linked := getJSON(t, ts.URL+"/collections/collection-b/items")
+	assert.Equal(t, []string{"b1"}, featureIds(linked))
+
+	status, _, item := getJSON(t, ts.URL+"/collections/collection-b/items/b1")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "collection-b", item["collection"])
+	assert.Equal(t, ts.URL+"/collections/collection-b/items/b1", linkHref(item, "self"))
+	assert.Equal(t, ts.URL+"/collections/collection-b", linkHref(item, "collection"))
+
+	assets := item["assets"].(map[string]any)
+	href := assets["data"].(map[string]any)["href"].(string)
+	expected, err := filepath.Abs("testdata/collection-b/b1/data.tif")
+	require.NoError(t, err)
+	assert.Equal(t, expected, href)
+
+	status, _, _ = getJSON(t, ts.URL+"/collections/collection-a/items/b1")
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestSearch(t *testing.T) {
+	ts := newTestServer(t)
+
+	cases := []struct {
+		name  string
+		query url.Values
+		ids   []string
+	}{
+		{name: "all", query: url.Values{}, ids: []string{"a1", "a2", "a3", "b1"}},
+		{name: "ids", query: url.Values{"ids": {"a2,b1"}}, ids: []string{"a2", "b1"}},
+		{name: "collections", query: url.Values{"collections": {"collection-b"}}, ids: []string{"b1"}},
+		{name: "bbox", query: url.Values{"bbox": {"-20,-20,5,5"}}, ids: []string{"a1", "b1"}},
+		{name: "intersects", query: url.Values{"intersects": {`{"type":"Point","coordinates":[10,10]}`}}, ids: []string{"a2"}},
+		{name: "datetime", query: url.Values{"datetime": {"2021-01-01T00:00:00Z"}}, ids: []string{"a1"}},
+		{name: "datetime interval", query: url.Values{"datetime": {"2021-02-01T00:00:00Z/2021-12-31T00:00:00Z"}}, ids: []string{"a2", "b1"}},
+		{name: "datetime open end", query: url.Values{"datetime": {"2021-12-01T00:00:00Z/.."}}, ids: []string{"a3"}},
+		{name: "datetime open start", query: url.Values{"datetime": {"/2021-03-15T00:00:00Z"}}, ids: []string{"a1", "b1"}},
+		{name: "filter", query: url.Values{"filter": {"eo:cloud_cover < 20"}}, ids: []string{"a1", "a3"}},
+		{name: "filter collection", query: url.Values{"filter": {"collection = 'collection-b'"}}, ids: []string{"b1"}},
+		{
+			name:  "filter json",
+			query: url.Values{"filter-lang": {"cql2-json"}, "filter": {`{"op":">","args":[{"property":"eo:cloud_cover"},10]}`}},
+			ids:   []string{"a2", "a3"},
+		},
+		{name: "combined", query: url.Values{"bbox": {"-1,-1,30,30"}, "datetime": {"2021-06-01T00:00:00Z/.."}, "filter": {"eo:cloud_cover < 20"}}, ids: []string{"a3"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			status, _, body := getJSON(t, ts.URL+"/search?"+c.query.Encode())
+			require.Equal(t, http.StatusOK, status, body["description"])
+			assert.Equal(t, c.ids, featureIds(body))
+		})
+	}
+}
+
+func TestSearchItemWithoutCollection(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "catalog.json"), map[string]any{
+		"stac_version": "1.0.0",
+		"type":         "Catalog",
+		"id":           "catalog",
+		"description":  "A catalog with an item that is not in a collection",
+		"links":        []any{map[string]any{"rel": "item", "href": "./item.json"}},
+	})
+	writeFile(t, filepath.Join(dir, "item.json"), map[string]any{
+		"stac_version": "1.0.0",
+		"type":         "Feature",
+		"id":           "item a&b",
+		"geometry":     map[string]any{"type": "Point", "coordinates": []float64{0, 0}},
+		"bbox":         []float64{0, 0, 0, 0},
+		"properties":   map[string]any{"datetime": "2021-01-01T00:00:00Z"},
+		"links":        []any{},
+		"assets":       map[string]any{},
+	})
+
+	s, err := server.New(filepath.Join(dir, "catalog.json"))
+	require.NoError(t, err)
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	_, _, body := getJSON(t, ts.URL+"/search")
+	features := body["features"].([]any)
+	require.Len(t, features, 1)
+	self := linkHref(features[0].(map[string]any), "self")
+	assert.Equal(t, ts.URL+"/search?ids=item+a%26b", self)
+
+	status, _, body := getJSON(t, self)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []string{"item a&b"}, featureIds(body))
+}
+
+func TestEscapedIds(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "catalog.json"), map[string]any{
+		"stac_version": "1.0.0",
+		"type":         "Catalog",
+		"id":           "catalog",
+		"description":  "A catalog with ids that need escaping",
+		"links":        []any{map[string]any{"rel": "child", "href": "./collection.json"}},
+	})
+	writeFile(t, filepath.Join(dir, "collection.json"), map[string]any{
+		"stac_version": "1.0.0",
+		"type":         "Collection",
+		"id":           "a b/c",
+		"description":  "A collection",
+		"license":      "CC-BY-4.0",
+		"extent": map[string]any{
+			"spatial":  map[string]any{"bbox": []any{[]any{-180, -90, 180, 90}}},
+			"temporal": map[string]any{"interval": []any{[]any{"2021-01-01T00:00:00Z", nil}}},
+		},
+		"links": []any{map[string]any{"rel": "item", "href": "./item.json"}},
+	})
+	writeFile(t, filepath.Join(dir, "item.json"), map[string]any{
+		"stac_version": "1.0.0",
+		"type":         "Feature",
+		"id":           "x?y#z",
+		"collection":   "a b/c",
+		"geometry":     map[string]any{"type": "Point", "coordinates": []float64{0, 0}},
+		"bbox":         []float64{0, 0, 0, 0},
+		"properties":   map[string]any{"datetime": "2021-01-01T00:00:00Z"},
+		"links":        []any{},
+		"assets":       map[string]any{},
+	})
+
+	s, err := server.New(filepath.Join(dir, "catalog.json"))
+	require.NoError(t, err)
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	_, _, landing := getJSON(t, ts.URL+"/")
+	child := linkHref(landing, "child")
+	assert.Equal(t, ts.URL+"/collections/a%20b%2Fc", child)
+
+	status, _, collection := getJSON(t, child)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "a b/c", collection["id"])
+	assert.Equal(t, child, linkHref(collection, "self"))
+
+	status, _, items := getJSON(t, linkHref(collection, "items"))
+	require.Equal(t, http.StatusOK, status)
+	features := items["features"].([]any)
+	require.Len(t, features, 1)
+	self := linkHref(features[0].(map[string]any), "self")
+	assert.Equal(t, child+"/items/x%3Fy%23z", self)
+
+	status, _, item := getJSON(t, self)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "x?y#z", item["id"])
+	assert.Equal(t, child, linkHref(item, "parent"))
+}
+
+func writeFile(t *testing.T, name string, value any) {
+	data, err := json.Marshal(value)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(name, data, 0644))
+}
+
+func TestSearchBadRequest(t *testing.T) {
+	ts := newTestServer(t)
+
+	queries := []url.Values{
+		{"limit": {"0"}},
+		{"limit": {"ten"}},
+		{"token": {"-1"}},
+		{"bbox": {"1,2,3"}},
+		{"bbox": {"a,b,c,d"}},
+		{"datetime": {"yesterday"}},
+		{"datetime": {".."}},
+		{"intersects": {"{"}},
+		{"filter": {"eo:cloud_cover <"}},
+		{"filter": {"id = 1"}, "filter-lang": {"sql"}},
+		{"filter": {"S_CROSSES(geometry, BBOX(0, 0, 1, 1))"}},
+	}
+
+	for _, query := range queries {
+		t.Run(query.Encode(), func(t *testing.T) {
+			status, _, body := getJSON(t, ts.URL+"/search?"+query.Encode())
+			assert.Equal(t, http.StatusBadRequest, status)
+			assert.Equal(t, "BadRequest", body["code"])
+			assert.NotEmpty(t, body["description"])
+		})
+	}
+}
+
+func TestSearchLimit(t *testing.T) {
+	ts := newTestServer(t, &server.Options{Limit: 1, MaxLimit: 2})
+
+	_, _, body := getJSON(t, ts.URL+"/search")
+	assert.Equal(t, []string{"a1"}, featureIds(body))
+
+	_, _, body = getJSON(t, ts.URL+"/search?limit=100")
+	assert.Equal(t, []string{"a1", "a2"}, featureIds(body))
+	assert.Equal(t, float64(2), body["numberReturned"])
+}
+
+func TestSearchClient(t *testing.T) {
+	ts := newTestServer(t)
+
+	ctx := context.Background()
+	c, err := client.New(ctx, ts.URL)
+	require.NoError(t, err)
+	assert.True(t, c.Conforms("item-search"))
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		t.Run(method, func(t *testing.T) {
+			ids := []string{}
+			params := &client.SearchParams{
+				Limit:      1,
+				Datetime:   "2021-01-01T00:00:00Z/..",
+				Method:     method,
+				Additional: map[string]any{"filter": "eo:cloud_cover > 1", "filter-lang": "cql2-text"},
+			}
+			for item, err := range c.Search(ctx, params) {
+				require.NoError(t, err)
+				ids = append(ids, item.Id)
+			}
+			assert.Equal(t, []string{"a1", "a2", "a3"}, ids)
+		})
+	}
+}
+
+func TestCrawl(t *testing.T) {
+	ts := newTestServer(t)
+
+	mutex := &sync.Mutex{}
+	visited := []string{}
+	visitor := func(resource crawler.Resource, info *crawler.ResourceInfo) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		visited = append(visited, strings.TrimPrefix(info.Location, ts.URL))
+		return nil
+	}
+
+	err := crawler.Crawl(ts.URL+"/", visitor)
+	require.NoError(t, err)
+
+	slices.Sort(visited)
+	assert.Equal(t, []string{
+		"/",
+		"/collections/collection-a",
+		"/collections/collection-a/items/a1",
+		"/collections/collection-a/items/a2",
+		"/collections/collection-a/items/a3",
+		"/collections/collection-b",
+		"/collections/collection-b/items/b1",
+	}, visited)
+}
+
+func TestQueryables(t *testing.T) {
+	ts := newTestServer(t)
+
+	status, contentType, body := getJSON(t, ts.URL+"/queryables")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "application/schema+json", contentType)
+	assert.Equal(t, "object", body["type"])
+
+	properties := body["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "number"}, properties["eo:cloud_cover"])
+	assert.Equal(t, map[string]any{"type": "string"}, properties["platform"])
+	assert.Contains(t, properties, "datetime")
+}
+
+func TestNotFound(t *testing.T) {
+	ts := newTestServer(t)
+
+	status, contentType, body := getJSON(t, ts.URL+"/missing")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, "application/json", contentType)
+	assert.Equal(t, "NotFound", body["code"])
+}
+
+func TestNewMissing(t *testing.T) {
+	_, err := server.New("testdata/missing.json")
+	assert.Error(t, err)
+}
@@ -0,0 +1,512 @@
+// Package summary generates collection summaries from item properties.
+//
+// A Summarizer accumulates the properties of items and produces a summaries
+// object for a collection.  Each property is summarized according to a Rule:
+// as a list of distinct values, as a range object with minimum and maximum
+// values, or as a JSON Schema.  By default, low-cardinality strings are
+// summarized as distinct values, numbers and datetimes are summarized as
+// ranges, and well-known fields (like eo:cloud_cover and gsd) use the rules in
+// DefaultRules.
+package summary
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/planetlabs/go-stac"
+)
+
+// Kind determines how a property is summarized.
+type Kind string
+
+const (
+	// Auto summarizes strings, booleans, and arrays of strings as distinct
+	// values (if there are no more than the configured maximum number of values)
+	// and numbers and datetimes as ranges.  Other properties are not summarized.
+	Auto Kind = ""
+
+	// Values summarizes a property as a list of distinct values.  Array values
+	// contribute each of their elements.
+	Values Kind = "values"
+
+	// Range summarizes a property as an object with minimum and maximum values.
+	Range Kind = "range"
+
+	// Schema summarizes a property as a JSON Schema.
+	Schema Kind = "schema"
+
+	// Skip excludes a property from the summaries.
+	Skip Kind = "skip"
+)
+
+// Kinds lists the supported kinds of summaries.
+var Kinds = []Kind{Auto, Values, Range, Schema, Skip}
+
+// Rule configures the summary for a single property.
+type Rule struct {
+	// Kind determines how the property is summarized.
+	Kind Kind
+
+	// Schema is used as the summary for properties with the Schema kind.  If
+	// nil, a schema is derived from the property values.
+	Schema map[string]any
+}
+
+// DefaultRules are used for properties without a rule in the options.
+var DefaultRules = map[string]*Rule{
+	"datetime":                              {Kind: Range},
+	"start_datetime":                        {Kind: Range},
+	"end_datetime":                          {Kind: Range},
+	"created":                               {Kind: Skip},
+	"updated":                               {Kind: Skip},
+	"title":                                 {Kind: Skip},
+	"description":                           {Kind: Skip},
+	"gsd":                                   {Kind: Values},
+	"platform":                              {Kind: Values},
+	"constellation":                         {Kind: Values},
+	"instruments":                           {Kind: Values},
+	"eo:cloud_cover":                        {Kind: Range},
+	"eo:snow_cover":                         {Kind: Range},
+	"view:off_nadir":                        {Kind: Range},
+	"view:incidence_angle":                  {Kind: Range},
+	"view:azimuth":                          {Kind: Range},
+	"view:sun_azimuth":                      {Kind: Range},
+	"view:sun_elevation":                    {Kind: Range},
+	"proj:epsg":                             {Kind: Values},
+	"proj:code":                             {Kind: Values},
+	"sat:orbit_state":                       {Kind: Values},
+	"sat:platform_international_designator": {Kind: Values},
+	"sat:absolute_orbit":                    {Kind: Range},
+	"sat:relative_orbit":                    {Kind: Range},
+	"sat:anx_datetime":                      {Kind: Range},
+	"processing:level":                      {Kind: Values},
+	"processing:facility":                   {Kind: Values},
+	"processing:version":                    {Kind: Values},
+	"processing:datetime":                   {Kind: Range},
+	"processing:lineage":                    {Kind: Skip},
+	"processing:software":                   {Kind: Skip},
+	"processing:expression":                 {Kind: Skip},
+	"sar:instrument_mode":                   {Kind: Values},
+	"sar:polarizations":                     {Kind: Values},
+	"published":                             {Kind: Skip},
+	"expires":                               {Kind: Skip},
+	"unpublished":                           {Kind: Skip},
+	"version":                               {Kind: Skip},
+	"deprecated":                            {Kind: Skip},
+	"experimental":                          {Kind: Skip},
+	"sci:doi":                               {Kind: Skip},
+	"sci:citation":                          {Kind: Skip},
+	"sci:publications":                      {Kind: Skip},
+}
+
+// Options for the summarizer.
+type Options struct {
+	// Rules configure the summaries for individual properties.  These take
+	// precedence over the DefaultRules.
+	Rules map[string]*Rule
+
+	// MaxValues is the maximum number of distinct values for a property to be
+	// summarized as a list of values with the Auto kind (default 10).
+	MaxValues int
+}
+
+// Summarizer accumulates item properties and generates summaries.  It is safe
+// for concurrent use.
+type Summarizer struct {
+	rules     map[string]*Rule
+	maxValues int
+	mutex     sync.Mutex
+	fields    map[string]*field
+}
+
+// New creates a new summarizer.
+func New(options ...*Options) *Summarizer {
+	s := &Summarizer{
+		rules:     map[string]*Rule{},
+		maxValues: 10,
+		fields:    map[string]*field{},
+	}
+	for _, opt := range options {
+		for name, rule := range opt.Rules {
+			s.rules[name] = rule
+		}
+		if opt.MaxValues > 0 {
+			s.maxValues = opt.MaxValues
+		}
+	}
+	return s
+}
+
+func (s *Summarizer) rule(name string) *Rule {
+	if rule, ok := s.rules[name]; ok && rule != nil {
+		return rule
+	}
+	if rule, ok := DefaultRules[name]; ok {
+		return rule
+	}
+	return &Rule{Kind: Auto}
+}
+
+// Add accumulates the properties of an item.
+func (s *Summarizer) Add(properties map[string]any) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for name, value := range properties {
+		rule := s.rule(name)
+		if rule.Kind == Skip || value == nil {
+			continue
+		}
+		f, ok := s.fields[name]
+		if !ok {
+			f = &field{values: map[string]any{}}
+			// distinct values are only limited when the kind is Auto
+			if rule.Kind == Auto {
+				f.limit = s.maxValues
+			}
+			s.fields[name] = f
+		}
+		f.add(value)
+	}
+}
+
+// AddItem accumulates the properties of an item, including any properties
+// provided by extensions.
+func (s *Summarizer) AddItem(item *stac.Item) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("failed to encode item %s: %w", item.Id, err)
+	}
+	value := &struct {
+		Properties map[string]any `json:"properties"`
+	}{}
+	if err := json.Unmarshal(data, value); err != nil {
+		return fmt.Errorf("failed to decode item %s: %w", item.Id, err)
+	}
+	s.Add(value.Properties)
+	return nil
+}
+
+// Summaries returns the summaries for the accumulated properties.
+func (s *Summarizer) Summaries() map[string]any {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	summaries := map[string]any{}
+	for name, f := range s.fields {
+		rule := s.rule(name)
+		var summary any
+		switch rule.Kind {
+		case Auto:
+			summary = f.auto()
+		case Values:
+			summary = f.distinct()
+		case Range:
+			summary = f.rangeObject()
+		case Schema:
+			if rule.Schema != nil {
+				summary = rule.Schema
+			} else {
+				summary = f.schema()
+			}
+		}
+		if summary != nil {
+			summaries[name] = summary
+		}
+	}
+	return summaries
+}
+
+// Items generates summaries for a list of items.
+func Items(items []*stac.Item, options ...*Options) (map[string]any, error) {
+	s := New(options...)
+	for _, item := range items {
+		if err := s.AddItem(item); err != nil {
+			return nil, err
+		}
+	}
+	return s.Summaries(), nil
+}
+
+const (
+	typeString   = "string"
+	typeDatetime = "datetime"
+	typeNumber   = "number"
+	typeBoolean  = "boolean"
+	typeArray    = "array"
+	typeObject   = "object"
+)
+
+// field accumulates the values of a single property.
+type field struct {
+	types map[string]bool
+
+	// values are the distinct scalar values (or array elements) keyed by their
+	// JSON encoding
+	values   map[string]any
+	limit    int
+	overflow bool
+
+	// elementTypes are the types of array elements
+	elementTypes map[string]bool
+
+	minNumber, maxNumber float64
+	hasNumber            bool
+	integer              bool
+
+	minString, maxString string
+	hasString            bool
+
+	minTime, maxTime         time.Time
+	minDatetime, maxDatetime string
+	hasDatetime              bool
+}
+
+func typeOf(value any) string {
+	switch v := value.(type) {
+	case string:
+		if _, err := time.Parse(time.RFC3339, v); err == nil {
+			return typeDatetime
+		}
+		return typeString
+	case float64, float32, int, int64, int32, uint, uint64, uint32:
+		return typeNumber
+	case bool:
+		return typeBoolean
+	case []any:
+		return typeArray
+	default:
+		return typeObject
+	}
+}
+
+func (f *field) add(value any) {
+	if f.types == nil {
+		f.types = map[string]bool{}
+		f.elementTypes = map[string]bool{}
+		f.integer = true
+	}
+	valueType := typeOf(value)
+	f.types[valueType] = true
+
+	switch valueType {
+	case typeArray:
+		for _, element := range value.([]any) {
+			elementType := typeOf(element)
+			f.elementTypes[elementType] = true
+			if elementType != typeArray && elementType != typeObject {
+				f.addScalar(elementType, element)
+			}
+		}
+	case typeObject:
+		return
+	default:
+		f.addScalar(valueType, value)
+	}
+}
+
+func (f *field) addScalar(valueType string, value any) {
+	if !f.overflow {
+		data, err := json.Marshal(value)
+		if err == nil {
+			key := string(data)
+			if _, ok := f.values[key]; !ok {
+				if f.limit > 0 && len(f.values) >= f.limit {
+					f.overflow = true
+					f.values = map[string]any{}
+				} else {
+					f.values[key] = value
+				}
+			}
+		}
+	}
+
+	switch valueType {
+	case typeNumber:
+		number := toFloat(value)
+		if number != math.Trunc(number) {
+			f.integer = false
+		}
+		if !f.hasNumber || number < f.minNumber {
+			f.minNumber = number
+		}
+		if !f.hasNumber || number > f.maxNumber {
+			f.maxNumber = number
+		}
+		f.hasNumber = true
+	case typeDatetime:
+		str := value.(string)
+		t, _ := time.Parse(time.RFC3339, str)
+		if !f.hasDatetime || t.Before(f.minTime) {
+			f.minTime, f.minDatetime = t, str
+		}
+		if !f.hasDatetime || t.After(f.maxTime) {
+			f.maxTime, f.maxDatetime = t, str
+		}
+		f.hasDatetime = true
+	case typeString:
+		str := value.(string)
+		if !f.hasString || str < f.minString {
+			f.minString = str
+		}
+		if !f.hasString || str > f.maxString {
+			f.maxString = str
+		}
+		f.hasString = true
+	}
+}
+
+func toFloat(value any) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case uint:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case uint32:
+		return float64(v)
+	}
+	return math.NaN()
+}
+
+// scalarType returns the single type of the values (or array elements) or an
+// empty string if there is more than one type.
+func (f *field) scalarType() string {
+	types := f.types
+	if f.types[typeArray] {
+		if len(f.types) > 1 {
+			return ""
+		}
+		types = f.elementTypes
+	}
+	if len(types) != 1 {
+		return ""
+	}
+	for t := range types {
+		return t
+	}
+	return ""
+}
+
+func (f *field) auto() any {
+	switch f.scalarType() {
+	case typeNumber, typeDatetime:
+		if f.types[typeArray] {
+			return nil
+		}
+		return f.rangeObject()
+	case typeString, typeBoolean:
+		if f.overflow {
+			return nil
+		}
+		return f.distinct()
+	}
+	return nil
+}
+
+// distinct returns the sorted list of distinct values.
+func (f *field) distinct() any {
+	if f.overflow || len(f.values) == 0 {
+		return nil
+	}
+	values := make([]any, 0, len(f.values))
+	for _, value := range f.values {
+		values = append(values, value)
+	}
+	sort.SliceStable(values, func(i, j int) bool {
+		return less(values[i], values[j])
+	})
+	return values
+}
+
+// less orders values by type (booleans, numbers, then strings) and then by value.
+func less(a, b any) bool {
+	rank := func(value any) int {
+		switch value.(type) {
+		case bool:
+			return 0
+		case string:
+			return 2
+		default:
+			return 1
+		}
+	}
+	if ra, rb := rank(a), rank(b); ra != rb {
+		return ra < rb
+	}
+	switch va := a.(type) {
+	case bool:
+		return !va && b.(bool)
+	case string:
+		return va < b.(string)
+	default:
+		return toFloat(a) < toFloat(b)
+	}
+}
+
+// rangeObject returns an object with minimum and maximum values.
+func (f *field) rangeObject() any {
+	switch f.scalarType() {
+	case typeNumber:
+		return map[string]any{"minimum": f.minNumber, "maximum": f.maxNumber}
+	case typeDatetime:
+		return map[string]any{"minimum": f.minDatetime, "maximum": f.maxDatetime}
+	case typeString:
+		return map[string]any{"minimum": f.minString, "maximum": f.maxString}
+	}
+	return nil
+}
+
+// schema derives a JSON Schema from the values.
+func (f *field) schema() any {
+	scalarType := f.scalarType()
+	var schema map[string]any
+	switch scalarType {
+	case typeNumber:
+		schema = map[string]any{"type": "number", "minimum": f.minNumber, "maximum": f.maxNumber}
+		if f.integer {
+			schema["type"] = "integer"
+		}
+	case typeDatetime:
+		schema = map[string]any{"type": "string", "format": "date-time"}
+	case typeString:
+		schema = map[string]any{"type": "string"}
+		if values := f.distinct(); values != nil {
+			schema["enum"] = values
+		}
+	case typeBoolean:
+		schema = map[string]any{"type": "boolean"}
+	case typeObject:
+		schema = map[string]any{"type": "object"}
+	default:
+		types := []string{}
+		for t := range f.types {
+			if t == typeDatetime {
+				t = typeString
+			}
+			if !slices.Contains(types, t) {
+				types = append(types, t)
+			}
+		}
+		slices.Sort(types)
+		return map[string]any{"type": types}
+	}
+	if f.types[typeArray] {
+		return map[string]any{"type": "array", "items": schema}
+	}
+	return schema
+}
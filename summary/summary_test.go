@@ -0,0 +1,135 @@
+package summary_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/summary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func properties(t *testing.T, data string) map[string]any {
+	value := map[string]any{}
+	require.NoError(t, json.Unmarshal([]byte(data), &value))
+	return value
+}
+
+func TestSummaries(t *testing.T) {
+	s := summary.New()
+	s.Add(properties(t, `{
+		"datetime": "2024-01-02T00:00:00Z",
+		"platform": "sat-b",
+		"instruments": ["cam"],
+		"gsd": 3,
+		"eo:cloud_cover": 12.5,
+		"quality": "good",
+		"processed": true,
+		"geometry_id": "a",
+		"extra": {"nested": true},
+		"proj:shape": [10, 10]
+	}`))
+	s.Add(properties(t, `{
+		"datetime": "2024-01-01T12:00:00-06:00",
+		"platform": "sat-a",
+		"instruments": ["cam", "lidar"],
+		"gsd": 5,
+		"eo:cloud_cover": 0,
+		"quality": "good",
+		"processed": false,
+		"geometry_id": "b",
+		"proj:shape": [20, 20]
+	}`))
+
+	assert.Equal(t, map[string]any{
+		"datetime":       map[string]any{"minimum": "2024-01-01T12:00:00-06:00", "maximum": "2024-01-02T00:00:00Z"},
+		"platform":       []any{"sat-a", "sat-b"},
+		"instruments":    []any{"cam", "lidar"},
+		"gsd":            []any{float64(3), float64(5)},
+		"eo:cloud_cover": map[string]any{"minimum": float64(0), "maximum": 12.5},
+		"quality":        []any{"good"},
+		"processed":      []any{false, true},
+		"geometry_id":    []any{"a", "b"},
+	}, s.Summaries())
+}
+
+func TestMaxValues(t *testing.T) {
+	s := summary.New(&summary.Options{MaxValues: 2, Rules: map[string]*summary.Rule{
+		"tile": {Kind: summary.Values},
+	}})
+	for i := range 3 {
+		s.Add(map[string]any{"id": fmt.Sprintf("item-%d", i), "tile": fmt.Sprintf("tile-%d", i), "kind": "scene"})
+	}
+
+	assert.Equal(t, map[string]any{
+		"tile": []any{"tile-0", "tile-1", "tile-2"},
+		"kind": []any{"scene"},
+	}, s.Summaries())
+}
+
+func TestRules(t *testing.T) {
+	custom := map[string]any{"type": "string", "pattern": "^[A-Z]+$"}
+	s := summary.New(&summary.Options{Rules: map[string]*summary.Rule{
+		"gsd":            {Kind: summary.Range},
+		"eo:cloud_cover": {Kind: summary.Skip},
+		"name":           {Kind: summary.Schema, Schema: custom},
+		"count":          {Kind: summary.Schema},
+		"band":           {Kind: summary.Schema},
+		"created":        {Kind: summary.Range},
+	}})
+	s.Add(map[string]any{"gsd": 3.0, "eo:cloud_cover": 10.0, "name": "A", "count": 2.0, "band": []any{"red"}, "created": "2024-01-01T00:00:00Z"})
+	s.Add(map[string]any{"gsd": 0.5, "eo:cloud_cover": 20.0, "name": "B", "count": 7.0, "band": []any{"green", "red"}, "created": "2023-01-01T00:00:00Z"})
+
+	assert.Equal(t, map[string]any{
+		"gsd":     map[string]any{"minimum": 0.5, "maximum": float64(3)},
+		"name":    custom,
+		"count":   map[string]any{"type": "integer", "minimum": float64(2), "maximum": float64(7)},
+		"band":    map[string]any{"type": "array", "items": map[string]any{"type": "string", "enum": []any{"green", "red"}}},
+		"created": map[string]any{"minimum": "2023-01-01T00:00:00Z", "maximum": "2024-01-01T00:00:00Z"},
+	}, s.Summaries())
+}
+
+func TestMixedTypes(t *testing.T) {
+	s := summary.New(&summary.Options{Rules: map[string]*summary.Rule{
+		"mixed": {Kind: summary.Schema},
+	}})
+	s.Add(map[string]any{"mixed": "a", "auto": "a"})
+	s.Add(map[string]any{"mixed": 1.0, "auto": 1.0})
+
+	assert.Equal(t, map[string]any{
+		"mixed": map[string]any{"type": []string{"number", "string"}},
+	}, s.Summaries())
+}
+
+func TestConcurrentAdd(t *testing.T) {
+	s := summary.New()
+	wg := &sync.WaitGroup{}
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Add(map[string]any{"eo:cloud_cover": float64(i)})
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, map[string]any{
+		"eo:cloud_cover": map[string]any{"minimum": float64(0), "maximum": float64(19)},
+	}, s.Summaries())
+}
+
+func TestItems(t *testing.T) {
+	items := []*stac.Item{
+		{Id: "a", Properties: map[string]any{"datetime": "2024-01-01T00:00:00Z", "platform": "a"}},
+		{Id: "b", Properties: map[string]any{"datetime": "2024-02-01T00:00:00Z", "platform": "b"}},
+	}
+	summaries, err := summary.Items(items)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"datetime": map[string]any{"minimum": "2024-01-01T00:00:00Z", "maximum": "2024-02-01T00:00:00Z"},
+		"platform": []any{"a", "b"},
+	}, summaries)
+}
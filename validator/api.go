@@ -0,0 +1,552 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/planetlabs/go-stac/crawler"
+	"github.com/planetlabs/go-stac/internal/normurl"
+)
+
+// Conformance class names used in a ConformanceReport.
+const (
+	ClassCore        = "core"
+	ClassFeatures    = "features"
+	ClassItemSearch  = "item-search"
+	ClassCollections = "collections"
+	ClassChildren    = "children"
+	ClassFilter      = "filter"
+	ClassSort        = "sort"
+	ClassFields      = "fields"
+)
+
+var (
+	stacConformancePattern = regexp.MustCompile(`^https://api\.stacspec\.org/v1\.[^/]+/(.+)$`)
+	ogcFilterPattern       = regexp.MustCompile(`^http://www\.opengis\.net/spec/ogcapi-features-3/1\.\d+/conf/filter$`)
+)
+
+// conformanceClassName returns the name of a conformance class URI (or the empty string if
+// the URI does not identify a supported class).
+func conformanceClassName(uri string) string {
+	if ogcFilterPattern.MatchString(uri) {
+		return ClassFilter
+	}
+	match := stacConformancePattern.FindStringSubmatch(uri)
+	if match == nil {
+		return ""
+	}
+	suffix := match[1]
+	if _, fragment, ok := strings.Cut(suffix, "#"); ok {
+		suffix = fragment
+	}
+	switch suffix {
+	case "core":
+		return ClassCore
+	case "ogcapi-features":
+		return ClassFeatures
+	case "item-search":
+		return ClassItemSearch
+	case "collections":
+		return ClassCollections
+	case "children":
+		return ClassChildren
+	case "filter":
+		return ClassFilter
+	case "sort":
+		return ClassSort
+	case "fields":
+		return ClassFields
+	}
+	return ""
+}
+
+// ConformanceReport describes how well a STAC API implements the conformance classes
+// declared on its landing page.
+type ConformanceReport struct {
+	// Entry is the URL of the landing page.
+	Entry string
+
+	// ConformsTo lists the conformance classes declared on the landing page.
+	ConformsTo []string
+
+	// Classes includes a report for each supported conformance class.
+	Classes []*ClassReport
+}
+
+// Passed is true if all checks passed.
+func (r *ConformanceReport) Passed() bool {
+	for _, class := range r.Classes {
+		if !class.Passed() {
+			return false
+		}
+	}
+	return true
+}
+
+// ClassReport includes the checks for a single conformance class.
+type ClassReport struct {
+	// Name is a short name for the conformance class (e.g. "item-search").
+	Name string
+
+	// ConformanceClasses lists the declared conformance class URIs with this name.
+	ConformanceClasses []string
+
+	// Checks made for the conformance class.
+	Checks []*Check
+}
+
+// Passed is true if all checks for the class passed.
+func (r *ClassReport) Passed() bool {
+	for _, check := range r.Checks {
+		if check.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Check is a single assertion about an API endpoint.
+type Check struct {
+	// Description of what was checked.
+	Description string
+
+	// Location is the URL of the endpoint that was checked.
+	Location string
+
+	// Err is nil if the check passed.
+	Err error
+
+	// Skipped is the reason a check could not be made (e.g. because there was nothing
+	// to check).  It is empty if the check was made.
+	Skipped string
+}
+
+type apiResponse struct {
+	location    *normurl.Locator
+	contentType string
+	body        map[string]any
+}
+
+func (r *apiResponse) links() crawler.Links {
+	return crawler.Resource(r.body).Links()
+}
+
+type classChecker struct {
+	ctx         context.Context
+	validator   *Validator
+	report      *ClassReport
+	landing     *apiResponse
+	collections *sync.Map
+}
+
+func (c *classChecker) check(description string, location string, err error) bool {
+	c.report.Checks = append(c.report.Checks, &Check{Description: description, Location: location, Err: err})
+	return err == nil
+}
+
+func (c *classChecker) skip(description string, location string, reason string) {
+	c.report.Checks = append(c.report.Checks, &Check{Description: description, Location: location, Skipped: reason})
+}
+
+// resolveHref returns the URL of a link relative to a base location (or the base
+// location if the href cannot be resolved).
+func resolveHref(base *normurl.Locator, href string) string {
+	loc, err := base.Resolve(href)
+	if err != nil {
+		return base.String()
+	}
+	return loc.String()
+}
+
+// ValidateAPI checks that a STAC API implements the conformance classes declared on its
+// landing page.  For each supported class, the required endpoints are requested and the
+// responses are checked for the expected content type, pagination, and schema-valid bodies.
+//
+// An error is only returned if the landing page cannot be fetched.  Failed checks are
+// included in the returned report.
+func (v *Validator) ValidateAPI(ctx context.Context, entry string) (*ConformanceReport, error) {
+	loc, err := normurl.New(entry)
+	if err != nil {
+		return nil, err
+	}
+	if loc.IsFilepath() {
+		return nil, fmt.Errorf("expected a URL for the API landing page, got %s", entry)
+	}
+
+	landing, err := v.request(ctx, http.MethodGet, loc, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ConformanceReport{
+		Entry:      entry,
+		ConformsTo: crawler.Resource(landing.body).ConformsTo(),
+	}
+
+	classes := map[string]*ClassReport{}
+	for _, uri := range report.ConformsTo {
+		name := conformanceClassName(uri)
+		if name == "" {
+			continue
+		}
+		class, ok := classes[name]
+		if !ok {
+			class = &ClassReport{Name: name}
+			classes[name] = class
+		}
+		class.ConformanceClasses = append(class.ConformanceClasses, uri)
+	}
+
+	// core is required for all STAC APIs
+	if _, ok := classes[ClassCore]; !ok {
+		classes[ClassCore] = &ClassReport{Name: ClassCore}
+	}
+
+	checks := map[string]func(*classChecker){
+		ClassCore:        checkCore,
+		ClassFeatures:    checkFeatures,
+		ClassItemSearch:  checkItemSearch,
+		ClassCollections: checkCollections,
+		ClassChildren:    checkChildren,
+		ClassFilter:      checkFilter,
+		ClassSort:        checkSort,
+		ClassFields:      checkFields,
+	}
+	collections := &sync.Map{}
+	for _, name := range []string{ClassCore, ClassFeatures, ClassItemSearch, ClassCollections, ClassChildren, ClassFilter, ClassSort, ClassFields} {
+		class, ok := classes[name]
+		if !ok {
+			continue
+		}
+		checks[name](&classChecker{ctx: ctx, validator: v, report: class, landing: landing, collections: collections})
+		report.Classes = append(report.Classes, class)
+	}
+
+	return report, nil
+}
+
+func (v *Validator) request(ctx context.Context, method string, loc *normurl.Locator, body any) (*apiResponse, error) {
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, loc.String(), reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response for %s %s: %d", method, loc, resp.StatusCode)
+	}
+
+	data := map[string]any{}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", loc, err)
+	}
+
+	return &apiResponse{location: loc, contentType: resp.Header.Get("Content-Type"), body: data}, nil
+}
+
+func expectContentType(resp *apiResponse, expected ...string) error {
+	mediaType, _, err := mime.ParseMediaType(resp.contentType)
+	if err != nil {
+		return fmt.Errorf("invalid content type %q: %w", resp.contentType, err)
+	}
+	if !slices.Contains(expected, mediaType) {
+		return fmt.Errorf("unexpected content type %q, expected %s", mediaType, strings.Join(expected, " or "))
+	}
+	return nil
+}
+
+// follow resolves and requests the href of a link.
+func (c *classChecker) follow(base *apiResponse, link crawler.Link, query map[string]string) (*apiResponse, error) {
+	loc, err := base.location.Resolve(link["href"])
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range query {
+		loc.SetQueryParam(key, value)
+	}
+	return c.validator.request(c.ctx, http.MethodGet, loc, nil)
+}
+
+// get requests a linked endpoint and checks the content type of the response.
+func (c *classChecker) get(description string, rel string, query map[string]string, contentTypes ...string) *apiResponse {
+	link := c.landing.links().Rel(rel)
+	if link == nil {
+		c.check(description, c.landing.location.String(), fmt.Errorf("missing %q link on landing page", rel))
+		return nil
+	}
+	resp, err := c.follow(c.landing, link, query)
+	if err != nil {
+		c.check(description, resolveHref(c.landing.location, link["href"]), err)
+		return nil
+	}
+	if !c.check(description, resp.location.String(), expectContentType(resp, contentTypes...)) {
+		return nil
+	}
+	return resp
+}
+
+func (c *classChecker) validateResource(value any, location string, expected ...crawler.ResourceType) error {
+	data, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("expected an object, got %T", value)
+	}
+	resource := crawler.Resource(data)
+	if len(expected) > 0 && !slices.Contains(expected, resource.Type()) {
+		return fmt.Errorf("unexpected resource type %q", resource.Type())
+	}
+	if selfLink := resource.Links().Rel("self"); selfLink != nil {
+		if base, err := normurl.New(location); err == nil {
+			location = resolveHref(base, selfLink["href"])
+		}
+	}
+	err := c.validator.validate(c.collections, resource, &crawler.ResourceInfo{Location: location, Entry: c.landing.location.String()})
+	if errors.Is(err, crawler.ErrStopRecursion) {
+		return nil
+	}
+	return err
+}
+
+// validateMembers checks that a response has an array member with schema-valid resources.
+func (c *classChecker) validateMembers(resp *apiResponse, key string, expected ...crawler.ResourceType) {
+	location := resp.location.String()
+	values, ok := resp.body[key].([]any)
+	if !c.check(fmt.Sprintf("response has a %q array", key), location, errorIf(!ok, "missing or invalid %q member", key)) {
+		return
+	}
+	for i, value := range values {
+		if err := c.validateResource(value, location, expected...); err != nil {
+			c.check(fmt.Sprintf("%s[%d] is valid", key, i), location, err)
+			return
+		}
+	}
+	c.check(fmt.Sprintf("%s are valid", key), location, nil)
+}
+
+// checkPagination checks that a response is limited and that the next page can be requested.
+// A limit of zero skips the check for the number of results.
+func (c *classChecker) checkPagination(resp *apiResponse, key string, limit int) {
+	location := resp.location.String()
+	values, _ := resp.body[key].([]any)
+	if limit > 0 && !c.check(fmt.Sprintf("response respects limit=%d", limit), location, errorIf(len(values) > limit, "got %d %s", len(values), key)) {
+		return
+	}
+
+	next := resp.links().Rel("next")
+	if next == nil {
+		return
+	}
+	if method := next["method"]; method != "" && method != http.MethodGet {
+		// POST pagination is not followed
+		return
+	}
+	nextResp, err := c.follow(resp, next, nil)
+	if err != nil {
+		c.check("next page can be requested", resolveHref(resp.location, next["href"]), err)
+		return
+	}
+	_, ok := nextResp.body[key].([]any)
+	c.check("next page can be requested", nextResp.location.String(), errorIf(!ok, "missing or invalid %q member", key))
+}
+
+func errorIf(condition bool, format string, args ...any) error {
+	if condition {
+		return fmt.Errorf(format, args...)
+	}
+	return nil
+}
+
+const (
+	mediaTypeJSON        = "application/json"
+	mediaTypeGeoJSON     = "application/geo+json"
+	mediaTypeSchema      = "application/schema+json"
+	relQueryables        = "http://www.opengis.net/def/rel/ogc/1.0/queryables"
+	conformanceLinkRel   = "conformance"
+	serviceDescLinkRel   = "service-desc"
+	paginationCheckLimit = 1
+)
+
+func checkCore(c *classChecker) {
+	location := c.landing.location.String()
+	c.check("landing page has a JSON content type", location, expectContentType(c.landing, mediaTypeJSON))
+	c.check("landing page is a valid catalog", location, c.validateResource(c.landing.body, location, crawler.Catalog))
+
+	links := c.landing.links()
+	for _, rel := range []string{"self", "root", serviceDescLinkRel} {
+		c.check(fmt.Sprintf("landing page has a %q link", rel), location, errorIf(links.Rel(rel) == nil, "missing %q link", rel))
+	}
+}
+
+func checkConformance(c *classChecker) {
+	resp := c.get("conformance endpoint responds with JSON", conformanceLinkRel, nil, mediaTypeJSON)
+	if resp == nil {
+		return
+	}
+	_, ok := resp.body["conformsTo"].([]any)
+	c.check("conformance endpoint lists conformance classes", resp.location.String(), errorIf(!ok, "missing or invalid \"conformsTo\" member"))
+}
+
+func checkCollections(c *classChecker) {
+	checkConformance(c)
+	resp := c.get("collections endpoint responds with JSON", "data", nil, mediaTypeJSON)
+	if resp == nil {
+		return
+	}
+	c.validateMembers(resp, "collections", crawler.Collection)
+}
+
+func checkFeatures(c *classChecker) {
+	checkConformance(c)
+	resp := c.get("collections endpoint responds with JSON", "data", nil, mediaTypeJSON)
+	if resp == nil {
+		return
+	}
+	collections, ok := resp.body["collections"].([]any)
+	if !c.check("collections endpoint lists collections", resp.location.String(), errorIf(!ok, "missing or invalid \"collections\" member")) {
+		return
+	}
+	if len(collections) == 0 {
+		c.skip("collection and items endpoints respond with valid resources", resp.location.String(), "no collections")
+		return
+	}
+
+	collection, ok := collections[0].(map[string]any)
+	if !c.check("collection is an object", resp.location.String(), errorIf(!ok, "unexpected collection type %T", collections[0])) {
+		return
+	}
+	links := crawler.Resource(collection).Links()
+	self := links.Rel("self")
+	if self != nil {
+		collectionResp, err := c.follow(resp, self, nil)
+		if err == nil {
+			err = expectContentType(collectionResp, mediaTypeJSON)
+		}
+		if err == nil {
+			err = c.validateResource(collectionResp.body, collectionResp.location.String(), crawler.Collection)
+		}
+		c.check("collection endpoint responds with a valid collection", resolveHref(resp.location, self["href"]), err)
+	}
+
+	itemsLink := links.Rel("items")
+	if !c.check("collection has an items link", resp.location.String(), errorIf(itemsLink == nil, "missing \"items\" link")) {
+		return
+	}
+	query := map[string]string{"limit": fmt.Sprint(paginationCheckLimit)}
+	itemsResp, err := c.follow(resp, itemsLink, query)
+	if err == nil {
+		err = expectContentType(itemsResp, mediaTypeGeoJSON, mediaTypeJSON)
+	}
+	if !c.check("items endpoint responds with GeoJSON", resolveHref(resp.location, itemsLink["href"]), err) {
+		return
+	}
+	c.check("items endpoint responds with a FeatureCollection", itemsResp.location.String(), errorIf(itemsResp.body["type"] != "FeatureCollection", "unexpected type %v", itemsResp.body["type"]))
+	c.validateMembers(itemsResp, "features", crawler.Item)
+	c.checkPagination(itemsResp, "features", paginationCheckLimit)
+}
+
+func (c *classChecker) search(description string, query map[string]string) *apiResponse {
+	resp := c.get(description, "search", query, mediaTypeGeoJSON, mediaTypeJSON)
+	if resp == nil {
+		return nil
+	}
+	if !c.check("search responds with a FeatureCollection", resp.location.String(), errorIf(resp.body["type"] != "FeatureCollection", "unexpected type %v", resp.body["type"])) {
+		return nil
+	}
+	return resp
+}
+
+func checkItemSearch(c *classChecker) {
+	limit := fmt.Sprint(paginationCheckLimit)
+	resp := c.search("search endpoint responds with GeoJSON", map[string]string{"limit": limit})
+	if resp == nil {
+		return
+	}
+	c.validateMembers(resp, "features", crawler.Item)
+	c.checkPagination(resp, "features", paginationCheckLimit)
+
+	for _, link := range c.landing.links() {
+		if link["rel"] != "search" || link["method"] != http.MethodPost {
+			continue
+		}
+		loc, err := c.landing.location.Resolve(link["href"])
+		if err != nil {
+			c.check("search endpoint supports POST", c.landing.location.String(), err)
+			return
+		}
+		postResp, err := c.validator.request(c.ctx, http.MethodPost, loc, map[string]any{"limit": paginationCheckLimit})
+		if err == nil {
+			err = expectContentType(postResp, mediaTypeGeoJSON, mediaTypeJSON)
+		}
+		c.check("search endpoint supports POST", loc.String(), err)
+		return
+	}
+}
+
+func checkChildren(c *classChecker) {
+	resp := c.get("children endpoint responds with JSON", "children", nil, mediaTypeJSON)
+	if resp == nil {
+		return
+	}
+	c.validateMembers(resp, "children", crawler.Catalog, crawler.Collection)
+	c.checkPagination(resp, "children", 0)
+}
+
+func checkFilter(c *classChecker) {
+	resp := c.get("queryables endpoint responds with JSON Schema", relQueryables, nil, mediaTypeSchema, mediaTypeJSON)
+	if resp != nil {
+		c.check("queryables describe an object", resp.location.String(), errorIf(resp.body["type"] != "object", "unexpected type %v", resp.body["type"]))
+	}
+	c.search("search accepts a CQL2 filter", map[string]string{
+		"limit":       "1",
+		"filter-lang": "cql2-text",
+		"filter":      "id IS NOT NULL",
+	})
+}
+
+func checkSort(c *classChecker) {
+	c.search("search accepts sortby", map[string]string{"limit": "1", "sortby": "-properties.datetime"})
+}
+
+func checkFields(c *classChecker) {
+	resp := c.search("search accepts fields", map[string]string{"limit": "1", "fields": "id,-assets"})
+	if resp == nil {
+		return
+	}
+	features, _ := resp.body["features"].([]any)
+	for _, feature := range features {
+		featureMap, _ := feature.(map[string]any)
+		if _, ok := featureMap["assets"]; ok {
+			c.check("fields excludes assets", resp.location.String(), errors.New("feature includes excluded assets"))
+			return
+		}
+	}
+	c.check("fields excludes assets", resp.location.String(), nil)
+}
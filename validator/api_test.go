@@ -0,0 +1,231 @@
+package validator_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/planetlabs/go-stac/validator"
+)
+
+type testAPI struct {
+	url             string
+	itemContentType string
+	noCollections   bool
+	relativeLinks   bool
+}
+
+func (api *testAPI) landing() map[string]any {
+	return map[string]any{
+		"stac_version": "1.0.0",
+		"type":         "Catalog",
+		"id":           "test-api",
+		"description":  "A test API",
+		"conformsTo": []string{
+			"https://api.stacspec.org/v1.0.0/core",
+			"https://api.stacspec.org/v1.0.0/ogcapi-features",
+			"https://api.stacspec.org/v1.0.0/item-search",
+			"https://api.stacspec.org/v1.0.0/item-search#sort",
+			"http://www.opengis.net/spec/ogcapi-features-1/1.0/conf/core",
+		},
+		"links": []map[string]any{
+			{"rel": "self", "href": api.url + "/", "type": "application/json"},
+			{"rel": "root", "href": api.url + "/", "type": "application/json"},
+			{"rel": "service-desc", "href": api.url + "/api", "type": "application/vnd.oai.openapi+json;version=3.0"},
+			{"rel": "conformance", "href": api.url + "/conformance", "type": "application/json"},
+			{"rel": "data", "href": api.url + "/collections", "type": "application/json"},
+			{"rel": "search", "href": api.url + "/search", "type": "application/geo+json", "method": "GET"},
+			{"rel": "search", "href": api.url + "/search", "type": "application/geo+json", "method": "POST"},
+		},
+	}
+}
+
+func (api *testAPI) collection() map[string]any {
+	itemsHref := api.url + "/collections/test-collection/items"
+	if api.relativeLinks {
+		itemsHref = "./collections/test-collection/items"
+	}
+	return map[string]any{
+		"stac_version": "1.0.0",
+		"type":         "Collection",
+		"id":           "test-collection",
+		"description":  "A test collection",
+		"license":      "CC-BY-4.0",
+		"extent": map[string]any{
+			"spatial":  map[string]any{"bbox": [][]float64{{0, 0, 0, 0}}},
+			"temporal": map[string]any{"interval": [][]any{{"2022-03-22T00:00:00Z", nil}}},
+		},
+		"links": []map[string]any{
+			{"rel": "self", "href": api.url + "/collections/test-collection", "type": "application/json"},
+			{"rel": "items", "href": itemsHref, "type": "application/geo+json"},
+		},
+	}
+}
+
+func (api *testAPI) item(id string) map[string]any {
+	return map[string]any{
+		"stac_version": "1.0.0",
+		"type":         "Feature",
+		"id":           id,
+		"collection":   "test-collection",
+		"bbox":         []float64{0, 0, 0, 0},
+		"geometry":     map[string]any{"type": "Point", "coordinates": []float64{0, 0}},
+		"properties":   map[string]any{"datetime": "2022-03-22T00:00:00Z"},
+		"links": []map[string]any{
+			{"rel": "self", "href": api.url + "/collections/test-collection/items/" + id, "type": "application/geo+json"},
+			{"rel": "collection", "href": api.url + "/collections/test-collection", "type": "application/json"},
+		},
+		"assets": map[string]any{},
+	}
+}
+
+func (api *testAPI) items(w http.ResponseWriter, r *http.Request, base string) {
+	id := "item-1"
+	links := []map[string]any{{"rel": "next", "href": base + "?page=2", "type": "application/geo+json"}}
+	if r.URL.Query().Get("page") == "2" {
+		id = "item-2"
+		links = []map[string]any{}
+	}
+	api.write(w, api.itemContentType, map[string]any{
+		"type":     "FeatureCollection",
+		"features": []any{api.item(id)},
+		"links":    links,
+	})
+}
+
+func (api *testAPI) write(w http.ResponseWriter, contentType string, body any) {
+	w.Header().Set("Content-Type", contentType)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func (api *testAPI) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		api.write(w, "application/json", api.landing())
+	})
+	mux.HandleFunc("GET /conformance", func(w http.ResponseWriter, r *http.Request) {
+		api.write(w, "application/json", map[string]any{"conformsTo": api.landing()["conformsTo"]})
+	})
+	mux.HandleFunc("GET /collections", func(w http.ResponseWriter, r *http.Request) {
+		collections := []any{api.collection()}
+		if api.noCollections {
+			collections = []any{}
+		}
+		api.write(w, "application/json", map[string]any{
+			"collections": collections,
+			"links":       []any{},
+		})
+	})
+	mux.HandleFunc("GET /collections/test-collection", func(w http.ResponseWriter, r *http.Request) {
+		api.write(w, "application/json", api.collection())
+	})
+	mux.HandleFunc("GET /collections/test-collection/items", func(w http.ResponseWriter, r *http.Request) {
+		api.items(w, r, api.url+"/collections/test-collection/items")
+	})
+	mux.HandleFunc("GET /search", func(w http.ResponseWriter, r *http.Request) {
+		api.items(w, r, api.url+"/search")
+	})
+	mux.HandleFunc("POST /search", func(w http.ResponseWriter, r *http.Request) {
+		api.items(w, r, api.url+"/search")
+	})
+	return mux
+}
+
+func (s *Suite) newTestAPI(itemContentType string) (*testAPI, func()) {
+	api := &testAPI{itemContentType: itemContentType}
+	server := httptest.NewServer(api.handler())
+	api.url = server.URL
+	return api, server.Close
+}
+
+func (s *Suite) TestValidateAPI() {
+	api, stop := s.newTestAPI("application/geo+json")
+	defer stop()
+
+	v := validator.New()
+	report, err := v.ValidateAPI(context.Background(), api.url+"/")
+	s.Require().NoError(err)
+
+	names := []string{}
+	for _, class := range report.Classes {
+		names = append(names, class.Name)
+		for _, check := range class.Checks {
+			s.NoError(check.Err, "%s: %s", class.Name, check.Description)
+		}
+	}
+	s.Equal([]string{"core", "features", "item-search", "sort"}, names)
+	s.True(report.Passed())
+}
+
+func (s *Suite) TestValidateAPIContentType() {
+	api, stop := s.newTestAPI("text/html")
+	defer stop()
+
+	v := validator.New()
+	report, err := v.ValidateAPI(context.Background(), api.url+"/")
+	s.Require().NoError(err)
+	s.False(report.Passed())
+
+	failed := map[string]bool{}
+	for _, class := range report.Classes {
+		failed[class.Name] = !class.Passed()
+	}
+	s.Equal(map[string]bool{
+		"core":        false,
+		"features":    true,
+		"item-search": true,
+		"sort":        true,
+	}, failed)
+}
+
+func (s *Suite) TestValidateAPINotFound() {
+	api, stop := s.newTestAPI("application/geo+json")
+	defer stop()
+
+	v := validator.New()
+	_, err := v.ValidateAPI(context.Background(), api.url+"/not-found")
+	s.Error(err)
+}
+
+func (s *Suite) TestValidateAPINoCollections() {
+	api, stop := s.newTestAPI("application/geo+json")
+	defer stop()
+	api.noCollections = true
+
+	v := validator.New()
+	report, err := v.ValidateAPI(context.Background(), api.url+"/")
+	s.Require().NoError(err)
+	s.True(report.Passed())
+
+	skipped := []string{}
+	for _, class := range report.Classes {
+		for _, check := range class.Checks {
+			if check.Skipped != "" {
+				skipped = append(skipped, class.Name+": "+check.Skipped)
+			}
+		}
+	}
+	s.Equal([]string{"features: no collections"}, skipped)
+}
+
+func (s *Suite) TestValidateAPIResolvedLocation() {
+	api, stop := s.newTestAPI("text/html")
+	defer stop()
+	api.relativeLinks = true
+
+	v := validator.New()
+	report, err := v.ValidateAPI(context.Background(), api.url+"/")
+	s.Require().NoError(err)
+
+	locations := []string{}
+	for _, class := range report.Classes {
+		for _, check := range class.Checks {
+			if check.Description == "items endpoint responds with GeoJSON" {
+				s.Error(check.Err)
+				locations = append(locations, check.Location)
+			}
+		}
+	}
+	s.Equal([]string{api.url + "/collections/test-collection/items"}, locations)
+}
@@ -0,0 +1,84 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/planetlabs/go-stac/crawler"
+)
+
+// legacyExtensions lists the extensions that were published with each pre-1.0 version of
+// the specification.  Before 1.0.0-rc.1, extensions were identified by short name in the
+// stac_extensions member and their schemas were published alongside the core schemas.
+var legacyExtensions = map[string][]string{
+	"0.9.0": {
+		"asset", "checksum", "datacube", "eo", "label", "pointcloud", "projection",
+		"sar", "sat", "scientific", "single-file-stac", "version",
+	},
+	"1.0.0-beta.1": {
+		"collection-assets", "datacube", "eo", "file", "item-assets", "label", "pointcloud",
+		"processing", "projection", "sar", "sat", "scientific", "single-file-stac",
+		"tiled-assets", "timestamps", "version", "view",
+	},
+	"1.0.0-beta.2": {
+		"datacube", "eo", "file", "item-assets", "label", "pointcloud", "processing",
+		"projection", "sar", "sat", "scientific", "single-file-stac", "tiled-assets",
+		"timestamps", "version", "view",
+	},
+}
+
+// legacyExtensionAliases maps alternate short names to the names used for the schema directories.
+var legacyExtensionAliases = map[string]string{
+	"proj":     "projection",
+	"sci":      "scientific",
+	"versions": "version",
+}
+
+// legacyCollectionExtensions are the extensions with schemas that apply to collections.
+// The schemas for other legacy extensions only apply to items.
+var legacyCollectionExtensions = map[string]bool{
+	"asset":             true,
+	"collection-assets": true,
+	"item-assets":       true,
+	"scientific":        true,
+	"version":           true,
+}
+
+// ExtensionSchemaURL returns the historical schema URL for an extension identified by
+// short name (e.g. "eo") in a resource with the given STAC version (e.g. "0.9.0").  The
+// second return value is false if the short name is not known for the version.
+//
+// Only 0.9.0, 1.0.0-beta.1, and 1.0.0-beta.2 are supported.  Starting with 1.0.0-rc.1,
+// the stac_extensions member lists schema URLs, so short names are not resolved for
+// 1.0.0-rc.* or later versions.
+func ExtensionSchemaURL(stacVersion string, name string) (string, bool) {
+	if alias, ok := legacyExtensionAliases[name]; ok {
+		name = alias
+	}
+	for _, known := range legacyExtensions[stacVersion] {
+		if known == name {
+			return fmt.Sprintf("https://schemas.stacspec.org/v%s/extensions/%s/json-schema/schema.json", stacVersion, name), true
+		}
+	}
+	return "", false
+}
+
+// legacyExtensionSchemaURL returns the schema URL for a short name extension if the schema
+// applies to the resource type.
+func (v *Validator) legacyExtensionSchemaURL(resource crawler.Resource, name string, location string) (string, bool) {
+	version := resource.Version()
+	schemaUrl, ok := ExtensionSchemaURL(version, name)
+	if !ok {
+		v.logger.Info("unknown extension", "resource", location, "extension", name, "version", version)
+		return "", false
+	}
+
+	if alias, ok := legacyExtensionAliases[name]; ok {
+		name = alias
+	}
+	resourceType := resource.Type()
+	if resourceType == crawler.Item || (resourceType == crawler.Collection && legacyCollectionExtensions[name]) {
+		return schemaUrl, true
+	}
+	v.logger.V(1).Info("extension schema does not apply to resource", "resource", location, "extension", name, "type", resourceType)
+	return "", false
+}
@@ -0,0 +1,55 @@
+package validator_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/planetlabs/go-stac/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtensionSchemaURL(t *testing.T) {
+	cases := []struct {
+		version string
+		name    string
+		url     string
+	}{
+		{"0.9.0", "eo", "https://schemas.stacspec.org/v0.9.0/extensions/eo/json-schema/schema.json"},
+		{"0.9.0", "proj", "https://schemas.stacspec.org/v0.9.0/extensions/projection/json-schema/schema.json"},
+		{"1.0.0-beta.2", "view", "https://schemas.stacspec.org/v1.0.0-beta.2/extensions/view/json-schema/schema.json"},
+		{"0.9.0", "view", ""},
+		{"1.0.0", "eo", ""},
+		{"1.0.0-beta.2", "unknown", ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.version+"/"+c.name, func(t *testing.T) {
+			url, ok := validator.ExtensionSchemaURL(c.version, c.name)
+			assert.Equal(t, c.url, url)
+			assert.Equal(t, c.url != "", ok)
+		})
+	}
+}
+
+func (s *Suite) TestLegacyExtensionInvalid() {
+	messages := []string{}
+	logger := funcr.New(func(prefix string, args string) {
+		messages = append(messages, args)
+	}, funcr.Options{})
+
+	v := validator.New(&validator.Options{Logger: &logger})
+	err := v.Validate(context.Background(), "testdata/cases/v1.0.0-beta.2/item-proj-invalid.json")
+	s.Require().Error(err)
+	s.IsType(&validator.ValidationError{}, err)
+	s.Contains(err.Error(), "extensions/projection/json-schema/schema.json")
+
+	unknown := false
+	for _, message := range messages {
+		if strings.Contains(message, `"unknown extension"`) && strings.Contains(message, `"extension"="unknown"`) {
+			unknown = true
+		}
+	}
+	s.True(unknown, "expected a message about the unknown extension")
+}
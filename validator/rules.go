@@ -0,0 +1,364 @@
+package validator
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/planetlabs/go-stac/crawler"
+	"github.com/planetlabs/go-stac/internal/registry"
+)
+
+// Severity indicates how serious a rule violation is.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// RuleContext provides a rule with the resource being checked and any related resources.
+type RuleContext struct {
+	// Resource is the resource being checked.
+	Resource crawler.Resource
+
+	// Location is the file path or URL of the resource.
+	Location string
+
+	// Collection is the parent collection of an item.  This will be nil if the resource
+	// is not an item or if the parent collection was not visited during the crawl.
+	Collection crawler.Resource
+}
+
+// RuleCheck checks a resource and returns a message for each problem found.
+type RuleCheck func(*RuleContext) []string
+
+// Rule is a semantic check that goes beyond what can be expressed with JSON Schema.
+type Rule struct {
+	// Id uniquely identifies the rule.
+	Id string
+
+	// Severity of any issues reported by the rule.
+	Severity Severity
+
+	// Description is a short summary of what the rule checks.
+	Description string
+
+	// Check is called for each resource.
+	Check RuleCheck
+}
+
+var rules = registry.New(func(rule *Rule) string { return rule.Id })
+
+// RegisterRule adds a rule to the list returned by Rules.  A previously registered rule
+// with the same id will be replaced.
+func RegisterRule(rule *Rule) {
+	rules.Register(rule)
+}
+
+// UnregisterRule removes a rule added with RegisterRule.
+func UnregisterRule(id string) {
+	rules.Unregister(id)
+}
+
+// Rules returns the built-in rules and any rules added with RegisterRule.
+func Rules() []*Rule {
+	return rules.Values()
+}
+
+// Issue is reported when a rule finds a problem with a resource.
+type Issue struct {
+	// Rule is the rule that reported the issue.
+	Rule *Rule
+
+	// Location is the file path or URL to the resource with the issue.
+	Location string
+
+	// The resource being crawled.
+	Resource crawler.Resource
+
+	// Message describes the problem.
+	Message string
+}
+
+func (issue *Issue) Error() string {
+	return fmt.Sprintf("%s: %s (%s)", issue.Location, issue.Message, issue.Rule.Id)
+}
+
+// GoString provides additional detail about the issue.
+//
+// Called when the # flag is used with the %v verb as in fmt.Printf("%#v", issue).
+func (issue *Issue) GoString() string {
+	return fmt.Sprintf("%s in %s: %s\n%s [%s]", issue.Rule.Severity, issue.Resource.Type(), issue.Location, issue.Message, issue.Rule.Id)
+}
+
+// IssueHandler is called with each issue found by a rule.  If the function
+// returns nil, validation will continue.  If the function returns an error,
+// validation will stop.
+type IssueHandler func(*Issue) error
+
+func init() {
+	RegisterRule(&Rule{
+		Id:          "bbox-contains-geometry",
+		Severity:    SeverityError,
+		Description: "The bbox of an item must contain its geometry.",
+		Check:       checkBboxContainsGeometry,
+	})
+	RegisterRule(&Rule{
+		Id:          "datetime-range",
+		Severity:    SeverityError,
+		Description: "The start_datetime of an item must not be after its end_datetime.",
+		Check:       checkDatetimeRange,
+	})
+	RegisterRule(&Rule{
+		Id:          "temporal-extent",
+		Severity:    SeverityError,
+		Description: "The datetime of an item must be within the temporal extent of its collection.",
+		Check:       checkTemporalExtent,
+	})
+	RegisterRule(&Rule{
+		Id:          "duplicate-asset-href",
+		Severity:    SeverityWarning,
+		Description: "Assets should not share the same href.",
+		Check:       checkDuplicateAssetHref,
+	})
+	RegisterRule(&Rule{
+		Id:          "collection-id",
+		Severity:    SeverityError,
+		Description: "The collection member of an item must match the id of its parent collection.",
+		Check:       checkCollectionId,
+	})
+}
+
+func checkBboxContainsGeometry(ctx *RuleContext) []string {
+	if ctx.Resource.Type() != crawler.Item {
+		return nil
+	}
+	bbox, ok := toFloats(ctx.Resource["bbox"])
+	if !ok || (len(bbox) != 4 && len(bbox) != 6) {
+		return nil
+	}
+	geometry, ok := ctx.Resource["geometry"].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	dims := len(bbox) / 2
+	outside := 0
+	visitPositions(geometry, func(position []float64) {
+		if !bboxContains(bbox, dims, position) {
+			outside += 1
+		}
+	})
+	if outside == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("%d geometry position(s) outside of bbox %v", outside, bbox)}
+}
+
+// tolerance allows for rounding in serialized coordinates
+const tolerance = 1e-9
+
+func bboxContains(bbox []float64, dims int, position []float64) bool {
+	if len(position) < 2 {
+		return true
+	}
+	minX, maxX := bbox[0], bbox[dims]
+	x := position[0]
+	if minX <= maxX {
+		if x < minX-tolerance || x > maxX+tolerance {
+			return false
+		}
+	} else if x < minX-tolerance && x > maxX+tolerance {
+		// bbox crosses the antimeridian
+		return false
+	}
+
+	for i := 1; i < dims && i < len(position); i += 1 {
+		if position[i] < bbox[i]-tolerance || position[i] > bbox[dims+i]+tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+func visitPositions(geometry map[string]any, visit func([]float64)) {
+	if geometries, ok := geometry["geometries"].([]any); ok {
+		for _, g := range geometries {
+			if child, ok := g.(map[string]any); ok {
+				visitPositions(child, visit)
+			}
+		}
+		return
+	}
+	visitCoordinates(geometry["coordinates"], visit)
+}
+
+func visitCoordinates(value any, visit func([]float64)) {
+	values, ok := value.([]any)
+	if !ok || len(values) == 0 {
+		return
+	}
+	if _, nested := values[0].([]any); nested {
+		for _, v := range values {
+			visitCoordinates(v, visit)
+		}
+		return
+	}
+	if position, ok := toFloats(values); ok {
+		visit(position)
+	}
+}
+
+func toFloats(value any) ([]float64, bool) {
+	values, ok := value.([]any)
+	if !ok {
+		return nil, false
+	}
+	floats := make([]float64, len(values))
+	for i, v := range values {
+		f, ok := v.(float64)
+		if !ok {
+			return nil, false
+		}
+		floats[i] = f
+	}
+	return floats, true
+}
+
+func checkDatetimeRange(ctx *RuleContext) []string {
+	if ctx.Resource.Type() != crawler.Item {
+		return nil
+	}
+	properties, ok := ctx.Resource["properties"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	start, startOk := parseTime(properties["start_datetime"])
+	end, endOk := parseTime(properties["end_datetime"])
+	if !startOk || !endOk {
+		return nil
+	}
+	if start.After(end) {
+		return []string{fmt.Sprintf("start_datetime %s is after end_datetime %s", properties["start_datetime"], properties["end_datetime"])}
+	}
+	return nil
+}
+
+func checkTemporalExtent(ctx *RuleContext) []string {
+	if ctx.Resource.Type() != crawler.Item || ctx.Collection == nil {
+		return nil
+	}
+	properties, ok := ctx.Resource["properties"].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	start, ok := parseTime(properties["start_datetime"])
+	if !ok {
+		start, ok = parseTime(properties["datetime"])
+		if !ok {
+			return nil
+		}
+	}
+	end, ok := parseTime(properties["end_datetime"])
+	if !ok {
+		end, ok = parseTime(properties["datetime"])
+		if !ok {
+			end = start
+		}
+	}
+
+	extentStart, extentEnd, ok := collectionInterval(ctx.Collection)
+	if !ok {
+		return nil
+	}
+	if (extentStart != nil && start.Before(*extentStart)) || (extentEnd != nil && end.After(*extentEnd)) {
+		return []string{fmt.Sprintf("item time is outside of the temporal extent of collection %q", ctx.Collection["id"])}
+	}
+	return nil
+}
+
+// collectionInterval returns the overall temporal extent of a collection.  A nil
+// start or end indicates an open interval.
+func collectionInterval(collection crawler.Resource) (*time.Time, *time.Time, bool) {
+	extent, ok := collection["extent"].(map[string]any)
+	if !ok {
+		return nil, nil, false
+	}
+	temporal, ok := extent["temporal"].(map[string]any)
+	if !ok {
+		return nil, nil, false
+	}
+	intervals, ok := temporal["interval"].([]any)
+	if !ok || len(intervals) == 0 {
+		return nil, nil, false
+	}
+	interval, ok := intervals[0].([]any)
+	if !ok || len(interval) != 2 {
+		return nil, nil, false
+	}
+
+	var start, end *time.Time
+	if t, ok := parseTime(interval[0]); ok {
+		start = &t
+	}
+	if t, ok := parseTime(interval[1]); ok {
+		end = &t
+	}
+	return start, end, true
+}
+
+func parseTime(value any) (time.Time, bool) {
+	str, ok := value.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, str)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+func checkDuplicateAssetHref(ctx *RuleContext) []string {
+	assets := ctx.Resource.Assets()
+	if len(assets) < 2 {
+		return nil
+	}
+
+	keys := map[string]string{}
+	messages := []string{}
+	for _, key := range slices.Sorted(maps.Keys(assets)) {
+		href := assets[key].Href()
+		if href == "" {
+			continue
+		}
+		if other, ok := keys[href]; ok {
+			messages = append(messages, fmt.Sprintf("assets %q and %q have the same href %q", other, key, href))
+			continue
+		}
+		keys[href] = key
+	}
+	return messages
+}
+
+func checkCollectionId(ctx *RuleContext) []string {
+	if ctx.Resource.Type() != crawler.Item || ctx.Collection == nil {
+		return nil
+	}
+	collectionId, ok := ctx.Collection["id"].(string)
+	if !ok {
+		return nil
+	}
+	value, ok := ctx.Resource["collection"]
+	if !ok {
+		return []string{fmt.Sprintf("missing collection member, expected %q", collectionId)}
+	}
+	if value != collectionId {
+		return []string{fmt.Sprintf("collection member %q does not match parent collection id %q", value, collectionId)}
+	}
+	return nil
+}
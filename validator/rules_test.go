@@ -0,0 +1,148 @@
+package validator_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/planetlabs/go-stac/crawler"
+	"github.com/planetlabs/go-stac/validator"
+)
+
+func (s *Suite) TestRulesValidCases() {
+	cases := []string{
+		"v1.0.0/catalog.json",
+		"v1.0.0/collection.json",
+		"v1.0.0/item.json",
+		"v1.0.0/catalog-with-multiple-items.json",
+		"v1.0.0/item-eo.json",
+	}
+
+	v := validator.New(&validator.Options{Rules: validator.Rules()})
+	ctx := context.Background()
+	for _, c := range cases {
+		s.Run(c, func() {
+			s.NoError(v.Validate(ctx, "testdata/cases/"+c))
+		})
+	}
+}
+
+func (s *Suite) TestRulesInvalidCases() {
+	cases := []struct {
+		resource string
+		rules    []string
+	}{
+		{
+			resource: "v1.0.0/item-bbox-mismatch.json",
+			rules:    []string{"bbox-contains-geometry"},
+		},
+		{
+			resource: "v1.0.0/item-datetime-range.json",
+			rules:    []string{"datetime-range"},
+		},
+		{
+			resource: "v1.0.0/item-duplicate-asset-href.json",
+			rules:    []string{"duplicate-asset-href"},
+		},
+		{
+			resource: "v1.0.0/collection-with-item-outside-extent.json",
+			rules:    []string{"temporal-extent", "collection-id"},
+		},
+	}
+
+	ctx := context.Background()
+	for _, c := range cases {
+		s.Run(c.resource, func() {
+			mutex := &sync.Mutex{}
+			found := []string{}
+			v := validator.New(&validator.Options{
+				Rules: validator.Rules(),
+				IssueHandler: func(issue *validator.Issue) error {
+					mutex.Lock()
+					defer mutex.Unlock()
+					found = append(found, issue.Rule.Id)
+					return nil
+				},
+			})
+			s.Require().NoError(v.Validate(ctx, "testdata/cases/"+c.resource))
+			s.ElementsMatch(c.rules, found)
+		})
+	}
+}
+
+func (s *Suite) TestRulesDefaultIssueHandler() {
+	v := validator.New(&validator.Options{Rules: validator.Rules()})
+
+	err := v.Validate(context.Background(), "testdata/cases/v1.0.0/item-bbox-mismatch.json")
+	s.Require().Error(err)
+
+	issue := &validator.Issue{}
+	s.Require().True(errors.As(err, &issue))
+	s.Equal("bbox-contains-geometry", issue.Rule.Id)
+	s.Equal(validator.SeverityError, issue.Rule.Severity)
+
+	s.NoError(v.Validate(context.Background(), "testdata/cases/v1.0.0/item-duplicate-asset-href.json"))
+}
+
+func (s *Suite) TestRegisterRule() {
+	validator.RegisterRule(&validator.Rule{
+		Id:       "test-item-id",
+		Severity: validator.SeverityError,
+		Check: func(ctx *validator.RuleContext) []string {
+			if ctx.Resource.Type() == crawler.Item && ctx.Resource["id"] == "custom-extension-schema-map" {
+				return []string{"unexpected id"}
+			}
+			return nil
+		},
+	})
+	s.T().Cleanup(func() { validator.UnregisterRule("test-item-id") })
+
+	v := validator.New(&validator.Options{
+		Rules: validator.Rules(),
+		SchemaMap: map[string]string{
+			"https://stac-extensions.github.io/custom/v1.0.0/schema.json": "https://example.com//extensions/custom.json",
+		},
+	})
+	err := v.Validate(context.Background(), "testdata/cases/v1.0.0/item-custom.json")
+	s.Require().Error(err)
+	s.Contains(err.Error(), "unexpected id (test-item-id)")
+}
+
+func (s *Suite) TestUnregisterRule() {
+	validator.RegisterRule(&validator.Rule{Id: "test-unregister", Severity: validator.SeverityWarning})
+	validator.UnregisterRule("test-unregister")
+
+	for _, rule := range validator.Rules() {
+		s.NotEqual("test-unregister", rule.Id)
+	}
+}
+
+func (s *Suite) TestRuleCollectionScopedToValidate() {
+	mutex := &sync.Mutex{}
+	withCollection := map[bool]int{}
+	rule := &validator.Rule{
+		Id:       "test-item-collection",
+		Severity: validator.SeverityWarning,
+		Check: func(ctx *validator.RuleContext) []string {
+			if ctx.Resource.Type() == crawler.Item {
+				mutex.Lock()
+				withCollection[ctx.Collection != nil] += 1
+				mutex.Unlock()
+			}
+			return nil
+		},
+	}
+
+	v := validator.New(&validator.Options{
+		Rules:        []*validator.Rule{rule},
+		IssueHandler: func(issue *validator.Issue) error { return nil },
+	})
+	ctx := context.Background()
+
+	s.Require().NoError(v.Validate(ctx, "testdata/cases/v1.0.0/collection-with-item-outside-extent.json"))
+	s.Equal(map[bool]int{true: 1}, withCollection)
+
+	// the collection from the previous call is not used
+	s.Require().NoError(v.Validate(ctx, "testdata/cases/v1.0.0/item-outside-extent.json"))
+	s.Equal(map[bool]int{true: 1, false: 1}, withCollection)
+}
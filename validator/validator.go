@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"runtime"
 	"sync"
@@ -14,7 +15,9 @@ import (
 	"github.com/dlclark/regexp2"
 	"github.com/go-logr/logr"
 	"github.com/go-logr/logr/funcr"
+	"github.com/planetlabs/go-stac"
 	"github.com/planetlabs/go-stac/crawler"
+	"github.com/planetlabs/go-stac/internal/normurl"
 	"github.com/santhosh-tekuri/jsonschema/v5"
 	_ "github.com/santhosh-tekuri/jsonschema/v5/httploader"
 	"golang.org/x/sync/singleflight"
@@ -59,13 +62,17 @@ func (r *ecmaRegexp) String() string {
 
 // Validator allows validation of STAC resources.
 type Validator struct {
-	concurrency int
-	noRecursion bool
-	cache       *sync.Map
-	group       *singleflight.Group
-	compiler    *jsonschema.Compiler
-	schemaMap   map[string]string
-	logger      logr.Logger
+	concurrency  int
+	noRecursion  bool
+	cache        *sync.Map
+	group        *singleflight.Group
+	compiler     *jsonschema.Compiler
+	schemaMap    map[string]string
+	logger       logr.Logger
+	rules        []*Rule
+	issues       IssueHandler
+	client       *http.Client
+	pollInterval time.Duration
 }
 
 // Options for the Validator.
@@ -82,6 +89,20 @@ type Options struct {
 
 	// Logger to use for logging.
 	Logger *logr.Logger
+
+	// Semantic rules to check after schema validation.  Use Rules to get the built-in
+	// rules and any registered with RegisterRule.
+	Rules []*Rule
+
+	// Optional function to handle issues reported by rules.  By default, issues with
+	// SeverityError will stop validation and other issues will be logged.
+	IssueHandler IssueHandler
+
+	// Optional client for requests made when validating an API with ValidateAPI.
+	Client *http.Client
+
+	// Interval between checks for modified files when watching with Watch.  Defaults to one second.
+	PollInterval time.Duration
 }
 
 func (v *Validator) apply(options *Options) {
@@ -97,23 +118,48 @@ func (v *Validator) apply(options *Options) {
 	if options.Logger != nil {
 		v.logger = *options.Logger
 	}
+	if options.Rules != nil {
+		v.rules = options.Rules
+	}
+	if options.IssueHandler != nil {
+		v.issues = options.IssueHandler
+	}
+	if options.Client != nil {
+		v.client = options.Client
+	}
+	if options.PollInterval != 0 {
+		v.pollInterval = options.PollInterval
+	}
 }
 
 // New creates a new Validator.
 func New(options ...*Options) *Validator {
 	v := &Validator{
-		concurrency: runtime.GOMAXPROCS(0),
-		group:       &singleflight.Group{},
-		cache:       &sync.Map{},
-		compiler:    jsonschema.NewCompiler(),
-		logger:      funcr.New(func(prefix string, args string) {}, funcr.Options{}),
+		concurrency:  runtime.GOMAXPROCS(0),
+		group:        &singleflight.Group{},
+		cache:        &sync.Map{},
+		client:       &http.Client{Timeout: time.Minute},
+		pollInterval: time.Second,
+		compiler:     jsonschema.NewCompiler(),
+		logger:       funcr.New(func(prefix string, args string) {}, funcr.Options{}),
 	}
 	for _, opt := range options {
 		v.apply(opt)
 	}
+	if v.issues == nil {
+		v.issues = v.defaultIssueHandler
+	}
 	return v
 }
 
+func (v *Validator) defaultIssueHandler(issue *Issue) error {
+	if issue.Rule.Severity == SeverityError {
+		return issue
+	}
+	v.logger.Info(issue.Message, "resource", issue.Location, "rule", issue.Rule.Id, "severity", issue.Rule.Severity)
+	return nil
+}
+
 func (v *Validator) loadSchema(schemaUrl string) (*jsonschema.Schema, error) {
 	log := v.logger.WithValues("schema", schemaUrl)
 	if substituteUrl, ok := v.schemaMap[schemaUrl]; ok {
@@ -156,33 +202,97 @@ func schemaUrl(version string, resourceType crawler.ResourceType) string {
 // returned.  Context cancellation will also stop validation and the context
 // error will be returned.
 func (v *Validator) Validate(ctx context.Context, resource string) error {
-	return crawler.Crawl(resource, v.validate, &crawler.Options{
+	// collections visited during this crawl (for rules that check items)
+	collections := &sync.Map{}
+	visitor := func(resource crawler.Resource, info *crawler.ResourceInfo) error {
+		return v.validate(collections, resource, info)
+	}
+	return crawler.Crawl(resource, visitor, &crawler.Options{
 		Queue: crawler.NewMemoryQueue(ctx, v.concurrency),
 	})
 }
 
+var defaultValidator = sync.OnceValue(func() *Validator { return New() })
+
 // ValidateBytes validates a single STAC resource.
 //
 // The location is a URL or file path that represents the resource and will
-// be used in any validation error.
+// be used in any validation error.  Compiled schemas are cached and shared
+// across calls.  Use Validator.ValidateBytes to validate with custom options.
 func ValidateBytes(ctx context.Context, data []byte, location string) error {
+	return defaultValidator().ValidateBytes(ctx, data, location)
+}
+
+// ValidateBytes validates a single STAC resource.
+//
+// The location is a URL or file path that represents the resource and will
+// be used in any validation error.  Linked resources are not validated.
+func (v *Validator) ValidateBytes(ctx context.Context, data []byte, location string) error {
 	resource := crawler.Resource{}
 	if err := json.Unmarshal(data, &resource); err != nil {
 		return fmt.Errorf("failed to parse data as JSON: %w", err)
 	}
-	v := New(&Options{NoRecursion: true})
+	return v.validateOne(ctx, resource, location)
+}
+
+// ValidateResource validates a single in-memory STAC resource.
+//
+// The value can be a crawler.Resource, a map, or any value that encodes as a
+// STAC resource with encoding/json (e.g. a *stac.Item).  The location is a URL
+// or file path that represents the resource and will be used in any validation
+// error.  Linked resources are not validated.
+func (v *Validator) ValidateResource(ctx context.Context, value any, location string) error {
+	switch r := value.(type) {
+	case crawler.Resource:
+		return v.validateOne(ctx, r, location)
+	case map[string]any:
+		return v.validateOne(ctx, crawler.Resource(r), location)
+	case []byte:
+		return v.ValidateBytes(ctx, r, location)
+	case json.RawMessage:
+		return v.ValidateBytes(ctx, r, location)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode resource as JSON: %w", err)
+	}
+	return v.ValidateBytes(ctx, data, location)
+}
+
+// ValidateItem validates an item.
+func (v *Validator) ValidateItem(ctx context.Context, item *stac.Item, location string) error {
+	return v.ValidateResource(ctx, item, location)
+}
+
+// ValidateCollection validates a collection.
+func (v *Validator) ValidateCollection(ctx context.Context, collection *stac.Collection, location string) error {
+	return v.ValidateResource(ctx, collection, location)
+}
+
+// ValidateCatalog validates a catalog.
+func (v *Validator) ValidateCatalog(ctx context.Context, catalog *stac.Catalog, location string) error {
+	return v.ValidateResource(ctx, catalog, location)
+}
+
+func (v *Validator) validateOne(ctx context.Context, resource crawler.Resource, location string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	info := &crawler.ResourceInfo{
 		Location: location,
 		Entry:    location,
 	}
-	err := v.validate(resource, info)
+	err := v.validate(&sync.Map{}, resource, info)
 	if !errors.Is(err, crawler.ErrStopRecursion) {
 		return err
 	}
 	return nil
 }
 
-func (v *Validator) validate(resource crawler.Resource, info *crawler.ResourceInfo) error {
+// validate validates a single resource.  Collections are stored in the provided map
+// so that later items can be checked against their collection.
+func (v *Validator) validate(collections *sync.Map, resource crawler.Resource, info *crawler.ResourceInfo) error {
 	v.logger.Info("validating resource", "resource", info.Location)
 	version := resource.Version()
 	if version == "" {
@@ -206,14 +316,18 @@ func (v *Validator) validate(resource crawler.Resource, info *crawler.ResourceIn
 	}
 
 	for _, extension := range resource.Extensions() {
+		extensionSchemaUrl := extension
 		extensionUrl, urlErr := url.Parse(extension)
 		if urlErr != nil || !extensionUrl.IsAbs() {
-			// this is expected for stac < 1.0.0
-			v.logger.V(1).Info("invalid extension URL", "extension", extension)
-			continue
+			// this is expected for stac < 1.0.0, where extensions are identified by short name
+			legacyUrl, ok := v.legacyExtensionSchemaURL(resource, extension, info.Location)
+			if !ok {
+				continue
+			}
+			extensionSchemaUrl = legacyUrl
 		}
 
-		extensionSchema, loadErr := v.loadSchema(extensionUrl.String())
+		extensionSchema, loadErr := v.loadSchema(extensionSchemaUrl)
 		if loadErr != nil {
 			return loadErr
 		}
@@ -226,9 +340,69 @@ func (v *Validator) validate(resource crawler.Resource, info *crawler.ResourceIn
 		}
 	}
 
+	if err := v.checkRules(collections, resource, info); err != nil {
+		return err
+	}
+
 	if v.noRecursion {
 		return crawler.ErrStopRecursion
 	}
 
 	return nil
 }
+
+func (v *Validator) checkRules(collections *sync.Map, resource crawler.Resource, info *crawler.ResourceInfo) error {
+	if len(v.rules) == 0 {
+		return nil
+	}
+
+	ctx := &RuleContext{
+		Resource: resource,
+		Location: info.Location,
+	}
+	switch resource.Type() {
+	case crawler.Collection:
+		collections.Store(info.Location, resource)
+	case crawler.Item:
+		ctx.Collection = parentCollection(collections, resource, info.Location)
+	}
+
+	for _, rule := range v.rules {
+		for _, message := range rule.Check(ctx) {
+			issue := &Issue{
+				Rule:     rule,
+				Location: info.Location,
+				Resource: resource,
+				Message:  message,
+			}
+			if err := v.issues(issue); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parentCollection returns a previously visited collection referenced by an item's
+// collection or parent link.
+func parentCollection(collections *sync.Map, item crawler.Resource, location string) crawler.Resource {
+	base, err := normurl.New(location)
+	if err != nil {
+		return nil
+	}
+	links := item.Links()
+	for _, rel := range []string{"collection", "parent"} {
+		link := links.Rel(rel)
+		if link == nil {
+			continue
+		}
+		loc, err := base.Resolve(link["href"])
+		if err != nil {
+			continue
+		}
+		if collection, ok := collections.Load(loc.String()); ok {
+			return collection.(crawler.Resource)
+		}
+	}
+	return nil
+}
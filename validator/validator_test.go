@@ -2,6 +2,7 @@ package validator_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/url"
@@ -10,6 +11,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/planetlabs/go-stac"
+	"github.com/planetlabs/go-stac/crawler"
 	"github.com/planetlabs/go-stac/validator"
 	"github.com/santhosh-tekuri/jsonschema/v5"
 	"github.com/stretchr/testify/suite"
@@ -102,6 +105,50 @@ func (s *Suite) TestValidateBytesInvalidItem() {
 	s.Assert().True(strings.HasSuffix(fmt.Sprintf("%#v", err), "missing properties: 'id'"))
 }
 
+func (s *Suite) TestValidateResource() {
+	v := validator.New()
+	ctx := context.Background()
+
+	location := "testdata/cases/v1.0.0/item.json"
+	data, readErr := os.ReadFile(location)
+	s.Require().NoError(readErr)
+
+	item := &stac.Item{}
+	s.Require().NoError(json.Unmarshal(data, item))
+	s.NoError(v.ValidateItem(ctx, item, location))
+
+	resource := crawler.Resource{}
+	s.Require().NoError(json.Unmarshal(data, &resource))
+	s.NoError(v.ValidateResource(ctx, resource, location))
+	s.NoError(v.ValidateResource(ctx, json.RawMessage(data), location))
+
+	item.Id = ""
+	err := v.ValidateItem(ctx, item, location)
+	s.Require().Error(err)
+	s.IsType(&validator.ValidationError{}, err)
+}
+
+func (s *Suite) TestValidateCollection() {
+	v := validator.New()
+
+	location := "testdata/cases/v1.0.0/collection.json"
+	data, readErr := os.ReadFile(location)
+	s.Require().NoError(readErr)
+
+	collection := &stac.Collection{}
+	s.Require().NoError(json.Unmarshal(data, collection))
+	s.NoError(v.ValidateCollection(context.Background(), collection, location))
+}
+
+func (s *Suite) TestValidateResourceCanceled() {
+	v := validator.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := v.ValidateResource(ctx, crawler.Resource{}, "canceled.json")
+	s.ErrorIs(err, context.Canceled)
+}
+
 func (s *Suite) TestSchemaMap() {
 	v := validator.New(&validator.Options{
 		SchemaMap: map[string]string{
@@ -0,0 +1,323 @@
+package validator
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/planetlabs/go-stac/crawler"
+	"github.com/planetlabs/go-stac/internal/normurl"
+)
+
+// WatchEvent is the outcome of validating a resource while watching.
+type WatchEvent struct {
+	// Location is the file path of the resource.
+	Location string
+
+	// Changed is true if the resource was validated because it or a resource it
+	// links to was modified.  It is false for the initial validation.
+	Changed bool
+
+	// Err is nil if the resource is valid.  Otherwise it is a *ValidationError, an
+	// *Issue, or an error loading the resource.
+	Err error
+}
+
+// WatchHandler is called with the outcome of each validation while watching.  Calls
+// are not made concurrently.  Any returned error will stop watching.
+type WatchHandler func(*WatchEvent) error
+
+// watchedFile is the state of a resource at the time it was last validated.
+type watchedFile struct {
+	modTime time.Time
+	size    int64
+	missing bool
+	links   []string
+}
+
+type watcher struct {
+	validator   *Validator
+	entry       string
+	handler     WatchHandler
+	mutex       *sync.Mutex
+	files       map[string]*watchedFile
+	collections *sync.Map
+}
+
+// Watch validates local STAC resources starting with the entry and then polls for
+// changes until the context is canceled.  When a file changes, that resource and any
+// resources that link to it are validated again.  New resources linked from a modified
+// resource are also crawled and validated.
+//
+// Unlike Validate, watching does not stop with the first invalid resource.  The
+// handler is called with the outcome of each validation.  The entry must be a
+// local file path.  When the context is canceled, the context error is returned.
+func (v *Validator) Watch(ctx context.Context, entry string, handler WatchHandler) error {
+	loc, err := normurl.New(entry)
+	if err != nil {
+		return fmt.Errorf("failed to parse entry %s: %w", entry, err)
+	}
+	if !loc.IsFilepath() {
+		return fmt.Errorf("watching is only supported for local files, got %s", entry)
+	}
+
+	w := &watcher{
+		validator:   v,
+		entry:       entry,
+		handler:     handler,
+		mutex:       &sync.Mutex{},
+		files:       map[string]*watchedFile{},
+		collections: &sync.Map{},
+	}
+
+	if err := w.crawl(ctx, entry, false); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(v.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := w.poll(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// crawl validates the resource at a location and any linked resources that are not
+// already being watched.
+func (w *watcher) crawl(ctx context.Context, location string, changed bool) error {
+	visitor := func(resource crawler.Resource, info *crawler.ResourceInfo) error {
+		w.mutex.Lock()
+		defer w.mutex.Unlock()
+		w.track(info.Location, resource)
+		if err := w.report(info.Location, changed, w.validator.validate(w.collections, resource, info)); err != nil {
+			return &stopError{err}
+		}
+		if w.validator.noRecursion {
+			return crawler.ErrStopRecursion
+		}
+		return nil
+	}
+
+	errorHandler := func(err error) error {
+		stop := &stopError{}
+		if errors.Is(err, crawler.ErrStopRecursion) || errors.As(err, &stop) {
+			return err
+		}
+		w.mutex.Lock()
+		defer w.mutex.Unlock()
+		if handlerErr := w.handler(&WatchEvent{Changed: changed, Err: err}); handlerErr != nil {
+			return &stopError{handlerErr}
+		}
+		return nil
+	}
+
+	filter := func(loc string) bool {
+		if loc == location {
+			return true
+		}
+		w.mutex.Lock()
+		defer w.mutex.Unlock()
+		_, watched := w.files[loc]
+		return !watched
+	}
+
+	err := crawler.Crawl(location, visitor, &crawler.Options{
+		Queue:        crawler.NewMemoryQueue(ctx, w.validator.concurrency),
+		ErrorHandler: errorHandler,
+		Filter:       filter,
+	})
+	stop := &stopError{}
+	if errors.As(err, &stop) {
+		return stop.err
+	}
+	return err
+}
+
+// report calls the handler with the outcome of a validation.  The mutex must be held.
+func (w *watcher) report(location string, changed bool, err error) error {
+	if errors.Is(err, crawler.ErrStopRecursion) {
+		err = nil
+	}
+	return w.handler(&WatchEvent{Location: location, Changed: changed, Err: err})
+}
+
+// dependencyRels are the relations to resources that are validated again when
+// a linked resource changes.  Links to the root and parent are not followed so
+// that changing a catalog does not validate everything below it.
+var dependencyRels = []string{"child", "item", "collection"}
+
+// track records the current state of a resource.  The mutex must be held.
+func (w *watcher) track(location string, resource crawler.Resource) {
+	file := &watchedFile{}
+	if info, err := os.Stat(location); err == nil {
+		file.modTime = info.ModTime()
+		file.size = info.Size()
+	}
+
+	base, err := normurl.New(location)
+	if err == nil {
+		for _, link := range resource.Links() {
+			if !slices.Contains(dependencyRels, link["rel"]) {
+				continue
+			}
+			loc, err := base.Resolve(link["href"])
+			if err != nil || !loc.IsFilepath() {
+				continue
+			}
+			file.links = append(file.links, loc.String())
+		}
+	}
+
+	w.files[location] = file
+}
+
+// poll checks for modified files and validates the affected resources.
+func (w *watcher) poll(ctx context.Context) error {
+	w.mutex.Lock()
+	modified := []string{}
+	for location, file := range w.files {
+		info, err := os.Stat(location)
+		if err != nil {
+			if !file.missing {
+				modified = append(modified, location)
+			}
+			continue
+		}
+		if file.missing || !info.ModTime().Equal(file.modTime) || info.Size() != file.size {
+			modified = append(modified, location)
+		}
+	}
+	if len(modified) == 0 {
+		w.mutex.Unlock()
+		return nil
+	}
+
+	affected := map[string]bool{}
+	for _, location := range modified {
+		affected[location] = true
+	}
+	for location, file := range w.files {
+		for _, link := range file.links {
+			if slices.Contains(modified, link) {
+				affected[location] = true
+			}
+		}
+	}
+	w.mutex.Unlock()
+
+	resources := map[string]crawler.Resource{}
+	for location := range affected {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		resource, err := w.load(location)
+		if err != nil {
+			// keep watching so the resource is validated again if it is restored
+			w.mutex.Lock()
+			w.files[location] = &watchedFile{missing: true, links: w.files[location].links}
+			handlerErr := w.handler(&WatchEvent{Location: location, Changed: true, Err: err})
+			w.mutex.Unlock()
+			if handlerErr != nil {
+				return handlerErr
+			}
+			continue
+		}
+		resources[location] = resource
+	}
+
+	// validate catalogs and collections first so items can be checked against their collection
+	locations := slices.SortedFunc(maps.Keys(resources), func(a string, b string) int {
+		return cmp.Or(
+			cmp.Compare(itemOrder(resources[a]), itemOrder(resources[b])),
+			cmp.Compare(a, b),
+		)
+	})
+
+	newLinks := []string{}
+	for _, location := range locations {
+		resource := resources[location]
+		info := &crawler.ResourceInfo{Location: location, Entry: w.entry}
+
+		w.mutex.Lock()
+		w.track(location, resource)
+		err := w.report(location, true, w.validator.validate(w.collections, resource, info))
+		if !w.validator.noRecursion {
+			for _, link := range w.files[location].links {
+				if _, watched := w.files[link]; !watched && !slices.Contains(newLinks, link) {
+					newLinks = append(newLinks, link)
+				}
+			}
+		}
+		w.mutex.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, link := range newLinks {
+		if err := w.crawlLink(ctx, link); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// crawlLink crawls a newly linked resource if it has not been visited by an earlier crawl.
+func (w *watcher) crawlLink(ctx context.Context, location string) error {
+	w.mutex.Lock()
+	_, watched := w.files[location]
+	w.mutex.Unlock()
+	if watched {
+		return nil
+	}
+	if _, err := os.Stat(location); err != nil {
+		// dangling links are not reported when watching
+		return nil
+	}
+	return w.crawl(ctx, location, true)
+}
+
+func (w *watcher) load(location string) (crawler.Resource, error) {
+	data, err := os.ReadFile(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", location, err)
+	}
+	resource := crawler.Resource{}
+	if err := json.Unmarshal(data, &resource); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", location, err)
+	}
+	return resource, nil
+}
+
+func itemOrder(resource crawler.Resource) int {
+	if resource.Type() == crawler.Item {
+		return 1
+	}
+	return 0
+}
+
+// stopError wraps errors that should stop a crawl.
+type stopError struct {
+	err error
+}
+
+func (e *stopError) Error() string {
+	return e.err.Error()
+}
+
+func (e *stopError) Unwrap() error {
+	return e.err
+}
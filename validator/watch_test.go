@@ -0,0 +1,120 @@
+package validator_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/planetlabs/go-stac/validator"
+)
+
+func (s *Suite) copyCase(dir string, name string, target string) string {
+	data, err := os.ReadFile(filepath.Join("testdata", "cases", "v1.0.0", name))
+	s.Require().NoError(err)
+	return s.writeFile(dir, target, data)
+}
+
+// writeFile writes to a temporary file and renames it so the watcher never sees a
+// partially written file.
+func (s *Suite) writeFile(dir string, target string, data []byte) string {
+	tempFile, err := os.CreateTemp(dir, ".tmp-*")
+	s.Require().NoError(err)
+	_, err = tempFile.Write(data)
+	s.Require().NoError(err)
+	s.Require().NoError(tempFile.Close())
+
+	targetPath := filepath.Join(dir, target)
+	s.Require().NoError(os.Rename(tempFile.Name(), targetPath))
+	return targetPath
+}
+
+// copyCaseWithLinks copies a test case after adding links.
+func (s *Suite) copyCaseWithLinks(dir string, name string, target string, links ...map[string]any) string {
+	data, err := os.ReadFile(filepath.Join("testdata", "cases", "v1.0.0", name))
+	s.Require().NoError(err)
+	resource := map[string]any{}
+	s.Require().NoError(json.Unmarshal(data, &resource))
+	resource["links"] = links
+	data, err = json.Marshal(resource)
+	s.Require().NoError(err)
+	return s.writeFile(dir, target, data)
+}
+
+func (s *Suite) nextEvents(events chan *validator.WatchEvent, count int) map[string]*validator.WatchEvent {
+	received := map[string]*validator.WatchEvent{}
+	for range count {
+		select {
+		case event := <-events:
+			received[filepath.Base(event.Location)] = event
+		case <-time.After(5 * time.Second):
+			s.FailNow("timed out waiting for watch events")
+		}
+	}
+	return received
+}
+
+func (s *Suite) TestWatch() {
+	dir := s.T().TempDir()
+	catalogPath := s.copyCase(dir, "catalog-with-item.json", "catalog.json")
+	s.copyCaseWithLinks(dir, "item.json", "item.json",
+		map[string]any{"rel": "root", "href": "./catalog.json"},
+		map[string]any{"rel": "parent", "href": "./catalog.json"},
+	)
+
+	v := validator.New(&validator.Options{PollInterval: 10 * time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan *validator.WatchEvent, 10)
+	done := make(chan error)
+	go func() {
+		done <- v.Watch(ctx, catalogPath, func(event *validator.WatchEvent) error {
+			events <- event
+			return nil
+		})
+	}()
+
+	initial := s.nextEvents(events, 2)
+	s.Require().Len(initial, 2)
+	for name, event := range initial {
+		s.NoError(event.Err, name)
+		s.False(event.Changed, name)
+	}
+
+	// the item links to the catalog as its root and parent, but is not validated again
+	s.copyCaseWithLinks(dir, "catalog-with-item.json", "catalog.json",
+		map[string]any{"rel": "root", "href": "./catalog.json"},
+		map[string]any{"rel": "item", "href": "./item.json"},
+	)
+	changed := s.nextEvents(events, 1)
+	s.Require().Contains(changed, "catalog.json")
+	s.True(changed["catalog.json"].Changed)
+	s.NoError(changed["catalog.json"].Err)
+	select {
+	case event := <-events:
+		s.Failf("unexpected event", "resource %s was validated again", event.Location)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// the catalog links to the item, so both are validated again
+	s.copyCase(dir, "item-missing-id.json", "item.json")
+	changed = s.nextEvents(events, 2)
+	s.Require().Len(changed, 2)
+	s.True(changed["item.json"].Changed)
+	s.IsType(&validator.ValidationError{}, changed["item.json"].Err)
+	s.True(changed["catalog.json"].Changed)
+	s.NoError(changed["catalog.json"].Err)
+
+	cancel()
+	s.ErrorIs(<-done, context.Canceled)
+}
+
+func (s *Suite) TestWatchURL() {
+	v := validator.New()
+	err := v.Watch(context.Background(), "https://example.com/catalog.json", func(event *validator.WatchEvent) error {
+		return nil
+	})
+	s.Error(err)
+}